@@ -1,21 +1,35 @@
 package main
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	_ "embed"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,8 +54,22 @@ var exampleServerGerritJSON []byte
 var exampleServerDockerJSON []byte
 
 const (
-	defaultBaseURL = "http://localhost:8080"
-	configFileName = ".mcpx-cli-config.json"
+	defaultBaseURL         = "http://localhost:8080"
+	configFileName         = ".mcpx-cli-config.json"
+	settingsFileName       = ".mcpx-cli-settings.json"
+	stateFileName          = ".mcpx-cli-state.json"
+	defaultMaxResponseSize = 64 << 20 // 64MiB
+	minWatchInterval       = 5 * time.Second
+
+	// exitCodeNotFound is the exit status of commands reporting that a server does not exist
+	exitCodeNotFound = 4
+	// exitCodeUnhealthy and exitCodeUnreachable are the exit statuses of health --exit-code-only
+	exitCodeUnhealthy   = 1
+	exitCodeUnreachable = 3
+
+	// Log formats
+	LogFormatText = "text"
+	LogFormatJSON = "json"
 
 	// Authentication methods (matching backend)
 	AuthMethodGitHubOAuth = "github-oauth"
@@ -50,6 +78,9 @@ const (
 	AuthMethodDNS         = "dns"
 	AuthMethodHTTP        = "http"
 
+	// authMethodEnvVar presets the login --method default, e.g. github-oidc in CI
+	authMethodEnvVar = "MCPX_AUTH_METHOD"
+
 	// Registry Types - supported package registry types
 	RegistryTypeNPM    = "npm"
 	RegistryTypePyPI   = "pypi"
@@ -114,6 +145,28 @@ type Server struct {
 	Repository  Repository  `json:"repository"`
 	Version     string      `json:"version"`
 	Meta        *ServerMeta `json:"_meta,omitempty"`
+	// embeddedID is the server object's own id when the registry metadata names a different serverId.
+	// The metadata wins (see GetServerID); the embedded id is only kept to report the inconsistency.
+	embeddedID string
+}
+
+// noteEmbeddedID records id as conflicting when it is set and differs from the registry's serverId
+func (s *Server) noteEmbeddedID(id, metaID string) {
+	if id != "" && metaID != "" && id != metaID {
+		s.embeddedID = id
+	}
+}
+
+// warnIDMismatches reports, in verbose mode, servers whose embedded id disagrees with the serverId of the
+// registry metadata. Operations by ID use the metadata's serverId, so a registry bug here could otherwise
+// make a delete or update hit a different server than the one listed.
+func (c *MCPXClient) warnIDMismatches(servers ...Server) {
+	for _, server := range servers {
+		if server.embeddedID != "" {
+			c.logger.Debug("server ID mismatch: the registry metadata serverId wins over the embedded id",
+				"name", server.Name, "serverId", server.GetServerID(), "id", server.embeddedID)
+		}
+	}
 }
 
 type ServerMeta struct {
@@ -129,7 +182,7 @@ type RegistryExtensions struct {
 }
 
 func (s *Server) GetServerID() string {
-	if s.Meta != nil && s.Meta.Official != nil {
+	if s.Meta != nil && s.Meta.Official != nil && s.Meta.Official.ServerID != "" {
 		return s.Meta.Official.ServerID
 	}
 	if s.ID != "" {
@@ -154,54 +207,38 @@ func generateVersionID(serverName, version string) string {
 
 // GetServerIDFromWrapper extracts server ID from ServerWrapper
 func (w *ServerWrapper) GetServerID() string {
-	if w.RegistryMeta != nil {
-		if official, ok := w.RegistryMeta["io.modelcontextprotocol.registry/official"].(map[string]interface{}); ok {
-			if serverID, ok := official["serverId"].(string); ok && serverID != "" {
-				return serverID
-			}
-		}
+	if official := w.RegistryMeta.official(); official != nil && official.ServerID != "" {
+		return official.ServerID
 	}
 	return w.Server.GetServerID()
 }
 
 // GetVersionIDFromWrapper extracts version ID from ServerWrapper
 func (w *ServerWrapper) GetVersionID() string {
-	if w.RegistryMeta != nil {
-		if official, ok := w.RegistryMeta["io.modelcontextprotocol.registry/official"].(map[string]interface{}); ok {
-			if versionID, ok := official["versionId"].(string); ok && versionID != "" {
-				return versionID
-			}
-		}
+	if official := w.RegistryMeta.official(); official != nil && official.VersionID != "" {
+		return official.VersionID
 	}
 	return w.Server.GetVersionID()
 }
 
 // GetServerIDFromDetailWrapper extracts server ID from ServerDetailWrapper
 func (w *ServerDetailWrapper) GetServerID() string {
-	if w.RegistryMeta != nil {
-		if official, ok := w.RegistryMeta["io.modelcontextprotocol.registry/official"].(map[string]interface{}); ok {
-			if serverID, ok := official["serverId"].(string); ok && serverID != "" {
-				return serverID
-			}
-		}
+	if official := w.RegistryMeta.official(); official != nil && official.ServerID != "" {
+		return official.ServerID
 	}
 	return w.Server.GetServerID()
 }
 
 // GetVersionIDFromDetailWrapper extracts version ID from ServerDetailWrapper
 func (w *ServerDetailWrapper) GetVersionID() string {
-	if w.RegistryMeta != nil {
-		if official, ok := w.RegistryMeta["io.modelcontextprotocol.registry/official"].(map[string]interface{}); ok {
-			if versionID, ok := official["versionId"].(string); ok && versionID != "" {
-				return versionID
-			}
-		}
+	if official := w.RegistryMeta.official(); official != nil && official.VersionID != "" {
+		return official.VersionID
 	}
 	return w.Server.GetVersionID()
 }
 
 func (s *Server) GetVersionID() string {
-	if s.Meta != nil && s.Meta.Official != nil {
+	if s.Meta != nil && s.Meta.Official != nil && s.Meta.Official.VersionID != "" {
 		return s.Meta.Official.VersionID
 	}
 	// Generate a unique version ID based on server name and version
@@ -214,6 +251,25 @@ type Metadata struct {
 	Total      int    `json:"total,omitempty"`
 }
 
+// UnmarshalJSON also accepts the snake_case next_cursor of older registries, so the cursor survives into the
+// camelCase metadata that --json emits whichever listing format the registry used
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		NextCursor      string `json:"nextCursor"`
+		NextCursorSnake string `json:"next_cursor"`
+		Count           int    `json:"count"`
+		Total           int    `json:"total"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Metadata{NextCursor: raw.NextCursor, Count: raw.Count, Total: raw.Total}
+	if m.NextCursor == "" {
+		m.NextCursor = raw.NextCursorSnake
+	}
+	return nil
+}
+
 type ServersResponse struct {
 	Servers  []ServerWrapper `json:"servers"`
 	Metadata Metadata        `json:"metadata,omitempty"`
@@ -226,13 +282,73 @@ type DetailedServersResponse struct {
 
 // New wrapper types for the API format
 type ServerWrapper struct {
-	Server       Server                 `json:"server"`
-	RegistryMeta map[string]interface{} `json:"_meta,omitempty"`
+	Server       Server        `json:"server"`
+	RegistryMeta *RegistryMeta `json:"_meta,omitempty"`
 }
 
 type ServerDetailWrapper struct {
-	Server       ServerDetail           `json:"server"`
-	RegistryMeta map[string]interface{} `json:"_meta,omitempty"`
+	Server       ServerDetail  `json:"server"`
+	RegistryMeta *RegistryMeta `json:"_meta,omitempty"`
+}
+
+// officialMetaKey is the _meta key of the extension the registry manages for every server version
+const officialMetaKey = "io.modelcontextprotocol.registry/official"
+
+// RegistryMeta is the _meta object of a wrapper. The registry-managed fields are typed: the official
+// extension, or the flat id, published_at, updated_at and is_latest keys that older registries sent instead.
+// Every other key is kept unchanged in Extra.
+type RegistryMeta struct {
+	Official *RegistryExtensions
+	Extra    map[string]json.RawMessage
+	// legacy is set when Official was derived from the flat keys, which stay in Extra
+	legacy bool
+}
+
+func (m *RegistryMeta) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = RegistryMeta{Extra: raw}
+	if official, ok := raw[officialMetaKey]; ok {
+		if err := json.Unmarshal(official, &m.Official); err != nil {
+			return fmt.Errorf("%s: %w", officialMetaKey, err)
+		}
+		delete(m.Extra, officialMetaKey)
+		return nil
+	}
+
+	// Flat keys of older registries; a value of the wrong type is ignored rather than failing the listing
+	var flat RegistryExtensions
+	found := false
+	for key, target := range map[string]any{"id": &flat.ServerID, "published_at": &flat.PublishedAt, "updated_at": &flat.UpdatedAt, "is_latest": &flat.IsLatest} {
+		if value, ok := raw[key]; ok && json.Unmarshal(value, target) == nil {
+			found = true
+		}
+	}
+	if found {
+		m.Official, m.legacy = &flat, true
+	}
+	return nil
+}
+
+func (m RegistryMeta) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any, len(m.Extra)+1)
+	for key, value := range m.Extra {
+		out[key] = value
+	}
+	if m.Official != nil && !m.legacy {
+		out[officialMetaKey] = m.Official
+	}
+	return json.Marshal(out)
+}
+
+// official returns the registry-managed fields of the metadata, or nil when there are none
+func (m *RegistryMeta) official() *RegistryExtensions {
+	if m == nil {
+		return nil
+	}
+	return m.Official
 }
 
 // Legacy response types for backward compatibility
@@ -242,8 +358,17 @@ type LegacyServersResponse struct {
 }
 
 type LegacyDetailedServersResponse struct {
-	Servers  []ServerDetail `json:"servers"`
-	Metadata Metadata       `json:"metadata,omitempty"`
+	Servers  []ServerDetail      `json:"servers"`
+	Metadata Metadata            `json:"metadata,omitempty"`
+	Details  *DetailFetchSummary `json:"details,omitempty"`
+}
+
+// DetailFetchSummary reports how many per-server detail requests of a detailed listing succeeded.
+// Servers whose details could not be fetched are listed without packages and remotes.
+type DetailFetchSummary struct {
+	DetailsFetched int      `json:"detailsFetched"`
+	DetailsFailed  int      `json:"detailsFailed"`
+	FailedIDs      []string `json:"failedIds,omitempty"`
 }
 
 type Input struct {
@@ -324,9 +449,227 @@ type PublishRequest struct {
 	XPublisher map[string]interface{} `json:"x-publisher,omitempty"`
 }
 
+// Logger writes informational, verbose and error messages, either as plain text or as single-line JSON records.
+// Data output (listings, JSON documents) is not logged and keeps going to stdout.
+type Logger struct {
+	mu      sync.Mutex
+	out     io.Writer
+	format  string
+	verbose bool
+}
+
+// logRecord is a single JSON log line
+type logRecord struct {
+	Level     string                 `json:"level"`
+	Msg       string                 `json:"msg"`
+	Timestamp string                 `json:"timestamp"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+func NewLogger(out io.Writer, format string, verbose bool) *Logger {
+	if format == "" {
+		format = LogFormatText
+	}
+	return &Logger{out: out, format: format, verbose: verbose}
+}
+
+// Debug logs a message only in verbose mode
+func (l *Logger) Debug(msg string, fields ...interface{}) {
+	if l.verbose {
+		l.log("debug", msg, fields)
+	}
+}
+
+func (l *Logger) Info(msg string, fields ...interface{}) {
+	l.log("info", msg, fields)
+}
+
+func (l *Logger) Warn(msg string, fields ...interface{}) {
+	l.log("warn", msg, fields)
+}
+
+func (l *Logger) Error(msg string, fields ...interface{}) {
+	l.log("error", msg, fields)
+}
+
+// log writes a record; fields are alternating key/value pairs
+func (l *Logger) log(level, msg string, fields []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.format == LogFormatJSON {
+		record := logRecord{
+			Level:     level,
+			Msg:       msg,
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		}
+		if len(fields) > 0 {
+			record.Fields = make(map[string]interface{}, len(fields)/2)
+			for i := 0; i < len(fields); i += 2 {
+				key := fmt.Sprint(fields[i])
+				var value interface{} = "(missing)"
+				if i+1 < len(fields) {
+					value = fields[i+1]
+				}
+				if err, ok := value.(error); ok {
+					value = err.Error()
+				}
+				record.Fields[key] = value
+			}
+		}
+		line, err := json.Marshal(record)
+		if err != nil {
+			line = []byte(fmt.Sprintf(`{"level":"error","msg":"failed to encode log record: %v"}`, err))
+		}
+		_, _ = fmt.Fprintln(l.out, string(line))
+		return
+	}
+
+	var b strings.Builder
+	switch level {
+	case "debug":
+		b.WriteString("[verbose] ")
+	case "warn":
+		b.WriteString("Warning: ")
+	case "error":
+		b.WriteString("Error: ")
+	}
+	b.WriteString(msg)
+	for i := 0; i < len(fields); i += 2 {
+		if i+1 < len(fields) {
+			fmt.Fprintf(&b, " %v=%v", fields[i], fields[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", fields[i])
+		}
+	}
+	_, _ = fmt.Fprintln(l.out, b.String())
+}
+
+// logWriter adapts the standard library logger (used for fatal errors) to a Logger
+type logWriter struct {
+	logger *Logger
+}
+
+func (w logWriter) Write(p []byte) (int, error) {
+	w.logger.Error(strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
+
+// progressBarWidth is the number of cells in a progress bar drawn on a terminal
+const progressBarWidth = 30
+
+// Progress reports how far a bulk operation has come on stderr, so it never mixes with data on stdout.
+// On a terminal it redraws a single bar line; otherwise it prints a line every 10% (or every 100 items
+// when the total is unknown) and once more at the end. A nil *Progress reports nothing.
+type Progress struct {
+	out      io.Writer
+	label    string
+	total    int
+	done     int
+	tty      bool
+	lastLine int
+}
+
+// newProgress starts a progress report for total items, or an unknown number when total is 0. It returns
+// nil, which reports nothing, when the client is quiet.
+func (c *MCPXClient) newProgress(label string, total int) *Progress {
+	if c.quiet {
+		return nil
+	}
+	out := c.progressOut
+	if out == nil {
+		out = os.Stderr
+	}
+	file, ok := out.(*os.File)
+	return &Progress{out: out, label: label, total: total, tty: ok && isTerminal(file)}
+}
+
+// Add records n finished items and updates the report
+func (p *Progress) Add(n int) {
+	if p == nil {
+		return
+	}
+	p.done += n
+	if p.tty {
+		_, _ = fmt.Fprintf(p.out, "\r\033[K%s", p.line())
+		return
+	}
+	if p.lineDue() {
+		p.lastLine = p.done
+		_, _ = fmt.Fprintln(p.out, p.line())
+	}
+}
+
+// Clear erases the bar from the terminal before other output is printed; Add draws it again
+func (p *Progress) Clear() {
+	if p != nil && p.tty && p.done > 0 {
+		_, _ = fmt.Fprint(p.out, "\r\033[K")
+	}
+}
+
+// Done ends the report with the final count
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	if p.tty {
+		_, _ = fmt.Fprintf(p.out, "\r\033[K%s\n", p.line())
+		return
+	}
+	if p.lastLine != p.done {
+		_, _ = fmt.Fprintln(p.out, p.line())
+	}
+}
+
+// lineDue reports whether a non-terminal report prints a line after the latest Add
+func (p *Progress) lineDue() bool {
+	if p.total <= 0 {
+		return p.done%100 == 0
+	}
+	return p.done*10/p.total > p.lastLine*10/p.total
+}
+
+func (p *Progress) line() string {
+	if p.total <= 0 {
+		return fmt.Sprintf("%s: %d done", p.label, p.done)
+	}
+	percent := p.done * 100 / p.total
+	if !p.tty {
+		return fmt.Sprintf("%s: %d/%d (%d%%)", p.label, p.done, p.total, percent)
+	}
+	filled := min(p.done*progressBarWidth/p.total, progressBarWidth)
+	return fmt.Sprintf("%s [%s%s] %d/%d %d%%", p.label, strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled), p.done, p.total, percent)
+}
+
 type MCPXClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL         string
+	httpClient      *http.Client
+	maxResponseSize int64
+	logger          *Logger
+	// cacheDir stores ETag-validated responses; caching is disabled when empty
+	cacheDir string
+	// retries is how many times a request failing with a transient network error is retried
+	retries int
+	// retryBackoff is the delay before the first retry; it doubles for every further attempt
+	retryBackoff time.Duration
+	// attemptTimeout caps each individual attempt, including reading the body; zero means no cap
+	attemptTimeout time.Duration
+	// deadline caps the whole operation across all attempts and backoff; zero means no deadline
+	deadline time.Time
+	// quiet disables progress reports of bulk operations
+	quiet bool
+	// sleep waits between retries; tests replace it to avoid real delays
+	sleep func(time.Duration)
+	// progressOut receives progress reports; nil means stderr
+	progressOut io.Writer
+	// authURL is the base of the token endpoints when auth is served separately; empty means <baseURL>/v0/auth
+	authURL string
+	// noAuth sends every request without a token, overriding both --token and the stored login
+	noAuth bool
+	// sinks receive the JSON document of a command in addition to its normal output (--tee)
+	sinks []outputSink
+	// sinksWritten records that a command handed its document to the sinks
+	sinksWritten bool
 }
 
 func NewMCPXClient(baseURL string) *MCPXClient {
@@ -335,9 +678,187 @@ func NewMCPXClient(baseURL string) *MCPXClient {
 	}
 
 	return &MCPXClient{
-		baseURL:    strings.TrimSuffix(baseURL, "/"),
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		httpClient:      &http.Client{Timeout: 30 * time.Second, Transport: newHTTPTransport(), CheckRedirect: checkRedirect},
+		maxResponseSize: defaultMaxResponseSize,
+		logger:          NewLogger(os.Stderr, LogFormatText, false),
+		cacheDir:        defaultCacheDir(),
+		retryBackoff:    defaultRetryBackoff,
+		sleep:           time.Sleep,
+	}
+}
+
+// normalizeBaseURL turns user input for --base-url or --registry into a usable base URL. A missing scheme
+// defaults to http:// for loopback hosts and https:// for everything else, and the trailing slash is dropped.
+// Input that does not parse as an http(s) URL with a host is rejected rather than failing on the first request.
+func normalizeBaseURL(raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", fmt.Errorf("base URL is empty")
+	}
+
+	if !strings.Contains(trimmed, "://") {
+		host := trimmed
+		if i := strings.IndexAny(host, "/?#"); i >= 0 {
+			host = host[:i]
+		}
+		scheme := "https://"
+		if hostname := (&url.URL{Host: host}).Hostname(); hostname == "localhost" || net.ParseIP(hostname).IsLoopback() {
+			scheme = "http://"
+		}
+		trimmed = scheme + trimmed
+	}
+
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", raw, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid base URL %q: scheme must be http or https", raw)
+	}
+	if parsed.Hostname() == "" {
+		return "", fmt.Errorf("invalid base URL %q: missing host", raw)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("invalid base URL %q: must not contain a query or fragment", raw)
+	}
+	return strings.TrimSuffix(parsed.String(), "/"), nil
+}
+
+// Connection pool defaults. Commands such as servers --all or --detailed send many sequential requests to
+// one host, so idle connections are kept for reuse instead of dialing (and TLS handshaking) every time.
+const (
+	defaultMaxIdleConnsPerHost = 16
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// newHTTPTransport returns the transport used by the client: HTTP/2 when the server supports it and keep-alive
+// connections pooled per host
+func newHTTPTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = defaultIdleConnTimeout
+	transport.DisableKeepAlives = false
+	return transport
+}
+
+// disableCompression stops the transport from sending Accept-Encoding: gzip and transparently
+// decompressing responses, so bodies are read exactly as the server (or a proxy) sent them
+func (c *MCPXClient) disableCompression() {
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+		transport.DisableCompression = true
+	}
+}
+
+// TLS versions accepted by --tls-min-version
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// setTLSMinVersion makes the transport refuse registries that cannot negotiate at least the given TLS version
+// ("1.2" or "1.3"). Without it Go's default minimum applies.
+func (c *MCPXClient) setTLSMinVersion(version string) error {
+	minVersion, ok := tlsVersions[version]
+	if !ok {
+		return fmt.Errorf("unsupported TLS version %q (expected 1.2 or 1.3)", version)
+	}
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.MinVersion = minVersion
+	return nil
+}
+
+// setClientCertificate loads a PEM certificate and private key and presents them to registries that ask
+// for a client certificate (mutual TLS). Bearer tokens are still sent as usual.
+func (c *MCPXClient) setClientCertificate(certFile, keyFile string) error {
+	if certFile == "" || keyFile == "" {
+		return fmt.Errorf("--client-cert and --client-key must be given together")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not match") {
+			return fmt.Errorf("the key in %s does not belong to the certificate in %s", keyFile, certFile)
+		}
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.Certificates = []tls.Certificate{cert}
+	return nil
+}
+
+// tlsConfig returns the TLS settings of the client's transport, creating them on first use
+func (c *MCPXClient) tlsConfig() (*tls.Config, error) {
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("the HTTP transport does not support TLS settings")
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	return transport.TLSClientConfig, nil
+}
+
+// parseByteSize parses a size such as "1048576", "512KB", "64MiB" or "1GB" into bytes
+func parseByteSize(value string) (int64, error) {
+	s := strings.TrimSpace(value)
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"KiB", 1 << 10},
+		{"MiB", 1 << 20},
+		{"GiB", 1 << 30},
+		{"KB", 1000},
+		{"MB", 1000 * 1000},
+		{"GB", 1000 * 1000 * 1000},
+		{"B", 1},
+	}
+
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(strings.ToUpper(s), strings.ToUpper(unit.suffix)) {
+			s = strings.TrimSpace(s[:len(s)-len(unit.suffix)])
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 1048576, 512KB or 64MiB)", value)
+	}
+
+	return n * multiplier, nil
+}
+
+// readResponseBody reads the whole response body, refusing to buffer more than maxResponseSize bytes
+func (c *MCPXClient) readResponseBody(resp *http.Response) ([]byte, error) {
+	reader := io.Reader(resp.Body)
+	if c.maxResponseSize > 0 {
+		reader = io.LimitReader(resp.Body, c.maxResponseSize+1)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		// A body cut off mid-stream is a network problem, not a registry bug
+		if classifyNetworkError(err) == NetworkErrorTruncated && resp.Request != nil {
+			return nil, newRequestError(resp.Request.URL.Host, err)
+		}
+		return nil, err
+	}
+
+	if c.maxResponseSize > 0 && int64(len(body)) > c.maxResponseSize {
+		return nil, fmt.Errorf("response too large: exceeds %d bytes (raise the limit with --max-response-size)", c.maxResponseSize)
 	}
+
+	return body, nil
 }
 
 // Authentication helper methods
@@ -414,7 +935,17 @@ func (c *MCPXClient) clearAuthConfig() error {
 }
 
 func (c *MCPXClient) makeRequest(method, endpoint string, body []byte, token string) (*http.Response, error) {
+	return c.makeRequestWithHeaders(method, endpoint, body, token, nil)
+}
+
+// makeRequestWithHeaders is makeRequest with additional request headers
+func (c *MCPXClient) makeRequestWithHeaders(method, endpoint string, body []byte, token string, headers map[string]string) (*http.Response, error) {
+	// Absolute endpoints point at another service, such as a separate token issuer
+	external := strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://")
 	url := c.baseURL + endpoint
+	if external {
+		url = endpoint
+	}
 
 	var bodyReader io.Reader
 	if body != nil {
@@ -430,9 +961,15 @@ func (c *MCPXClient) makeRequest(method, endpoint string, body []byte, token str
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	// Use provided token or auto-load from config
+	// Use provided token or auto-load from config; the stored registry token is not sent to other services.
+	// --no-auth wins over both, so a request shows exactly what an anonymous caller gets.
 	authToken := token
-	if authToken == "" {
+	if c.noAuth {
+		if authToken != "" {
+			c.logger.Debug("not sending the given token (--no-auth)", "method", method, "url", url)
+		}
+		authToken = ""
+	} else if authToken == "" && !external {
 		config, err := c.loadAuthConfig()
 		if err != nil {
 			return nil, fmt.Errorf("failed to load auth config: %w", err)
@@ -445,565 +982,5090 @@ func (c *MCPXClient) makeRequest(method, endpoint string, body []byte, token str
 	}
 
 	req.Header.Set("User-Agent", "mcpx-cli/1.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
-	return c.httpClient.Do(req)
-}
+	// Conditional requests: replay the stored ETag for cacheable endpoints
+	var cached *cacheEntry
+	cacheKey := ""
+	if c.isCacheable(method, endpoint) {
+		cacheKey = etagCacheKey(url, authToken)
+		if cached = c.loadCacheEntry(cacheKey); cached != nil {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
 
-// Authentication commands
-func (c *MCPXClient) login(authMethod string) error {
-	switch authMethod {
-	case AuthMethodGitHubOAuth:
-		return c.loginGitHubOAuth()
-	case AuthMethodGitHubOIDC:
-		return c.loginGitHubOIDC()
-	case AuthMethodAnonymous:
-		return c.loginAnonymous()
-	default:
-		return fmt.Errorf("unsupported authentication method: %s", authMethod)
+	start := time.Now()
+	resp, err := c.doWithRetries(req, body)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("received response", "method", method, "url", url, "status", resp.StatusCode)
+	if c.logger.verbose {
+		resp.Body = &responseSummaryBody{ReadCloser: resp.Body, logger: c.logger, method: method, path: req.URL.EscapedPath(), status: resp.StatusCode, start: start}
+	}
+
+	if cacheKey != "" {
+		return c.applyETagCache(resp, cacheKey, cached)
 	}
+
+	return resp, nil
 }
 
-func (c *MCPXClient) loginGitHubOAuth() error {
-	// Implement GitHub OAuth flow
-	fmt.Println("GitHub OAuth authentication not yet implemented")
-	return nil
+// responseSummaryBody counts the bytes read from a response body and, once the body is closed, logs a
+// one-line summary of the request in verbose mode. The elapsed time runs from sending the request (including
+// retries) until the body is closed, so it covers reading the whole response.
+type responseSummaryBody struct {
+	io.ReadCloser
+	logger *Logger
+	method string
+	path   string
+	status int
+	start  time.Time
+	bytes  int64
+	logged bool
 }
 
-func (c *MCPXClient) loginGitHubOIDC() error {
-	// Implement GitHub OIDC flow
-	fmt.Println("GitHub OIDC authentication not yet implemented")
-	return nil
+func (b *responseSummaryBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	return n, err
 }
 
-func (c *MCPXClient) loginAnonymous() error {
-	resp, err := c.makeRequest("POST", "/v0/auth/none", nil, "")
-	if err != nil {
-		return fmt.Errorf("failed to authenticate: %w", err)
+func (b *responseSummaryBody) Close() error {
+	err := b.ReadCloser.Close()
+	if !b.logged {
+		b.logged = true
+		b.logger.Debug("request summary", "method", b.method, "path", b.path, "status", b.status, "bytes", b.bytes, "elapsed_ms", time.Since(b.start).Milliseconds())
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		// Read the response body for error details
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("authentication failed with status: %d, response: %s", resp.StatusCode, string(bodyBytes))
-	}
+// doWithRetries sends req, retrying transient network errors up to c.retries times with exponential backoff.
+// Each attempt is bounded by c.attemptTimeout and all attempts together by c.deadline.
+// Errors are returned as *RequestError so callers get an actionable message.
+func (c *MCPXClient) doWithRetries(req *http.Request, body []byte) (*http.Response, error) {
+	delay := c.retryBackoff
+	rateLimited := 0
+	for attempt := 0; ; {
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
 
-	// Read the response body and log it for debugging
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
+		attemptReq, cancel := c.attemptRequest(req)
+		c.logger.Debug("sending request", "method", req.Method, "url", req.URL.String(), "attempt", attempt+rateLimited+1)
+		resp, err := c.httpClient.Do(attemptReq)
+		if err == nil && c.retries > 0 {
+			// Read the body within the attempt, so a body cut off mid-stream is retried like a failed request
+			// instead of surfacing later as a confusing JSON error
+			err = bufferResponseBody(resp, c.maxResponseSize)
+		}
+		if err == nil {
+			if retryableStatus(req.Method, resp.StatusCode) && attempt < c.retries && !c.pastDeadline(delay) {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+				cancel()
+				c.logger.Warn("request failed, retrying", "status", resp.StatusCode, "attempt", attempt+1, "delay", delay.String())
+				c.logger.Debug("retrying request", "url", req.URL.String(), "reason", resp.Status, "attempt", attempt+rateLimited+1, "delay", delay.String())
+				c.sleep(delay)
+				delay *= 2
+				attempt++
+				continue
+			}
+			if wait, ok := c.rateLimitWait(resp, rateLimited, delay); ok {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+				cancel()
+				c.logger.Warn(fmt.Sprintf("rate limited, waiting %ds", (wait+time.Second-1)/time.Second))
+				c.logger.Debug("retrying request", "url", req.URL.String(), "reason", resp.Status, "attempt", attempt+rateLimited+1, "delay", wait.String())
+				c.sleep(wait)
+				rateLimited++
+				continue
+			}
+			if attempts := attempt + rateLimited + 1; attempts > 1 {
+				outcome := "succeeded"
+				if resp.StatusCode == http.StatusTooManyRequests || retryableStatus(req.Method, resp.StatusCode) {
+					outcome = "gave up"
+				}
+				c.logger.Debug(fmt.Sprintf("request %s after %d attempts", outcome, attempts), "url", req.URL.String(), "status", resp.StatusCode)
+			}
+			// The attempt's context must outlive this call, since the caller still reads the body
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		cancel()
 
-	if len(bodyBytes) == 0 {
-		return fmt.Errorf("server returned empty response body")
+		var loopErr *RedirectLoopError
+		if errors.As(err, &loopErr) {
+			return nil, loopErr
+		}
+		reqErr := newRequestError(req.URL.Host, err)
+		if !retryableError(req.Method, reqErr) || attempt >= c.retries {
+			if attempts := attempt + rateLimited + 1; attempts > 1 {
+				c.logger.Debug(fmt.Sprintf("request gave up after %d attempts", attempts), "url", req.URL.String(), "err", reqErr.Error())
+			}
+			return nil, reqErr
+		}
+		if c.pastDeadline(delay) {
+			c.logger.Debug(fmt.Sprintf("request gave up after %d attempts", attempt+rateLimited+1), "url", req.URL.String(), "reason", "deadline")
+			return nil, fmt.Errorf("deadline exceeded after %d attempt(s): %w", attempt+1, reqErr)
+		}
+		c.logger.Warn("request failed, retrying", "err", reqErr.Error(), "attempt", attempt+1, "delay", delay.String())
+		c.sleep(delay)
+		delay *= 2
+		attempt++
 	}
+}
 
-	var tokenResp TokenResponse
-	if err := json.Unmarshal(bodyBytes, &tokenResp); err != nil {
-		return fmt.Errorf("failed to decode token response: %w, response body: %s", err, string(bodyBytes))
-	}
+// pastDeadline reports whether waiting delay before another attempt would run past --deadline
+func (c *MCPXClient) pastDeadline(delay time.Duration) bool {
+	return !c.deadline.IsZero() && time.Now().Add(delay).After(c.deadline)
+}
 
-	// Use provided expiration or default to 1 hour from now
-	expiresAt := tokenResp.ExpiresAt
-	if expiresAt == 0 {
-		expiresAt = time.Now().Add(time.Hour).Unix()
+// Retry policy. With --retries, idempotent methods are retried on transient network errors and on the
+// transient statuses below. PUT counts as idempotent because an update replaces the whole server entry, so
+// sending it twice leaves the same state. POST (publish, login) and PATCH are not: they are only retried when
+// the request never reached the registry, i.e. it failed while resolving or dialing. A 429 answer is retried
+// for every method (see rateLimitWait), since the registry rejected the request without processing it.
+
+// isIdempotentMethod reports whether sending a request with method twice has the same effect as once
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
 	}
+	return false
+}
 
-	config := AuthConfig{
-		Method:    AuthMethodAnonymous,
-		Token:     tokenResp.RegistryToken,
-		ExpiresAt: expiresAt,
+// retryableStatus reports whether a response with status is worth retrying for method
+func retryableStatus(method string, status int) bool {
+	if !isIdempotentMethod(method) {
+		return false
 	}
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
 
-	if err := c.saveAuthConfig(config); err != nil {
-		return fmt.Errorf("failed to save auth config: %w", err)
+// retryableError reports whether a failed round trip may be retried for method
+func retryableError(method string, err *RequestError) bool {
+	if !err.Retryable() {
+		return false
 	}
-
-	fmt.Println("Successfully authenticated as anonymous user")
-	return nil
+	return isIdempotentMethod(method) || requestNotSent(err.Err)
 }
 
-func (c *MCPXClient) logout() error {
-	if err := c.clearAuthConfig(); err != nil {
-		return fmt.Errorf("failed to clear authentication: %w", err)
+// requestNotSent reports whether err happened before any of the request was written: a failed DNS lookup or
+// a dial that was refused or timed out. Errors later on may mean the registry already acted on the request.
+func requestNotSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
 	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
 
-	fmt.Println("Successfully logged out")
+// bufferResponseBody replaces the body of resp with an in-memory copy. At most limit+1 bytes are read, enough
+// for readResponseBody to still report a response that is too large.
+func bufferResponseBody(resp *http.Response, limit int64) error {
+	reader := io.Reader(resp.Body)
+	if limit > 0 {
+		reader = io.LimitReader(resp.Body, limit+1)
+	}
+	data, err := io.ReadAll(reader)
+	_ = resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
 	return nil
 }
 
-func (c *MCPXClient) Health() error {
-	fmt.Println("=== Health Check ===")
-
+// Rate limit handling: a 429 response is retried after the delay the registry asks for in Retry-After,
+// independently of --retries, as long as the wait is reasonable
+const (
+	maxRateLimitRetries = 3
+	maxRetryAfter       = 2 * time.Minute
+)
+
+// rateLimitWait reports whether resp is a 429 that should be retried and how long to wait first. Without a
+// usable Retry-After header the current backoff delay is used. A 429 is returned to the caller once
+// maxRateLimitRetries is reached, or when the wait exceeds maxRetryAfter or would pass the deadline.
+func (c *MCPXClient) rateLimitWait(resp *http.Response, rateLimited int, backoff time.Duration) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests || rateLimited >= maxRateLimitRetries {
+		return 0, false
+	}
+	wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		wait = backoff
+	}
+	if wait > maxRetryAfter {
+		c.logger.Warn(fmt.Sprintf("rate limited, and the requested wait of %s is too long to retry", wait.Round(time.Second)))
+		return 0, false
+	}
+	if !c.deadline.IsZero() && time.Now().Add(wait).After(c.deadline) {
+		return 0, false
+	}
+	return wait, true
+}
+
+// parseRetryAfter parses a Retry-After header in either the delta-seconds ("120") or the HTTP-date
+// ("Wed, 21 Oct 2015 07:28:00 GMT") form. A date in the past means no wait.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
+
+// attemptRequest returns req bound to a context that expires after c.attemptTimeout or at c.deadline,
+// whichever comes first. The returned cancel func must be called once the attempt is finished.
+func (c *MCPXClient) attemptRequest(req *http.Request) (*http.Request, context.CancelFunc) {
+	deadline := c.deadline
+	if c.attemptTimeout > 0 {
+		if attemptDeadline := time.Now().Add(c.attemptTimeout); deadline.IsZero() || attemptDeadline.Before(deadline) {
+			deadline = attemptDeadline
+		}
+	}
+	if deadline.IsZero() {
+		return req, func() {}
+	}
+	ctx, cancel := context.WithDeadline(req.Context(), deadline)
+	return req.WithContext(ctx), cancel
+}
+
+// cancelOnClose releases a request context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+const defaultRetryBackoff = 500 * time.Millisecond
+
+// Network error classes distinguished by classifyNetworkError
+const (
+	NetworkErrorUnknown = iota
+	NetworkErrorDNS
+	NetworkErrorRefused
+	NetworkErrorTimeout
+	NetworkErrorTruncated
+	NetworkErrorTLSVersion
+)
+
+// classifyNetworkError tells DNS resolution failures, refused connections and timeouts apart
+func classifyNetworkError(err error) int {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return NetworkErrorTimeout
+		}
+		return NetworkErrorDNS
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return NetworkErrorRefused
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return NetworkErrorTimeout
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return NetworkErrorTruncated
+	}
+	// crypto/tls has no typed error for a version mismatch; both the local check ("server selected
+	// unsupported protocol version") and the server's alert ("protocol version not supported") say so
+	if strings.Contains(err.Error(), "tls: ") && strings.Contains(err.Error(), "protocol version") {
+		return NetworkErrorTLSVersion
+	}
+	return NetworkErrorUnknown
+}
+
+// RequestError is a failed HTTP round trip, classified by cause
+type RequestError struct {
+	Kind int
+	Host string
+	Err  error
+}
+
+func newRequestError(host string, err error) *RequestError {
+	return &RequestError{Kind: classifyNetworkError(err), Host: host, Err: err}
+}
+
+func (e *RequestError) Error() string {
+	switch e.Kind {
+	case NetworkErrorDNS:
+		return fmt.Sprintf("could not resolve host %s — check --base-url", hostWithoutPort(e.Host))
+	case NetworkErrorRefused:
+		return fmt.Sprintf("connection to %s refused — is the registry running? check --base-url", e.Host)
+	case NetworkErrorTimeout:
+		return fmt.Sprintf("request to %s timed out — the registry may be overloaded or unreachable", e.Host)
+	case NetworkErrorTruncated:
+		return fmt.Sprintf("connection to %s was closed before the whole response arrived — retry, e.g. with --retries", e.Host)
+	case NetworkErrorTLSVersion:
+		return fmt.Sprintf("TLS handshake with %s failed: the registry does not support a TLS version allowed by the CLI (see --tls-min-version): %v", e.Host, e.Err)
+	default:
+		return fmt.Sprintf("request to %s failed: %v", e.Host, e.Err)
+	}
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the request may succeed when sent again; unresolvable hosts never will
+func (e *RequestError) Retryable() bool {
+	return e.Kind == NetworkErrorRefused || e.Kind == NetworkErrorTimeout || e.Kind == NetworkErrorTruncated
+}
+
+// maxRedirects is how many redirects are followed before giving up, the same limit as Go's default policy
+const maxRedirects = 10
+
+// RedirectLoopError reports a request that kept being redirected, usually because --base-url is wrong
+type RedirectLoopError struct {
+	// Chain holds every URL visited, starting with the original request
+	Chain []string
+}
+
+func (e *RedirectLoopError) Error() string {
+	msg := fmt.Sprintf("redirect loop after %d redirect(s): %s — check --base-url", len(e.Chain)-1, strings.Join(e.Chain, " → "))
+	if first, err := url.Parse(e.Chain[0]); err == nil && first.Scheme == "http" {
+		for _, hop := range e.Chain[1:] {
+			if next, err := url.Parse(hop); err == nil && next.Scheme == "https" {
+				msg += fmt.Sprintf(" (the registry redirects to https; try --base-url https://%s)", next.Host)
+				break
+			}
+		}
+	}
+	return msg
+}
+
+// checkRedirect stops following redirects as soon as a URL repeats, or after maxRedirects
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	repeated := false
+	for _, prev := range via {
+		repeated = repeated || prev.URL.String() == req.URL.String()
+	}
+	if !repeated && len(via) < maxRedirects {
+		return nil
+	}
+
+	chain := make([]string, 0, len(via)+1)
+	for _, prev := range via {
+		chain = append(chain, prev.URL.String())
+	}
+	return &RedirectLoopError{Chain: append(chain, req.URL.String())}
+}
+
+func hostWithoutPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// cacheEntry is a response body stored on disk together with its ETag
+type cacheEntry struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// defaultCacheDir returns the directory used for cached responses, or "" if none is available
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mcpx-cli", "etags")
+}
+
+// isCacheable reports whether responses of a request may be cached with ETags
+func (c *MCPXClient) isCacheable(method, endpoint string) bool {
+	return c.cacheDir != "" && method == "GET" && strings.HasPrefix(endpoint, "/v0/servers")
+}
+
+// etagCacheKey derives the cache file name for a URL; the token is included so users never share entries
+func etagCacheKey(url, token string) string {
+	hash := sha256.Sum256([]byte(url + "\n" + token))
+	return fmt.Sprintf("%x.json", hash)
+}
+
+func (c *MCPXClient) loadCacheEntry(key string) *cacheEntry {
+	data, err := os.ReadFile(filepath.Join(c.cacheDir, key))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.ETag == "" {
+		return nil
+	}
+	return &entry
+}
+
+func (c *MCPXClient) saveCacheEntry(key string, entry cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.cacheDir, 0700); err != nil {
+		c.logger.Debug("failed to create cache directory", "dir", c.cacheDir, "err", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(c.cacheDir, key), data, 0600); err != nil {
+		c.logger.Debug("failed to write cache entry", "err", err)
+	}
+}
+
+// applyETagCache serves the cached body on 304 Not Modified and stores new ETag-bearing 200 responses
+func (c *MCPXClient) applyETagCache(resp *http.Response, key string, cached *cacheEntry) (*http.Response, error) {
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_ = resp.Body.Close()
+		c.logger.Debug("not modified, using cached response", "url", cached.URL)
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		return resp, nil
+	}
+
+	body, err := c.readResponseBody(resp)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	c.saveCacheEntry(key, cacheEntry{URL: resp.Request.URL.String(), ETag: etag, Body: body})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// Settings holds user preferences. They live apart from AuthConfig so that logout does not remove them.
+type Settings struct {
+	// Registries maps registry aliases, usable with --registry, to base URLs
+	Registries map[string]string `json:"registries,omitempty"`
+	// DefaultLimit replaces the built-in page size of listings when --page-size is not given; 0 means unset
+	DefaultLimit int `json:"defaultLimit,omitempty"`
+	// Retries replaces the default of 0 when --retries is not given
+	Retries int `json:"retries,omitempty"`
+	// RetryBackoff replaces defaultRetryBackoff when --retry-backoff is not given, e.g. "2s"; empty means unset
+	RetryBackoff string `json:"retryBackoff,omitempty"`
+	// UpdateURL replaces defaultUpdateURL for self-update, e.g. for a mirror of the releases
+	UpdateURL string `json:"updateUrl,omitempty"`
+}
+
+func settingsPath() (string, error) {
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		var err error
+		homeDir, err = os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+	}
+	return filepath.Join(homeDir, settingsFileName), nil
+}
+
+func loadSettings() (Settings, error) {
+	var settings Settings
+	path, err := settingsPath()
+	if err != nil {
+		return settings, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil // No settings file is OK
+		}
+		return settings, fmt.Errorf("failed to read settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	return settings, nil
+}
+
+func saveSettings(settings Settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// State is data the CLI remembers between invocations, such as saved pagination cursors
+type State struct {
+	// Cursors maps a registry base URL to the next cursor saved with servers --save-cursor
+	Cursors map[string]string `json:"cursors,omitempty"`
+	// LastUpdateCheck is the Unix time of the last background release check
+	LastUpdateCheck int64 `json:"lastUpdateCheck,omitempty"`
+	// LatestVersion is the release tag found by that check
+	LatestVersion string `json:"latestVersion,omitempty"`
+}
+
+func statePath() (string, error) {
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		var err error
+		homeDir, err = os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+	}
+	return filepath.Join(homeDir, stateFileName), nil
+}
+
+func loadState() (State, error) {
+	var state State
+	path, err := statePath()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil // No state file is OK
+		}
+		return state, fmt.Errorf("failed to read state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+	return state, nil
+}
+
+func saveState(state State) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// savedCursor returns the cursor saved for this client's registry
+func (c *MCPXClient) savedCursor() (string, error) {
+	state, err := loadState()
+	if err != nil {
+		return "", err
+	}
+	cursor := state.Cursors[c.baseURL]
+	if cursor == "" {
+		return "", fmt.Errorf("no saved cursor for %s; run 'mcpx-cli servers --save-cursor' first", c.baseURL)
+	}
+	return cursor, nil
+}
+
+// saveCursor records the next cursor for this client's registry. An empty cursor means the listing is
+// exhausted, so the saved one is removed.
+func (c *MCPXClient) saveCursor(cursor string) error {
+	state, err := loadState()
+	if err != nil {
+		return err
+	}
+	if cursor == "" {
+		delete(state.Cursors, c.baseURL)
+	} else {
+		if state.Cursors == nil {
+			state.Cursors = map[string]string{}
+		}
+		state.Cursors[c.baseURL] = cursor
+	}
+	if err := saveState(state); err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+	return nil
+}
+
+// Set stores a setting by its dotted key, e.g. "registry.prod"
+func (s *Settings) Set(key, value string) error {
+	switch {
+	case strings.HasPrefix(key, "registry.") && len(key) > len("registry."):
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s must be an http(s) URL, got %q", key, value)
+		}
+		if s.Registries == nil {
+			s.Registries = map[string]string{}
+		}
+		s.Registries[strings.TrimPrefix(key, "registry.")] = value
+	case key == "default-limit":
+		limit, err := parsePageSize(key, value)
+		if err != nil {
+			return err
+		}
+		s.DefaultLimit = limit
+	case key == "retries":
+		retries, err := parseRetries(key, value)
+		if err != nil {
+			return err
+		}
+		s.Retries = retries
+	case key == "retry-backoff":
+		backoff, err := parseRetryBackoff(key, value)
+		if err != nil {
+			return err
+		}
+		s.RetryBackoff = backoff.String()
+	case key == "update-url":
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s must be an http(s) URL, got %q", key, value)
+		}
+		s.UpdateURL = value
+	default:
+		return fmt.Errorf("unknown setting %q (supported: registry.<name>, default-limit, retries, retry-backoff, update-url)", key)
+	}
+	return nil
+}
+
+// Get returns a setting by its dotted key
+func (s Settings) Get(key string) (string, bool) {
+	if name, ok := strings.CutPrefix(key, "registry."); ok {
+		value, found := s.Registries[name]
+		return value, found
+	}
+	switch {
+	case key == "default-limit" && s.DefaultLimit > 0:
+		return strconv.Itoa(s.DefaultLimit), true
+	case key == "retries" && s.Retries > 0:
+		return strconv.Itoa(s.Retries), true
+	case key == "retry-backoff" && s.RetryBackoff != "":
+		return s.RetryBackoff, true
+	case key == "update-url" && s.UpdateURL != "":
+		return s.UpdateURL, true
+	}
+	return "", false
+}
+
+// Entries returns every setting as sorted "key=value" lines
+func (s Settings) Entries() []string {
+	var entries []string
+	for name, value := range s.Registries {
+		entries = append(entries, "registry."+name+"="+value)
+	}
+	if s.DefaultLimit > 0 {
+		entries = append(entries, "default-limit="+strconv.Itoa(s.DefaultLimit))
+	}
+	if s.Retries > 0 {
+		entries = append(entries, "retries="+strconv.Itoa(s.Retries))
+	}
+	if s.RetryBackoff != "" {
+		entries = append(entries, "retry-backoff="+s.RetryBackoff)
+	}
+	if s.UpdateURL != "" {
+		entries = append(entries, "update-url="+s.UpdateURL)
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// Environment variables that preset global flags, e.g. from a project's --env-file
+const (
+	baseURLEnvVar = "MCPX_BASE_URL"
+	timeoutEnvVar = "MCPX_TIMEOUT"
+)
+
+// parseEnvFile parses KEY=VALUE lines as written in a .env file. Blank lines and lines starting with # are
+// skipped, an "export " prefix is allowed and a value wrapped in matching quotes is unquoted. Keys keep the
+// order of the file; a repeated key takes its last value.
+func parseEnvFile(data []byte) ([][2]string, error) {
+	var pairs [][2]string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE, got %q", i+1, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs, nil
+}
+
+// loadEnvFile sets the variables of the .env file at path that are not already in the environment, so a
+// value exported in the shell always wins over the file
+func loadEnvFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+	pairs, err := parseEnvFile(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, pair := range pairs {
+		if _, set := os.LookupEnv(pair[0]); set {
+			continue
+		}
+		if err := os.Setenv(pair[0], pair[1]); err != nil {
+			return fmt.Errorf("%s: failed to set %s: %w", path, pair[0], err)
+		}
+	}
+	return nil
+}
+
+// envFileArg returns the value of --env-file among the global flags of fs at the start of args, which end at
+// the command name. The file has to be loaded before fs parses them, because its variables supply defaults.
+func envFileArg(fs *flag.FlagSet, args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "env-file" {
+			if hasValue {
+				return value
+			}
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+			return ""
+		}
+		// Skip the separate value of any other flag that takes one
+		if f := fs.Lookup(name); f != nil && !hasValue {
+			if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); !ok || !bf.IsBoolFlag() {
+				i++
+			}
+		}
+	}
+	return ""
+}
+
+// Page size of listings when neither --page-size nor a personal default is set
+const (
+	defaultPageSize       = 30
+	defaultPageSizeEnvVar = "MCPX_DEFAULT_LIMIT"
+)
+
+// parsePageSize parses a page size from source (a setting or environment variable), which must be a positive integer
+func parsePageSize(source, value string) (int, error) {
+	limit, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", source, value)
+	}
+	return limit, nil
+}
+
+// configuredPageSize returns the page size used when --page-size is not given: $MCPX_DEFAULT_LIMIT, then the
+// default-limit setting, then defaultPageSize
+func configuredPageSize() (int, error) {
+	if value, ok := os.LookupEnv(defaultPageSizeEnvVar); ok && value != "" {
+		return parsePageSize(defaultPageSizeEnvVar, value)
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		return 0, err
+	}
+	if settings.DefaultLimit > 0 {
+		return settings.DefaultLimit, nil
+	}
+	return defaultPageSize, nil
+}
+
+// applyDefaultPageSize sets the configured page size on opts unless --page-size or --limit was passed
+// explicitly, so "--page-size 30" still wins over a personal default of 100
+func applyDefaultPageSize(fs *flag.FlagSet, opts *ListServersOptions) error {
+	explicit := false
+	fs.Visit(func(f *flag.Flag) {
+		explicit = explicit || f.Name == "page-size" || f.Name == "limit"
+	})
+	if explicit {
+		return nil
+	}
+	limit, err := configuredPageSize()
+	if err != nil {
+		return err
+	}
+	opts.Limit = limit
+	return nil
+}
+
+// Accepted ranges of the retries and retry-backoff settings and flags
+const (
+	maxRetries      = 20
+	minRetryBackoff = 10 * time.Millisecond
+	maxRetryBackoff = time.Minute
+)
+
+// parseRetries parses a retry count from source (a setting), which must be between 0 and maxRetries
+func parseRetries(source, value string) (int, error) {
+	retries, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || retries < 0 || retries > maxRetries {
+		return 0, fmt.Errorf("%s must be an integer from 0 to %d, got %q", source, maxRetries, value)
+	}
+	return retries, nil
+}
+
+// parseRetryBackoff parses a retry delay from source (a setting), e.g. "500ms" or "2s"
+func parseRetryBackoff(source, value string) (time.Duration, error) {
+	backoff, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || backoff < minRetryBackoff || backoff > maxRetryBackoff {
+		return 0, fmt.Errorf("%s must be a duration from %s to %s, got %q", source, minRetryBackoff, maxRetryBackoff, value)
+	}
+	return backoff, nil
+}
+
+// applyRetrySettings fills in retries and backoff from the settings file unless --retries or --retry-backoff
+// was passed explicitly, so the precedence is flag, then setting, then the built-in default. Explicit flag
+// values are checked against the same ranges as the settings.
+func applyRetrySettings(fs *flag.FlagSet, retries *int, backoff *time.Duration) error {
+	retriesSet, backoffSet := false, false
+	fs.Visit(func(f *flag.Flag) {
+		retriesSet = retriesSet || f.Name == "retries"
+		backoffSet = backoffSet || f.Name == "retry-backoff"
+	})
+	if retriesSet && (*retries < 0 || *retries > maxRetries) {
+		return fmt.Errorf("--retries must be from 0 to %d", maxRetries)
+	}
+	if backoffSet && (*backoff < minRetryBackoff || *backoff > maxRetryBackoff) {
+		return fmt.Errorf("--retry-backoff must be from %s to %s", minRetryBackoff, maxRetryBackoff)
+	}
+	if retriesSet && backoffSet {
+		return nil
+	}
+
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	if !retriesSet {
+		*retries = settings.Retries
+	}
+	if !backoffSet && settings.RetryBackoff != "" {
+		if *backoff, err = parseRetryBackoff("retry-backoff setting", settings.RetryBackoff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveRegistry maps a registry alias to its base URL; values that are not aliases are used as URLs
+func resolveRegistry(settings Settings, registry string) string {
+	if baseURL, ok := settings.Registries[registry]; ok {
+		return baseURL
+	}
+	return registry
+}
+
+// APIError is a registry response with an unexpected status code
+type APIError struct {
+	Op         string
+	StatusCode int
+	Body       []byte
+	// Hint is actionable guidance for common statuses such as 401 and 403; empty when there is none
+	Hint string
+}
+
+func (e *APIError) Error() string {
+	body := strings.TrimSpace(string(e.Body))
+	msg := fmt.Sprintf("%s failed with status %d", e.Op, e.StatusCode)
+	if body != "" {
+		msg += ": " + body
+	}
+	if e.Hint != "" {
+		msg += "\nHint: " + e.Hint
+	}
+	return msg
+}
+
+// authFailureHint explains a 401 or 403 from a write to serverName. A 401 means the registry did not accept
+// the credentials at all; a 403 means it did, but they do not grant access to this server's namespace.
+// Other statuses have no hint.
+func authFailureHint(statusCode int, serverName string) string {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return "not authenticated or the token has expired; run 'mcpx-cli login' or pass --token, then try again"
+	case http.StatusForbidden:
+		return fmt.Sprintf("authenticated, but not allowed to modify %s; check that you own the server and that its namespace matches your login (e.g. io.github.<user>/* requires logging in with GitHub as <user>)", serverName)
+	}
+	return ""
+}
+
+// jsonError is how every command reports a failure in --json mode. Error holds the registry's
+// problem+json document when it sent one, and {"message": "..."} otherwise. Status is 0 when no response was received.
+type jsonError struct {
+	Error  interface{} `json:"error"`
+	Status int         `json:"status"`
+	Hint   string      `json:"hint,omitempty"`
+}
+
+func newJSONError(status int, body []byte) jsonError {
+	var problem map[string]interface{}
+	if err := json.Unmarshal(body, &problem); err == nil && problem != nil {
+		return jsonError{Error: problem, Status: status}
+	}
+	return jsonError{Error: map[string]interface{}{"message": strings.TrimSpace(string(body))}, Status: status}
+}
+
+// printJSONError writes an error response to stdout as a jsonError document
+func printJSONError(status int, body []byte) error {
+	return writeJSONError(newJSONError(status, body))
+}
+
+// errJSONErrorWritten is returned once a jsonError document is on stdout: the command failed, but the
+// failure has been reported and main only sets the exit status
+var errJSONErrorWritten = errors.New("error already written as JSON")
+
+// writeJSONError writes doc to stdout and returns errJSONErrorWritten
+func writeJSONError(doc jsonError) error {
+	prettyJSON, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format JSON: %w", err)
+	}
+	fmt.Println(string(prettyJSON))
+	return errJSONErrorWritten
+}
+
+// fatal reports a command failure and exits. In JSON mode the error is also written to stdout as a jsonError
+// document, so the JSON stream never ends without an answer.
+func fatal(jsonOutput bool, context string, err error) {
+	if errors.Is(err, errJSONErrorWritten) {
+		os.Exit(1)
+	}
+	if jsonOutput {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			doc := newJSONError(apiErr.StatusCode, apiErr.Body)
+			doc.Hint = apiErr.Hint
+			_ = writeJSONError(doc)
+		} else {
+			_ = printJSONError(0, []byte(err.Error()))
+		}
+	}
+	log.Fatalf("%s: %v", context, err)
+}
+
+// defaultAuthMethod returns the login method used when --method is not given: $MCPX_AUTH_METHOD if set,
+// otherwise anonymous. An unknown method in the environment is an error rather than a silent fallback.
+func defaultAuthMethod() (string, error) {
+	method := strings.TrimSpace(os.Getenv(authMethodEnvVar))
+	switch method {
+	case "":
+		return AuthMethodAnonymous, nil
+	case AuthMethodAnonymous, AuthMethodGitHubOAuth, AuthMethodGitHubOIDC:
+		return method, nil
+	default:
+		return "", fmt.Errorf("%s=%q is not a supported method (anonymous, github-oauth, github-oidc)", authMethodEnvVar, method)
+	}
+}
+
+// Authentication commands
+func (c *MCPXClient) login(authMethod string) error {
+	switch authMethod {
+	case AuthMethodGitHubOAuth:
+		return c.loginGitHubOAuth()
+	case AuthMethodGitHubOIDC:
+		return c.loginGitHubOIDC()
+	case AuthMethodAnonymous:
+		return c.loginAnonymous()
+	default:
+		return fmt.Errorf("unsupported authentication method: %s", authMethod)
+	}
+}
+
+func (c *MCPXClient) loginGitHubOAuth() error {
+	// Implement GitHub OAuth flow
+	fmt.Println("GitHub OAuth authentication not yet implemented")
+	return nil
+}
+
+func (c *MCPXClient) loginGitHubOIDC() error {
+	// Implement GitHub OIDC flow
+	fmt.Println("GitHub OIDC authentication not yet implemented")
+	return nil
+}
+
+func (c *MCPXClient) loginAnonymous() error {
+	config, err := c.requestAnonymousToken()
+	if err != nil {
+		return err
+	}
+
+	if err := c.saveAuthConfig(config); err != nil {
+		return fmt.Errorf("failed to save auth config: %w", err)
+	}
+
+	c.logger.Info("Successfully authenticated as anonymous user")
+	return nil
+}
+
+// authEndpoint returns the endpoint of a token issuing method, e.g. "none" for anonymous tokens. It is
+// relative to the registry unless --auth-url moved auth to another base URL.
+func (c *MCPXClient) authEndpoint(method string) string {
+	if c.authURL == "" {
+		return "/v0/auth/" + method
+	}
+	return c.authURL + "/" + method
+}
+
+// requestAnonymousToken obtains an anonymous registry token without storing it
+func (c *MCPXClient) requestAnonymousToken() (AuthConfig, error) {
+	resp, err := c.makeRequest("POST", c.authEndpoint("none"), nil, "")
+	if err != nil {
+		return AuthConfig{}, fmt.Errorf("failed to authenticate: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		// Read the response body for error details
+		bodyBytes, _ := c.readResponseBody(resp)
+		return AuthConfig{}, fmt.Errorf("authentication failed with status: %d, response: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// Read the response body and log it for debugging
+	bodyBytes, err := c.readResponseBody(resp)
+	if err != nil {
+		return AuthConfig{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if len(bodyBytes) == 0 {
+		return AuthConfig{}, fmt.Errorf("server returned empty response body")
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(bodyBytes, &tokenResp); err != nil {
+		return AuthConfig{}, fmt.Errorf("failed to decode token response: %w, response body: %s", err, string(bodyBytes))
+	}
+
+	// Use provided expiration or default to 1 hour from now
+	expiresAt := tokenResp.ExpiresAt
+	if expiresAt == 0 {
+		expiresAt = time.Now().Add(time.Hour).Unix()
+	}
+
+	return AuthConfig{
+		Method:    AuthMethodAnonymous,
+		Token:     tokenResp.RegistryToken,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Server namespace kinds, see classifyNamespace
+const (
+	// NamespaceGitHub names (io.github.<user>/*) are owned by a GitHub user and need a GitHub token
+	NamespaceGitHub = "github"
+	// NamespaceAnonymous names (io.modelcontextprotocol.anonymous/*) can be published by anyone with an anonymous token
+	NamespaceAnonymous = "anonymous"
+	// NamespaceCustom is every other namespace, e.g. a verified domain
+	NamespaceCustom = "custom"
+)
+
+const anonymousNamespacePrefix = "io.modelcontextprotocol.anonymous/"
+
+// classifyNamespace reports which kind of namespace a server name belongs to
+func classifyNamespace(serverName string) string {
+	switch {
+	case strings.HasPrefix(serverName, "io.github."):
+		return NamespaceGitHub
+	case strings.HasPrefix(serverName, anonymousNamespacePrefix):
+		return NamespaceAnonymous
+	default:
+		return NamespaceCustom
+	}
+}
+
+// namespaceToken returns the token to publish or update serverName with. An empty result means the stored
+// login is used. io.github.* names need an explicit token. Anonymous names get an anonymous token: a stored
+// anonymous login is reused, otherwise one is obtained (and stored when there is no login to replace).
+// Other names log in anonymously only when nothing is stored, as publish always has.
+func (c *MCPXClient) namespaceToken(serverName, token string) (string, error) {
+	kind := classifyNamespace(serverName)
+	if kind == NamespaceAnonymous {
+		c.logger.Warn(fmt.Sprintf("%s is in the anonymous namespace; anonymous servers may be removed by the registry at any time, publish under io.github.<user>/* for a lasting entry", serverName))
+	}
+	if token != "" || c.noAuth {
+		return token, nil
+	}
+
+	switch kind {
+	case NamespaceGitHub:
+		return "", fmt.Errorf("authentication token is required for GitHub namespaced servers (io.github.*)")
+	case NamespaceAnonymous:
+		config, err := c.loadAuthConfig()
+		if err == nil && config.Token != "" {
+			if config.Method == AuthMethodAnonymous {
+				return "", nil
+			}
+			// Keep the stored login and use a one-off anonymous token for this request
+			c.logger.Info(fmt.Sprintf("Stored %s login cannot publish anonymous servers; requesting an anonymous token...", config.Method))
+			anonymous, err := c.requestAnonymousToken()
+			if err != nil {
+				return "", err
+			}
+			return anonymous.Token, nil
+		}
+	default:
+		if config, err := c.loadAuthConfig(); err == nil && config.Token != "" {
+			return "", nil
+		}
+	}
+
+	c.logger.Info("No valid authentication found. Attempting anonymous authentication...")
+	if err := c.loginAnonymous(); err != nil {
+		return "", fmt.Errorf("failed to authenticate: %w", err)
+	}
+	return "", nil
+}
+
+func (c *MCPXClient) logout() error {
+	if err := c.clearAuthConfig(); err != nil {
+		return fmt.Errorf("failed to clear authentication: %w", err)
+	}
+
+	fmt.Println("Successfully logged out")
+	return nil
+}
+
+func (c *MCPXClient) Health() error {
+	fmt.Println("=== Health Check ===")
+
+	resp, err := c.makeRequest("GET", "/v0/health", nil, "")
+	if err != nil {
+		return fmt.Errorf("health request failed: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	fmt.Printf("Status Code: %d\n", resp.StatusCode)
+
+	if resp.StatusCode == 200 {
+		var healthResp HealthResponse
+		if err := json.Unmarshal(body, &healthResp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		fmt.Printf("Status: %s\n", healthResp.Status)
+		if healthResp.GitHubClientID != "" {
+			fmt.Printf("GitHub Client ID: %s\n", healthResp.GitHubClientID)
+		}
+	} else {
+		c.logger.Error(string(body))
+	}
+
+	return nil
+}
+
+// HealthExitCode checks the registry without printing anything and returns the exit status for
+// health --exit-code-only: 0 when it answers 200 with status "ok", exitCodeUnhealthy for any other
+// answer, and exitCodeUnreachable when no answer arrives (DNS, refused connection, timeout).
+func (c *MCPXClient) HealthExitCode() int {
 	resp, err := c.makeRequest("GET", "/v0/health", nil, "")
 	if err != nil {
-		return fmt.Errorf("health request failed: %w", err)
+		return exitCodeUnreachable
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return exitCodeUnreachable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return exitCodeUnhealthy
+	}
+
+	var healthResp HealthResponse
+	if err := json.Unmarshal(body, &healthResp); err != nil || !strings.EqualFold(healthResp.Status, "ok") {
+		return exitCodeUnhealthy
+	}
+	return 0
+}
+
+// CheckCredentials sends the --token value, or else the stored login, to a protected endpoint of the registry
+// and reports whether the registry accepts it. A token that exists locally can still be rejected, e.g. when it
+// was issued by another registry or revoked. The verdict comes from the status: 2xx means accepted, 403 means
+// authenticated but not allowed to use the endpoint, 401 means rejected. Anything but accepted is an error.
+// The registry API has no endpoint meant for checking a token, so the caller names one that requires it.
+func (c *MCPXClient) CheckCredentials(token, endpoint string) error {
+	if endpoint == "" {
+		return fmt.Errorf("--endpoint is required: pass a GET endpoint of this registry that requires authentication")
+	}
+	fmt.Println("=== Credential Check ===")
+	fmt.Printf("Registry: %s\n", c.baseURL)
+
+	if c.noAuth {
+		return fmt.Errorf("--no-auth sends no credentials, so there is nothing to check")
+	}
+	source := "--token"
+	if token == "" {
+		config, err := c.loadAuthConfig()
+		if err != nil {
+			return err
+		}
+		if config.Token == "" {
+			return fmt.Errorf("no credentials to check (not logged in, or the stored token expired); run 'mcpx-cli login' or pass --token")
+		}
+		token = config.Token
+		source = fmt.Sprintf("stored %s login", config.Method)
+		if config.ExpiresAt > 0 {
+			source += fmt.Sprintf(", expires %s", time.Unix(config.ExpiresAt, 0).UTC().Format(time.RFC3339))
+		}
+	}
+	fmt.Printf("Credentials: %s\n", source)
+
+	resp, err := c.makeRequest("GET", endpoint, nil, token)
+	if err != nil {
+		return fmt.Errorf("check request failed: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	fmt.Printf("Status Code: %d\n", resp.StatusCode)
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		fmt.Println("✅ The registry accepted the credentials")
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		fmt.Println("❌ The registry rejected the credentials")
+		return &APIError{Op: "check credentials", StatusCode: resp.StatusCode, Body: body,
+			Hint: "the token is expired, revoked or was issued by another registry; run 'mcpx-cli login' against this base URL"}
+	case resp.StatusCode == http.StatusForbidden:
+		fmt.Println("❌ The registry recognised the credentials, but they do not grant access to " + endpoint)
+		return &APIError{Op: "check credentials", StatusCode: resp.StatusCode, Body: body}
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed:
+		fmt.Println("❌ Inconclusive: the registry does not serve " + endpoint)
+		return &APIError{Op: "check credentials", StatusCode: resp.StatusCode, Body: body,
+			Hint: "pass --endpoint with a protected GET endpoint of this registry"}
+	default:
+		fmt.Println("❌ Inconclusive: unexpected response")
+		return &APIError{Op: "check credentials", StatusCode: resp.StatusCode, Body: body}
+	}
+}
+
+// ListServersOptions holds the flags shared by the list-style commands (servers, search, versions)
+type ListServersOptions struct {
+	Cursor string
+	// Limit is the page size requested from the registry (--page-size, or the deprecated --limit)
+	Limit int
+	// Count is the total number of servers wanted across pages; fetching stops once it is reached
+	Count int
+	// NoClientLimit keeps every server of a single page even when the registry returned more than Limit
+	NoClientLimit bool
+	JSON          bool
+	Detailed      bool
+	// All follows NextCursor until every page has been fetched
+	All bool
+	// Filter keeps only servers whose name or description contains it (case-insensitive, client-side)
+	Filter string
+	// IDOnly prints one server ID per line and nothing else
+	IDOnly bool
+	// RepositoryURL keeps only servers whose repository URL contains it (case-insensitive, client-side)
+	RepositoryURL string
+	// GroupBy clusters the output; the only supported value is "repository"
+	GroupBy string
+	// Order sorts listed versions, "asc" or "desc"; empty keeps the registry's order (versions)
+	Order string
+	// Head and Tail keep only the first or last N servers after fetching and filtering (servers)
+	Head int
+	Tail int
+	// SaveCursor records the next cursor for the registry so a later Resume continues from it (servers)
+	SaveCursor bool
+	// Resume starts from the cursor saved by an earlier SaveCursor (servers)
+	Resume bool
+	// CSV prints the servers as comma-separated values with a header row (servers)
+	CSV bool
+	// UpdatedAfter and UpdatedBefore keep only servers whose registry updatedAt (or publishedAt when the
+	// registry sent no update time) lies in the range; a zero time leaves that side open (client-side)
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	// FieldSelectors are sent to the registry as query parameters and checked again client-side (servers)
+	FieldSelectors []fieldSelector
+}
+
+// hasFilters reports whether any client-side filter is set
+func (o ListServersOptions) hasFilters() bool {
+	return o.Filter != "" || o.RepositoryURL != "" || !o.UpdatedAfter.IsZero() || !o.UpdatedBefore.IsZero() || len(o.FieldSelectors) > 0
+}
+
+// fieldSelector is one key=value of --field-selector, e.g. status=active
+type fieldSelector struct {
+	Key   string
+	Value string
+}
+
+// fieldSelectorFields are the keys --field-selector accepts. Each is sent to /v0/servers as the query parameter
+// of the same name, and matches returns whether a server satisfies it, for registries that reject or ignore it.
+var fieldSelectorFields = map[string]func(server Server, value string) bool{
+	// The registry omits the status of active servers
+	"status": func(server Server, value string) bool {
+		status := server.Status
+		if status == "" {
+			status = ServerStatusActive
+		}
+		return strings.EqualFold(status, value)
+	},
+	"source": func(server Server, value string) bool {
+		return strings.EqualFold(server.Repository.Source, value)
+	},
+}
+
+// parseFieldSelectors parses a --field-selector value: comma-separated key=value pairs with known keys
+func parseFieldSelectors(value string) ([]fieldSelector, error) {
+	var selectors []fieldSelector
+	for _, pair := range strings.Split(value, ",") {
+		key, fieldValue, err := parseKeyValue(pair)
+		if err != nil {
+			return nil, err
+		}
+		key = strings.ToLower(key)
+		if _, ok := fieldSelectorFields[key]; !ok {
+			known := make([]string, 0, len(fieldSelectorFields))
+			for name := range fieldSelectorFields {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown field selector %q (expected one of: %s)", key, strings.Join(known, ", "))
+		}
+		selectors = append(selectors, fieldSelector{Key: key, Value: strings.TrimSpace(fieldValue)})
+	}
+	return selectors, nil
+}
+
+// withFieldSelectors adds the selectors to endpoint as query parameters
+func withFieldSelectors(endpoint string, selectors []fieldSelector) string {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		params = url.Values{}
+	}
+	for _, selector := range selectors {
+		params.Set(selector.Key, selector.Value)
+	}
+	return path + "?" + params.Encode()
+}
+
+// parseTimeBound parses an --updated-after/--updated-before value: an RFC3339 timestamp, a date such as
+// 2025-01-31, or a duration back from now such as 36h, 7d or 2w
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			if days, err := strconv.Atoi(n); err == nil && days >= 0 {
+				return now.Add(-time.Duration(days) * unit), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (expected RFC3339 such as 2025-01-31T12:00:00Z, a date such as 2025-01-31, or a duration such as 36h, 7d or 2w)", value)
+}
+
+// updatedAt returns when the registry last changed a server: the updatedAt of its registry metadata, or
+// publishedAt when there is none. ok is false when neither is a valid RFC3339 timestamp.
+func updatedAt(server Server) (time.Time, bool) {
+	if server.Meta == nil || server.Meta.Official == nil {
+		return time.Time{}, false
+	}
+	for _, value := range []string{server.Meta.Official.UpdatedAt, server.Meta.Official.PublishedAt} {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// filterServers applies the client-side filters of opts to servers
+func filterServers(servers []Server, opts ListServersOptions) []Server {
+	if !opts.hasFilters() {
+		return servers
+	}
+
+	needle := strings.ToLower(opts.Filter)
+	repoNeedle := strings.ToLower(opts.RepositoryURL)
+	filtered := []Server{}
+	for _, server := range servers {
+		if needle != "" && !strings.Contains(strings.ToLower(server.Name), needle) && !strings.Contains(strings.ToLower(server.Description), needle) {
+			continue
+		}
+		if repoNeedle != "" && !strings.Contains(strings.ToLower(server.Repository.URL), repoNeedle) {
+			continue
+		}
+		if !matchesFieldSelectors(server, opts.FieldSelectors) {
+			continue
+		}
+		if !opts.UpdatedAfter.IsZero() || !opts.UpdatedBefore.IsZero() {
+			// Servers without a registry timestamp cannot be placed in the range
+			updated, ok := updatedAt(server)
+			if !ok || (!opts.UpdatedAfter.IsZero() && !updated.After(opts.UpdatedAfter)) || (!opts.UpdatedBefore.IsZero() && !updated.Before(opts.UpdatedBefore)) {
+				continue
+			}
+		}
+		filtered = append(filtered, server)
+	}
+	return filtered
+}
+
+// matchesFieldSelectors reports whether server satisfies every selector
+func matchesFieldSelectors(server Server, selectors []fieldSelector) bool {
+	for _, selector := range selectors {
+		if !fieldSelectorFields[selector.Key](server, selector.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// sliceServers applies --head or --tail to an already fetched and filtered list
+func sliceServers(servers []Server, opts ListServersOptions) []Server {
+	switch {
+	case opts.Head > 0 && opts.Head < len(servers):
+		return servers[:opts.Head]
+	case opts.Tail > 0 && opts.Tail < len(servers):
+		return servers[len(servers)-opts.Tail:]
+	}
+	return servers
+}
+
+const noRepositoryGroup = "(no repository)"
+
+// groupServersByRepository clusters servers by repository URL, returning the groups and their sorted keys
+func groupServersByRepository(servers []Server) (map[string][]Server, []string) {
+	groups := map[string][]Server{}
+	var keys []string
+	for _, server := range servers {
+		key := server.Repository.URL
+		if key == "" {
+			key = noRepositoryGroup
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], server)
+	}
+	sort.Strings(keys)
+	return groups, keys
+}
+
+// printServerGroups prints servers clustered by repository URL
+func printServerGroups(servers []Server, jsonOutput bool) error {
+	groups, keys := groupServersByRepository(servers)
+
+	if jsonOutput {
+		prettyJSON, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(prettyJSON))
+		return nil
+	}
+
+	if len(servers) == 0 {
+		fmt.Println("No servers found.")
+		return nil
+	}
+
+	fmt.Printf("Total Servers: %d in %d repositories\n", len(servers), len(keys))
+	for _, key := range keys {
+		fmt.Printf("\n=== %s (%d) ===\n", key, len(groups[key]))
+		for _, server := range groups[key] {
+			fmt.Printf("  %s %s (ID: %s)\n", server.Name, server.Version, server.GetServerID())
+		}
+	}
+	return nil
+}
+
+// maxCursorLength bounds --cursor values; registry cursors are short opaque tokens
+const maxCursorLength = 1024
+
+// errInvalidCursor is returned when the registry rejects a cursor with 400 Bad Request
+var errInvalidCursor = errors.New("invalid or expired cursor")
+
+// checkCursor validates a --cursor value before it is sent. Cursors are opaque, so only obviously broken
+// values are rejected: whitespace or control characters, or an excessive length. A full listing URL pasted
+// as a cursor is recognised: its cursor parameter is used when it points at this registry, and it is
+// rejected when it belongs to another one.
+func (c *MCPXClient) checkCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	if len(cursor) > maxCursorLength {
+		return "", fmt.Errorf("cursor is %d characters long; expected at most %d", len(cursor), maxCursorLength)
+	}
+	for _, r := range cursor {
+		if r <= ' ' || r == 0x7f {
+			return "", fmt.Errorf("cursor %q contains whitespace or control characters", cursor)
+		}
+	}
+
+	if parsed, err := url.Parse(cursor); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") {
+		base, err := url.Parse(c.baseURL)
+		if err != nil || !strings.EqualFold(base.Host, parsed.Host) {
+			return "", fmt.Errorf("cursor is a URL for %s, a different registry than %s", parsed.Host, c.baseURL)
+		}
+		inner := parsed.Query().Get("cursor")
+		if inner == "" {
+			return "", fmt.Errorf("cursor URL %s has no cursor parameter", cursor)
+		}
+		return inner, nil
+	}
+	return cursor, nil
+}
+
+// cursorError replaces a 400 Bad Request for a listing that started at cursor with errInvalidCursor,
+// since the registry's own message for a rejected cursor is rarely helpful
+func cursorError(cursor string, err error) error {
+	var apiErr *APIError
+	if cursor != "" && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		return fmt.Errorf("%w %q", errInvalidCursor, cursor)
+	}
+	return err
+}
+
+// withPageParams adds the cursor and limit query parameters to an endpoint that may already carry a query string
+func withPageParams(endpoint, cursor string, limit int) string {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		params = url.Values{}
+	}
+
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	if len(params) == 0 {
+		return path
+	}
+	return path + "?" + params.Encode()
+}
+
+// parseServersResponse parses a server listing in the wrapper format
+// ({"servers": [{"server": {...}, "_meta": {...}}]}), the legacy format ({"servers": [{"name": ...}]}),
+// or a mix of both. The format is detected per entry, and server IDs are taken from the registry metadata.
+func parseServersResponse(body []byte) ([]Server, Metadata, error) {
+	var raw struct {
+		Servers  []json.RawMessage `json:"servers"`
+		Metadata Metadata          `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	servers := make([]Server, 0, len(raw.Servers))
+	for i, entry := range raw.Servers {
+		var probe struct {
+			Server json.RawMessage `json:"server"`
+		}
+		if err := json.Unmarshal(entry, &probe); err != nil {
+			return nil, raw.Metadata, fmt.Errorf("servers[%d]: %w", i, err)
+		}
+
+		if len(probe.Server) > 0 {
+			// New wrapper format
+			var wrapper ServerWrapper
+			if err := json.Unmarshal(entry, &wrapper); err != nil {
+				return nil, raw.Metadata, fmt.Errorf("servers[%d]: %w", i, err)
+			}
+			server := wrapper.Server
+			// Extract server ID from wrapper metadata and set it in the server object
+			if serverID := wrapper.GetServerID(); serverID != "" {
+				server.noteEmbeddedID(wrapper.Server.ID, serverID)
+				server.ID = serverID
+			}
+			// Keep the registry extensions (latest flag, publish date) of the wrapper
+			if official := wrapper.RegistryMeta.official(); server.Meta == nil && official != nil {
+				server.Meta = &ServerMeta{Official: official}
+			}
+			servers = append(servers, server)
+			continue
+		}
+
+		// Legacy format: server IDs are extracted via the _meta field in the Server struct
+		var server Server
+		if err := json.Unmarshal(entry, &server); err != nil {
+			return nil, raw.Metadata, fmt.Errorf("servers[%d]: %w", i, err)
+		}
+		if server.Meta != nil && server.Meta.Official != nil {
+			server.noteEmbeddedID(server.ID, server.Meta.Official.ServerID)
+		}
+		servers = append(servers, server)
+	}
+
+	return servers, raw.Metadata, nil
+}
+
+// fetchServersPage fetches a single page of a server listing.
+// Servers and metadata are only parsed for a 200 response; the status code and raw body are always returned.
+func (c *MCPXClient) fetchServersPage(endpoint, cursor string, limit int) ([]Server, Metadata, int, []byte, error) {
+	var metadata Metadata
+
+	resp, err := c.makeRequest("GET", withPageParams(endpoint, cursor, limit), nil, "")
+	if err != nil {
+		return nil, metadata, 0, nil, fmt.Errorf("list servers request failed: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return nil, metadata, resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, metadata, resp.StatusCode, body, nil
+	}
+
+	servers, metadata, err := parseServersResponse(body)
+	if err != nil {
+		return nil, metadata, resp.StatusCode, body, fmt.Errorf("failed to parse response: %w", err)
+	}
+	c.warnIDMismatches(servers...)
+
+	return servers, metadata, resp.StatusCode, body, nil
+}
+
+// errStopIteration can be returned by a yield function to stop iterating early without an error
+var errStopIteration = errors.New("stop iteration")
+
+// serverListKey identifies one entry of a listing: the version ID when the registry sends one, and otherwise
+// the server ID together with name and version, so the versions of one server stay distinct
+func serverListKey(server Server) string {
+	if server.Meta != nil && server.Meta.Official != nil && server.Meta.Official.VersionID != "" {
+		return "version:" + server.Meta.Official.VersionID
+	}
+	id := server.ID
+	if server.Meta != nil && server.Meta.Official != nil && server.Meta.Official.ServerID != "" {
+		id = server.Meta.Official.ServerID
+	}
+	return id + "\x00" + server.Name + "\x00" + server.Version
+}
+
+// iterateServerList calls yield for every server of a listing endpoint, following NextCursor from the
+// given cursor until the listing is exhausted or yield returns an error. Format detection (wrapper vs
+// legacy) and ID extraction are handled per page. An entry seen before, e.g. on overlapping pages, is
+// skipped, and the number skipped is logged in verbose mode. The returned metadata describes what was iterated.
+func (c *MCPXClient) iterateServerList(endpoint, cursor string, limit int, yield func(Server) error) (Metadata, error) {
+	var metadata Metadata
+	seenCursors := map[string]bool{}
+	seenServers := map[string]bool{}
+	duplicates := 0
+	defer func() {
+		if duplicates > 0 {
+			c.logger.Debug("collapsed duplicate servers returned by the registry", "endpoint", endpoint, "duplicates", duplicates)
+		}
+	}()
+
+	for {
+		servers, pageMeta, statusCode, body, err := c.fetchServersPage(endpoint, cursor, limit)
+		if err != nil {
+			return metadata, err
+		}
+		if statusCode != 200 {
+			return metadata, &APIError{Op: "list request", StatusCode: statusCode, Body: body}
+		}
+
+		if pageMeta.Total > 0 {
+			metadata.Total = pageMeta.Total
+		}
+		for _, server := range servers {
+			key := serverListKey(server)
+			if seenServers[key] {
+				duplicates++
+				continue
+			}
+			seenServers[key] = true
+			if err := yield(server); err != nil {
+				if errors.Is(err, errStopIteration) {
+					return metadata, nil
+				}
+				return metadata, err
+			}
+			metadata.Count++
+		}
+
+		if pageMeta.NextCursor == "" || len(servers) == 0 {
+			return metadata, nil
+		}
+		if seenCursors[pageMeta.NextCursor] {
+			c.logger.Warn("registry returned a cursor that was already visited, stopping pagination", "cursor", pageMeta.NextCursor)
+			return metadata, nil
+		}
+		seenCursors[pageMeta.NextCursor] = true
+		cursor = pageMeta.NextCursor
+	}
+}
+
+// iterateServers calls yield for every server in the registry, starting at cursor
+func (c *MCPXClient) iterateServers(cursor string, limit int, yield func(Server) error) error {
+	_, err := c.iterateServerList("/v0/servers", cursor, limit, yield)
+	return err
+}
+
+// fetchAllPages collects every server of a listing endpoint, starting at cursor.
+// The returned metadata describes the combined result and carries no cursor.
+func (c *MCPXClient) fetchAllPages(endpoint, cursor string, limit int) ([]Server, Metadata, error) {
+	var all []Server
+	metadata, err := c.iterateServerList(endpoint, cursor, limit, func(server Server) error {
+		all = append(all, server)
+		return nil
+	})
+	if err != nil {
+		return nil, metadata, err
+	}
+
+	return all, metadata, nil
+}
+
+// fetchServerList fetches one page, every page when opts.All is set, or pages until opts.Count servers are collected.
+// For a single page, a non-200 status is returned with its body instead of an error so callers can print it.
+func (c *MCPXClient) fetchServerList(endpoint string, opts ListServersOptions) ([]Server, Metadata, int, []byte, error) {
+	cursor, err := c.checkCursor(opts.Cursor)
+	if err != nil {
+		return nil, Metadata{}, 0, nil, err
+	}
+	opts.Cursor = cursor
+
+	if opts.Count > 0 {
+		var servers []Server
+		metadata, err := c.iterateServerList(endpoint, opts.Cursor, opts.Limit, func(server Server) error {
+			servers = append(servers, server)
+			if len(servers) >= opts.Count {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, metadata, 0, nil, cursorError(opts.Cursor, err)
+		}
+		metadata.Count = len(servers)
+		return servers, metadata, 200, nil, nil
+	}
+	if opts.All {
+		servers, metadata, err := c.fetchAllPages(endpoint, opts.Cursor, opts.Limit)
+		if err != nil {
+			return nil, metadata, 0, nil, cursorError(opts.Cursor, err)
+		}
+		return servers, metadata, 200, nil, nil
+	}
+
+	servers, metadata, statusCode, body, err := c.fetchServersPage(endpoint, opts.Cursor, opts.Limit)
+	if err == nil && statusCode == http.StatusBadRequest && opts.Cursor != "" {
+		return nil, metadata, statusCode, body, cursorError(opts.Cursor, &APIError{Op: "list request", StatusCode: statusCode, Body: body})
+	}
+	if err == nil && !opts.NoClientLimit && opts.Limit > 0 && len(servers) > opts.Limit {
+		// Some registries ignore the limit parameter and return everything. Their cursor points past the whole
+		// response, so following it after truncating would skip servers; it is dropped, and --save-cursor fails.
+		if opts.SaveCursor {
+			return nil, metadata, 0, nil, fmt.Errorf("registry returned %d servers for a page size of %d, so no cursor resumes after the first %d; use --no-client-limit",
+				len(servers), opts.Limit, opts.Limit)
+		}
+		c.logger.Warn(fmt.Sprintf("registry returned %d servers for a page size of %d; showing the first %d without a next cursor (use --no-client-limit to show all)",
+			len(servers), opts.Limit, opts.Limit))
+		servers = servers[:opts.Limit]
+		metadata.NextCursor = ""
+		if metadata.Count > 0 {
+			metadata.Count = len(servers)
+		}
+	}
+	return servers, metadata, statusCode, body, err
+}
+
+// serversCSVHeader is the header row written by writeServersCSV
+var serversCSVHeader = []string{"id", "name", "version", "status", "repository_url", "source", "description"}
+
+// writeServersCSV writes one row per server below a header row. Values containing commas, quotes or
+// newlines are quoted by encoding/csv, so descriptions import cleanly into spreadsheets.
+func writeServersCSV(w io.Writer, servers []Server) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(serversCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, server := range servers {
+		record := []string{server.GetServerID(), server.Name, server.Version, server.Status, server.Repository.URL, server.Repository.Source, server.Description}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// printServerList prints servers in the human-readable listing format
+func printServerList(servers []Server, metadata Metadata) {
+	if len(servers) == 0 {
+		fmt.Println("No servers found.")
+		return
+	}
+
+	fmt.Printf("Total Servers: %d\n", len(servers))
+	if metadata.NextCursor != "" {
+		fmt.Printf("Next Cursor: %s\n", metadata.NextCursor)
+	}
+	for i, server := range servers {
+		fmt.Printf("\n--- Server %d ---\n", i+1)
+		fmt.Printf("ID: %s\n", server.GetServerID())
+		if versionID := server.GetVersionID(); versionID != "" {
+			fmt.Printf("Version ID: %s\n", versionID)
+		}
+		fmt.Printf("Name: %s\n", server.Name)
+		fmt.Printf("Description: %s\n", server.Description)
+		if server.Status != "" {
+			fmt.Printf("Status: %s\n", server.Status)
+		}
+		fmt.Printf("Repository: %s (%s)\n", server.Repository.URL, server.Repository.Source)
+		fmt.Printf("Version: %s\n", server.Version)
+	}
+}
+
+// printServerListJSON prints servers in the legacy list JSON format
+func printServerListJSON(servers []Server, metadata Metadata) error {
+	prettyJSON, err := json.MarshalIndent(serverListDocument(servers, metadata), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format JSON: %w", err)
+	}
+	fmt.Println(string(prettyJSON))
+	return nil
+}
+
+// serverListDocument is the --json form of a server listing
+func serverListDocument(servers []Server, metadata Metadata) LegacyServersResponse {
+	if servers == nil {
+		// Always emit an array, never null
+		servers = []Server{}
+	}
+	return LegacyServersResponse{Servers: servers, Metadata: metadata}
+}
+
+func (c *MCPXClient) ListServers(opts ListServersOptions) error {
+	quiet := opts.JSON || opts.IDOnly || opts.CSV
+	if !quiet {
+		fmt.Println("=== List Servers ===")
+	}
+
+	if opts.Resume {
+		cursor, err := c.savedCursor()
+		if err != nil {
+			return err
+		}
+		opts.Cursor = cursor
+	}
+
+	servers, metadata, statusCode, body, err := c.fetchSelectedServerList(opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.SaveCursor && statusCode == 200 {
+		if err := c.saveCursor(metadata.NextCursor); err != nil {
+			return err
+		}
+		if metadata.NextCursor == "" {
+			c.logger.Info("reached the end of the listing; the saved cursor was cleared")
+		}
+	}
+
+	if opts.IDOnly {
+		if statusCode != 200 {
+			return &APIError{Op: "list servers", StatusCode: statusCode, Body: body}
+		}
+		servers = sliceServers(filterServers(servers, opts), opts)
+		for _, server := range servers {
+			fmt.Println(server.GetServerID())
+		}
+		metadata.Count = len(servers)
+		return c.writeToSinks(serverListDocument(servers, metadata))
+	}
+
+	if !quiet {
+		fmt.Printf("Status Code: %d\n", statusCode)
+	}
+
+	if opts.hasFilters() || opts.Head > 0 || opts.Tail > 0 {
+		servers = sliceServers(filterServers(servers, opts), opts)
+		metadata.Count = len(servers)
+	}
+
+	if statusCode == 200 && !opts.Detailed {
+		if err := c.writeToSinks(serverListDocument(servers, metadata)); err != nil {
+			return err
+		}
+	}
+
+	if statusCode == 200 && opts.GroupBy != "" {
+		return printServerGroups(servers, opts.JSON)
+	}
+
+	if opts.CSV {
+		if statusCode != 200 {
+			return &APIError{Op: "list servers", StatusCode: statusCode, Body: body}
+		}
+		return writeServersCSV(os.Stdout, servers)
+	}
+
+	if statusCode == 200 {
+		if opts.Detailed && opts.JSON {
+			detailedServers, summary, err := c.fetchServerDetails(servers)
+			if err != nil {
+				return err
+			}
+			detailedResp := LegacyDetailedServersResponse{
+				Servers:  detailedServers,
+				Metadata: metadata,
+				Details:  &summary,
+			}
+			if err := c.writeToSinks(detailedResp); err != nil {
+				return err
+			}
+			prettyJSON, err := json.MarshalIndent(detailedResp, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+			fmt.Println(string(prettyJSON))
+		} else if opts.JSON {
+			// Convert back to legacy format for output
+			return printServerListJSON(servers, metadata)
+		} else {
+			printServerList(servers, metadata)
+		}
+	} else {
+		if opts.JSON {
+			return printJSONError(statusCode, body)
+		}
+		return &APIError{Op: "list servers", StatusCode: statusCode, Body: body}
+	}
+
+	return nil
+}
+
+// fetchSelectedServerList fetches the servers listing with the field selectors of opts as query parameters, so
+// the registry filters before paginating. A registry that does not support them answers 400; the listing is
+// then fetched without them and the selectors only filter client-side, as they always do in filterServers.
+func (c *MCPXClient) fetchSelectedServerList(opts ListServersOptions) ([]Server, Metadata, int, []byte, error) {
+	if len(opts.FieldSelectors) == 0 {
+		return c.fetchServerList("/v0/servers", opts)
+	}
+	servers, metadata, statusCode, body, err := c.fetchServerList(withFieldSelectors("/v0/servers", opts.FieldSelectors), opts)
+	var apiErr *APIError
+	rejected := statusCode == http.StatusBadRequest || (errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest)
+	// With a cursor, a 400 more likely means the cursor is invalid, which is reported as such
+	if !rejected || opts.Cursor != "" {
+		return servers, metadata, statusCode, body, err
+	}
+	pairs := make([]string, 0, len(opts.FieldSelectors))
+	for _, selector := range opts.FieldSelectors {
+		pairs = append(pairs, selector.Key+"="+selector.Value)
+	}
+	c.logger.Warn("registry rejected the field selectors (status 400); filtering client-side instead, so pages may hold fewer servers",
+		"selectors", strings.Join(pairs, ","))
+	return c.fetchServerList("/v0/servers", opts)
+}
+
+// fetchServerDetails fetches the packages and remotes of every server. A server whose detail request fails
+// with an error status or an unparseable body falls back to its shallow entry and is recorded in the summary.
+func (c *MCPXClient) fetchServerDetails(servers []Server) ([]ServerDetail, DetailFetchSummary, error) {
+	detailedServers := []ServerDetail{}
+	var summary DetailFetchSummary
+	progress := c.newProgress("Fetching details", len(servers))
+	for _, server := range servers {
+		serverDetail, ok, err := c.fetchListedServerDetail(server)
+		if err != nil {
+			progress.Done()
+			return nil, summary, err
+		}
+		if ok {
+			summary.DetailsFetched++
+		} else {
+			summary.DetailsFailed++
+			summary.FailedIDs = append(summary.FailedIDs, serverDetail.ID)
+		}
+		detailedServers = append(detailedServers, serverDetail)
+		progress.Add(1)
+	}
+	progress.Done()
+
+	if summary.DetailsFailed > 0 {
+		c.logger.Warn(fmt.Sprintf("details fetched for %d of %d servers", summary.DetailsFetched, len(servers)), "failed_ids", strings.Join(summary.FailedIDs, ","))
+	}
+	return detailedServers, summary, nil
+}
+
+// fetchListedServerDetail fetches the packages and remotes of one listing entry. When the detail request
+// fails with an error status or an unparseable body, a warning is logged, the shallow entry is returned
+// instead and ok is false.
+func (c *MCPXClient) fetchListedServerDetail(server Server) (ServerDetail, bool, error) {
+	// Wrapper-format listings carry the ID in _meta rather than in the server object
+	serverID := server.ID
+	if serverID == "" {
+		serverID = server.GetServerID()
+	}
+
+	detailResp, err := c.makeRequest("GET", "/v0/servers/"+url.PathEscape(serverID), nil, "")
+	if err != nil {
+		return ServerDetail{}, false, fmt.Errorf("failed to get details for server %s: %w", serverID, err)
+	}
+	detailBody, err := c.readResponseBody(detailResp)
+	_ = detailResp.Body.Close()
+	if err != nil {
+		return ServerDetail{}, false, fmt.Errorf("failed to read detail response for server %s: %w", serverID, err)
+	}
+
+	if detailResp.StatusCode == 200 {
+		serverDetail, err := parseServerDetail(detailBody)
+		if err == nil && serverDetail.Name != "" {
+			// The detail body may not repeat the ID; keep the one the listing reported
+			if serverDetail.ID == "" {
+				serverDetail.ID = serverID
+			}
+			return serverDetail, true, nil
+		}
+	}
+
+	c.logger.Warn("failed to fetch server details, using the list entry", "id", serverID, "status", detailResp.StatusCode)
+	fallback := ServerDetail{Server: server}
+	fallback.ID = serverID
+	return fallback, false, nil
+}
+
+// serverNamesLike returns the distinct names of servers whose name contains pattern, case-insensitively, in
+// listing order. The registry's search narrows the listing first; its matches on other fields are dropped.
+func (c *MCPXClient) serverNamesLike(pattern string) ([]string, error) {
+	servers, _, err := c.fetchAllPages("/v0/servers?search="+url.QueryEscape(pattern), "", 0)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	seen := map[string]bool{}
+	lowerPattern := strings.ToLower(pattern)
+	for _, server := range servers {
+		if seen[server.Name] || !strings.Contains(strings.ToLower(server.Name), lowerPattern) {
+			continue
+		}
+		seen[server.Name] = true
+		names = append(names, server.Name)
+	}
+	return names, nil
+}
+
+// selectServerName resolves --name-like to one server name. A single match is used directly; several matches
+// are offered as a numbered choice when interactive, and reported as an error otherwise.
+func (c *MCPXClient) selectServerName(pattern string, interactive bool) (string, error) {
+	names, err := c.serverNamesLike(pattern)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case len(names) == 0:
+		return "", fmt.Errorf("no server name contains %q", pattern)
+	case len(names) == 1:
+		c.logger.Info(fmt.Sprintf("Using the only match: %s", names[0]))
+		return names[0], nil
+	case !interactive:
+		return "", fmt.Errorf("%d servers match %q, pass one of them by name: %s", len(names), pattern, strings.Join(names, ", "))
+	}
+	return promptChoice(fmt.Sprintf("%d servers match %q, select one:", len(names), pattern), names, ""), nil
+}
+
+// FirstServer prints the details of the first listed server, or of the first one matching the client-side
+// filters of opts, as a single object rather than a list. Without filters only one server is requested.
+func (c *MCPXClient) FirstServer(opts ListServersOptions) error {
+	limit := 1
+	if opts.hasFilters() {
+		limit = opts.Limit
+	}
+
+	var first *Server
+	_, err := c.iterateServerList("/v0/servers", opts.Cursor, limit, func(server Server) error {
+		if len(filterServers([]Server{server}, opts)) == 0 {
+			return nil
+		}
+		first = &server
+		return errStopIteration
+	})
+	if err != nil {
+		return cursorError(opts.Cursor, err)
+	}
+	if first == nil {
+		if opts.hasFilters() {
+			return fmt.Errorf("no server matches the given filters")
+		}
+		return fmt.Errorf("the registry has no servers")
+	}
+
+	detail, _, err := c.fetchListedServerDetail(*first)
+	if err != nil {
+		return err
+	}
+	if opts.JSON {
+		return printIndentedJSON(detail)
+	}
+	fmt.Println("=== First Server ===")
+	printServerDetail(detail)
+	return nil
+}
+
+// ServerCount is the outcome of the count command. Source is "metadata" when the registry reported the total
+// and "listing" when every page had to be fetched and counted.
+type ServerCount struct {
+	Total  int    `json:"total"`
+	Source string `json:"source"`
+}
+
+// CountServers prints the number of servers in the registry. A single one-server page is enough when the
+// registry reports metadata.total; otherwise the whole listing is paged through with pageSize.
+func (c *MCPXClient) CountServers(pageSize int, jsonOutput bool) error {
+	servers, metadata, statusCode, body, err := c.fetchServersPage("/v0/servers", "", 1)
+	if err != nil {
+		return err
+	}
+	if statusCode != 200 {
+		return &APIError{Op: "list request", StatusCode: statusCode, Body: body}
+	}
+
+	count := ServerCount{Total: metadata.Total, Source: "metadata"}
+	if metadata.Total == 0 && (len(servers) > 0 || metadata.NextCursor != "") {
+		c.logger.Debug("registry did not report metadata.total; counting every page")
+		listed, err := c.iterateServerList("/v0/servers", "", pageSize, func(Server) error { return nil })
+		if err != nil {
+			return err
+		}
+		count = ServerCount{Total: listed.Count, Source: "listing"}
+	}
+
+	if jsonOutput {
+		return printIndentedJSON(count)
+	}
+	fmt.Printf("Total servers: %d\n", count.Total)
+	return nil
+}
+
+// SearchServers lists servers matching a free-text query using the registry's search parameter
+func (c *MCPXClient) SearchServers(query string, opts ListServersOptions) error {
+	if !opts.JSON {
+		fmt.Printf("=== Search Servers (Query: %s) ===\n", query)
+	}
+
+	servers, metadata, statusCode, body, err := c.fetchServerList("/v0/servers?search="+url.QueryEscape(query), opts)
+	if err != nil {
+		return err
+	}
+
+	if !opts.JSON {
+		fmt.Printf("Status Code: %d\n", statusCode)
+	}
+
+	if statusCode != 200 {
+		if opts.JSON {
+			return printJSONError(statusCode, body)
+		}
+		return &APIError{Op: "search servers", StatusCode: statusCode, Body: body}
+	}
+
+	if err := c.writeToSinks(serverListDocument(servers, metadata)); err != nil {
+		return err
+	}
+	if opts.JSON {
+		return printServerListJSON(servers, metadata)
+	}
+	printServerList(servers, metadata)
+	return nil
+}
+
+// FindServersByRepository lists every server whose repository ID (e.g. "owner/repo") matches repo, case-insensitively
+func (c *MCPXClient) FindServersByRepository(repo string, jsonOutput bool) error {
+	if !jsonOutput {
+		fmt.Printf("=== Find Servers (Repository: %s) ===\n", repo)
+	}
+
+	matches := []Server{}
+	err := c.iterateServers("", 100, func(server Server) error {
+		if strings.EqualFold(server.Repository.ID, repo) {
+			matches = append(matches, server)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printServerListJSON(matches, Metadata{Count: len(matches)})
+	}
+
+	if len(matches) == 0 {
+		fmt.Println("No servers found.")
+		return nil
+	}
+	for _, server := range matches {
+		fmt.Printf("%s %s (ID: %s)\n", server.Name, server.Version, server.GetServerID())
+	}
+	return nil
+}
+
+// ListServerVersions lists every published version of a server
+func (c *MCPXClient) ListServerVersions(serverName string, opts ListServersOptions) error {
+	if !opts.JSON {
+		fmt.Printf("=== Server Versions (Name: %s) ===\n", serverName)
+	}
+
+	servers, metadata, statusCode, body, err := c.fetchServerList(serverVersionsEndpoint(serverName), opts)
+	if err != nil {
+		return err
+	}
+
+	if !opts.JSON {
+		fmt.Printf("Status Code: %d\n", statusCode)
+	}
+
+	if statusCode != 200 {
+		if opts.JSON {
+			return printJSONError(statusCode, body)
+		}
+		return &APIError{Op: "list versions", StatusCode: statusCode, Body: body}
+	}
+
+	if opts.Order != "" {
+		sortVersions(servers, opts.Order)
+	}
+
+	if opts.JSON {
+		return printServerListJSON(servers, metadata)
+	}
+
+	if len(servers) == 0 {
+		fmt.Println("No versions found.")
+		return nil
+	}
+
+	fmt.Printf("Total Versions: %d\n", len(servers))
+	if metadata.NextCursor != "" {
+		fmt.Printf("Next Cursor: %s\n", metadata.NextCursor)
+	}
+	for _, server := range servers {
+		line := fmt.Sprintf("  %s", server.Version)
+		if server.Status != "" {
+			line += fmt.Sprintf(" (%s)", server.Status)
+		}
+		if isLatest(server) {
+			line += " [latest]"
+		}
+		fmt.Println(line)
+	}
+	if opts.Order == VersionOrderDesc && !isLatest(servers[0]) {
+		for _, server := range servers {
+			if isLatest(server) {
+				fmt.Printf("Note: the registry marks %s as latest, but %s sorts higher\n", server.Version, servers[0].Version)
+			}
+		}
+	}
+	return nil
+}
+
+// serverVersionsEndpoint builds the endpoint listing the versions of a server
+func serverVersionsEndpoint(serverName string) string {
+	// URL encode the server name for the API (use PathEscape for path segments)
+	// Note: We need to double-encode slashes because Go's HTTP server decodes %2F to / before routing
+	encodedName := url.PathEscape(serverName)
+	// Double-encode the % in %2F to %252F so it survives Go's URL decoding
+	encodedName = strings.ReplaceAll(encodedName, "%2F", "%252F")
+	return "/v0/servers/" + encodedName + "/versions"
+}
+
+// serverEndpoint builds the endpoint of a specific server version
+func serverEndpoint(serverName, version string) string {
+	return serverVersionsEndpoint(serverName) + "/" + url.PathEscape(version)
+}
+
+// ServerExists checks whether a server ID exists with a HEAD request, without downloading the server
+func (c *MCPXClient) ServerExists(serverID string) (bool, error) {
+	resp, err := c.makeRequest("HEAD", "/v0/servers/"+url.PathEscape(serverID), nil, "")
+	if err != nil {
+		return false, fmt.Errorf("exists request failed: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, &APIError{Op: "exists request", StatusCode: resp.StatusCode}
+	}
+}
+
+// parseServerDetail parses a server detail response in either the wrapper or the legacy format
+func parseServerDetail(body []byte) (ServerDetail, error) {
+	var serverDetail ServerDetail
+
+	// Try new wrapper format first; legacy bodies may also carry "_meta", so require the "server" key
+	var probe struct {
+		Server json.RawMessage `json:"server"`
+	}
+	var detailWrapper ServerDetailWrapper
+	if err := json.Unmarshal(body, &probe); err == nil && len(probe.Server) > 0 {
+		if err := json.Unmarshal(body, &detailWrapper); err != nil {
+			return serverDetail, err
+		}
+		serverDetail = detailWrapper.Server
+		official := detailWrapper.RegistryMeta.official()
+		// Extract server ID from wrapper metadata; it stays empty when the registry sent none
+		if official != nil && official.ServerID != "" {
+			serverDetail.noteEmbeddedID(serverDetail.ID, official.ServerID)
+			serverDetail.ID = official.ServerID
+		}
+		// Keep the registry-managed dates and latest flag for display
+		if serverDetail.Meta == nil && official != nil {
+			serverDetail.Meta = &ServerMeta{Official: official}
+		}
+		return serverDetail, nil
+	}
+
+	// Try legacy format
+	if err := json.Unmarshal(body, &serverDetail); err != nil {
+		return serverDetail, err
+	}
+	if serverDetail.Meta != nil && serverDetail.Meta.Official != nil {
+		serverDetail.noteEmbeddedID(serverDetail.ID, serverDetail.Meta.Official.ServerID)
+	}
+	return serverDetail, nil
+}
+
+// fetchServerDetail fetches the latest version of a server by name.
+// The parsed detail is only returned for a 200 response; the status code and raw body are always returned.
+func (c *MCPXClient) fetchServerDetail(serverName string) (*ServerDetail, int, []byte, error) {
+	return c.fetchServerVersion(serverName, "latest")
+}
+
+// fetchServerVersion is fetchServerDetail for a specific version, or "latest"
+func (c *MCPXClient) fetchServerVersion(serverName, version string) (*ServerDetail, int, []byte, error) {
+	resp, err := c.makeRequest("GET", serverEndpoint(serverName, version), nil, "")
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("get server request failed: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return nil, resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, resp.StatusCode, body, nil
+	}
+
+	serverDetail, err := parseServerDetail(body)
+	if err != nil {
+		return nil, resp.StatusCode, body, fmt.Errorf("failed to parse response: %w", err)
+	}
+	c.warnIDMismatches(serverDetail.Server)
+
+	return &serverDetail, resp.StatusCode, body, nil
+}
+
+// watchStyle is what watchLoop prints before each run
+type watchStyle int
+
+const (
+	// watchClear clears the screen and prints a refresh header
+	watchClear watchStyle = iota
+	// watchTimestamp appends a timestamp line (--no-clear)
+	watchTimestamp
+	// watchPlain prints nothing, so --json output stays a stream of JSON documents
+	watchPlain
+)
+
+// watchLoop runs fn immediately and then once per interval until ctx is cancelled.
+// What is printed before each run depends on style. Errors from fn are reported and do not stop the loop.
+func watchLoop(ctx context.Context, interval time.Duration, style watchStyle, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		switch style {
+		case watchClear:
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Every %s: refreshed at %s (Ctrl-C to stop)\n\n", interval, time.Now().Format(time.RFC3339))
+		case watchTimestamp:
+			fmt.Printf("\n### %s ###\n", time.Now().Format(time.RFC3339))
+		}
+
+		if err := fn(); err != nil && !errors.Is(err, errJSONErrorWritten) {
+			log.Printf("Error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetServerOptions controls how server prints a server
+type GetServerOptions struct {
+	JSON bool
+	// PreferRegistry keeps only the install command of the first package of this registry type
+	PreferRegistry string
+	// Latest resolves the latest version from the versions listing instead of relying on /versions/latest
+	Latest bool
+}
+
+// GetServer prints the latest version of a server. opts.PreferRegistry limits the install commands of the
+// text output to one package of that registry type.
+func (c *MCPXClient) GetServer(serverName string, opts GetServerOptions) error {
+	jsonOutput, preferRegistry := opts.JSON, opts.PreferRegistry
+	version := "latest"
+	if opts.Latest {
+		resolved, err := c.resolveLatestVersion(serverName)
+		if err != nil {
+			return err
+		}
+		version = resolved
+		if jsonOutput {
+			c.logger.Info(fmt.Sprintf("Resolved latest version: %s", version))
+		}
+	}
+
+	if !jsonOutput {
+		fmt.Printf("=== Get Server Details (Name: %s) ===\n", serverName)
+		if opts.Latest {
+			fmt.Printf("Resolved latest version: %s\n", version)
+		}
+		fmt.Printf("Request URL: %s%s\n", c.baseURL, serverEndpoint(serverName, version))
+	}
+
+	detail, statusCode, body, err := c.fetchServerVersion(serverName, version)
+	if err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		fmt.Printf("Status Code: %d\n", statusCode)
+	}
+
+	if statusCode == 200 {
+		serverDetail := *detail
+		if err := c.writeToSinks(serverDetail); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			prettyJSON, err := json.MarshalIndent(serverDetail, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+			fmt.Println(string(prettyJSON))
+		} else {
+			printServerDetail(serverDetail)
+			hints, err := selectInstallHints(serverDetail.Packages, preferRegistry)
+			if err != nil {
+				return err
+			}
+			printInstallHints(hints)
+		}
+	} else {
+		if jsonOutput {
+			return printJSONError(statusCode, body)
+		}
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	return nil
+}
+
+// resolveLatestVersion returns the version the versions listing marks as latest. When the registry marks none,
+// the highest version is used and a warning says so.
+func (c *MCPXClient) resolveLatestVersion(serverName string) (string, error) {
+	versions, _, err := c.fetchAllPages(serverVersionsEndpoint(serverName), "", 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to list versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("server %s has no versions", serverName)
+	}
+	for _, server := range versions {
+		if isLatest(server) {
+			return server.Version, nil
+		}
+	}
+	sortVersions(versions, VersionOrderDesc)
+	c.logger.Warn(fmt.Sprintf("no version of %s is marked latest; using the highest version %s", serverName, versions[0].Version))
+	return versions[0].Version, nil
+}
+
+// printServerDetail prints the metadata, packages and remotes of a server as text
+func printServerDetail(serverDetail ServerDetail) {
+	fmt.Printf("Name: %s\n", serverDetail.Name)
+	if serverID := serverDetail.GetServerID(); serverID != "" {
+		fmt.Printf("Server ID: %s\n", serverID)
+	}
+	if versionID := serverDetail.GetVersionID(); versionID != "" {
+		fmt.Printf("Version ID: %s\n", versionID)
+	}
+	fmt.Printf("Description: %s\n", serverDetail.Description)
+	if serverDetail.Status != "" {
+		fmt.Printf("Status: %s\n", serverDetail.Status)
+	}
+	fmt.Printf("Repository: %s (%s)\n", serverDetail.Repository.URL, serverDetail.Repository.Source)
+	fmt.Printf("Version: %s\n", serverDetail.Version)
+	if serverDetail.Meta != nil && serverDetail.Meta.Official != nil {
+		official := serverDetail.Meta.Official
+		if official.PublishedAt != "" {
+			fmt.Printf("Published: %s\n", official.PublishedAt)
+		}
+		if official.UpdatedAt != "" {
+			fmt.Printf("Last Updated: %s\n", official.UpdatedAt)
+		}
+		if official.IsLatest {
+			fmt.Printf("Latest: yes\n")
+		}
+	}
+	if len(serverDetail.Packages) > 0 {
+		fmt.Printf("\nPackages:\n")
+		printPackages(serverDetail.Packages)
+	}
+	if len(serverDetail.Remotes) > 0 {
+		fmt.Printf("\nRemotes:\n")
+		printRemotes(serverDetail.Remotes)
+	}
+}
+
+// printRemotes prints the transport, URL and headers of each remote, indented under a heading
+func printRemotes(remotes []Remote) {
+	for i, remote := range remotes {
+		fmt.Printf("  Remote %d:\n", i+1)
+		transportType := remote.Type
+		if transportType == "" {
+			transportType = "unknown"
+		}
+		fmt.Printf("    Transport: %s\n", transportType)
+		fmt.Printf("    URL: %s\n", remote.URL)
+		if len(remote.Headers) > 0 {
+			fmt.Printf("    Headers:\n")
+			for _, header := range remote.Headers {
+				required := "optional"
+				if header.IsRequired {
+					required = "required"
+				}
+				line := fmt.Sprintf("      - %s", header.Name)
+				if header.Value != "" && !header.IsSecret {
+					line += ": " + header.Value
+				}
+				line += fmt.Sprintf(" (%s)", required)
+				if header.Description != "" {
+					line += " " + header.Description
+				}
+				fmt.Println(line)
+			}
+		}
+	}
+}
+
+// printPackages prints each package with its environment variables and arguments, indented under a heading
+func printPackages(packages []Package) {
+	for i, pkg := range packages {
+		fmt.Printf("  Package %d:\n", i+1)
+		fmt.Printf("    Registry: %s\n", pkg.RegistryType)
+		fmt.Printf("    Identifier: %s\n", pkg.Identifier)
+		fmt.Printf("    Version: %s\n", pkg.Version)
+		if pkg.WheelURL != "" {
+			fmt.Printf("    Wheel URL: %s\n", pkg.WheelURL)
+		}
+		if pkg.BinaryURL != "" {
+			fmt.Printf("    Binary URL: %s\n", pkg.BinaryURL)
+		}
+		if pkg.RuntimeHint != "" {
+			fmt.Printf("    Runtime Hint: %s\n", pkg.RuntimeHint)
+		}
+		if pkg.Transport.Type != "" {
+			fmt.Printf("    Transport: %s\n", pkg.Transport.Type)
+		}
+		if len(pkg.EnvironmentVariables) > 0 {
+			fmt.Printf("    Environment Variables:\n")
+			for _, env := range pkg.EnvironmentVariables {
+				required := "optional"
+				if env.IsRequired {
+					required = "required"
+				}
+				fmt.Printf("      - %s: %s (%s)\n", env.Name, env.Description, required)
+			}
+		}
+		printArguments("Runtime Arguments", pkg.RuntimeArguments)
+		printArguments("Package Arguments", pkg.PackageArguments)
+	}
+}
+
+// printArguments prints the runtime or package arguments of a package under the given heading
+func printArguments(heading string, args []Argument) {
+	if len(args) == 0 {
+		return
+	}
+	fmt.Printf("    %s:\n", heading)
+	for _, arg := range args {
+		required := "optional"
+		if arg.IsRequired {
+			required = "required"
+		}
+		nameInfo := arg.Type
+		if arg.Name != "" {
+			nameInfo = fmt.Sprintf("%s:%s", arg.Type, arg.Name)
+		}
+		fmt.Printf("      - %s (%s): %s\n", nameInfo, required, arg.Description)
+	}
+}
+
+// fetchLatestDetail fetches the latest version of a server, turning any non-200 answer into an APIError
+func (c *MCPXClient) fetchLatestDetail(serverName string) (*ServerDetail, error) {
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != 200 {
+		return nil, &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+	return detail, nil
+}
+
+// printIndentedJSON prints v as indented JSON on stdout
+func printIndentedJSON(v any) error {
+	prettyJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format JSON: %w", err)
+	}
+	fmt.Println(string(prettyJSON))
+	return nil
+}
+
+// ListServerPackages prints only the packages of a server, the part needed to install it
+func (c *MCPXClient) ListServerPackages(serverName string, jsonOutput bool) error {
+	detail, err := c.fetchLatestDetail(serverName)
+	if err != nil {
+		return err
+	}
+
+	packages := detail.Packages
+	if jsonOutput {
+		if packages == nil {
+			packages = []Package{}
+		}
+		return printIndentedJSON(packages)
+	}
+
+	fmt.Printf("=== Packages (Name: %s, Version: %s) ===\n", detail.Name, detail.Version)
+	if len(packages) == 0 {
+		fmt.Println("No packages; the server may only be reachable through its remotes")
+		return nil
+	}
+	printPackages(packages)
+	return nil
+}
+
+// ListServerRemotes prints only the remotes of a server, the endpoints for connecting to it without installing
+func (c *MCPXClient) ListServerRemotes(serverName string, jsonOutput bool) error {
+	detail, err := c.fetchLatestDetail(serverName)
+	if err != nil {
+		return err
+	}
+
+	remotes := detail.Remotes
+	if jsonOutput {
+		if remotes == nil {
+			remotes = []Remote{}
+		}
+		return printIndentedJSON(remotes)
+	}
+
+	fmt.Printf("=== Remotes (Name: %s, Version: %s) ===\n", detail.Name, detail.Version)
+	if len(remotes) == 0 {
+		fmt.Println("No remotes; the server has to be installed from one of its packages")
+		return nil
+	}
+	printRemotes(remotes)
+	return nil
+}
+
+// installCommand derives a best-effort command for installing or running a package.
+// The command is a heuristic based on the registry type and runtime hint, not data provided by the registry.
+func installCommand(pkg Package) string {
+	if pkg.Identifier == "" {
+		return ""
+	}
+
+	switch pkg.RegistryType {
+	case RegistryTypeNPM:
+		runner := RuntimeHintNPX
+		if pkg.RuntimeHint != "" {
+			runner = pkg.RuntimeHint
+		}
+		return fmt.Sprintf("%s %s", runner, versionedIdentifier(pkg.Identifier, "@", pkg.Version))
+	case RegistryTypePyPI:
+		if pkg.RuntimeHint == "" || pkg.RuntimeHint == RuntimeHintUVX {
+			return fmt.Sprintf("uvx %s", versionedIdentifier(pkg.Identifier, "@", pkg.Version))
+		}
+		return fmt.Sprintf("pip install %s", versionedIdentifier(pkg.Identifier, "==", pkg.Version))
+	case RegistryTypeWheel:
+		if pkg.WheelURL != "" {
+			return fmt.Sprintf("pip install %s", pkg.WheelURL)
+		}
+		return fmt.Sprintf("pip install %s", pkg.Identifier)
+	case RegistryTypeDocker, RegistryTypeOCI:
+		return fmt.Sprintf("docker run -i --rm %s", versionedIdentifier(pkg.Identifier, ":", pkg.Version))
+	case RegistryTypeNuGet:
+		return fmt.Sprintf("dnx %s", versionedIdentifier(pkg.Identifier, "@", pkg.Version))
+	case RegistryTypeBinary:
+		if pkg.BinaryURL != "" {
+			return fmt.Sprintf("curl -fLO %s", pkg.BinaryURL)
+		}
+		if strings.HasPrefix(pkg.Identifier, "http://") || strings.HasPrefix(pkg.Identifier, "https://") {
+			return fmt.Sprintf("curl -fLO %s", pkg.Identifier)
+		}
+	}
+
+	if pkg.RuntimeHint != "" {
+		return fmt.Sprintf("%s %s", pkg.RuntimeHint, pkg.Identifier)
+	}
+
+	return ""
+}
+
+// versionedIdentifier appends the version to the identifier using the given separator, if a version is set
+func versionedIdentifier(identifier, separator, version string) string {
+	if version == "" {
+		return identifier
+	}
+	return identifier + separator + version
+}
+
+// selectInstallHints derives an install command for every package that has one. With preferRegistry set,
+// only the first package of that registry type is kept, and it is an error when there is none.
+func selectInstallHints(packages []Package, preferRegistry string) ([]InstallHint, error) {
+	hints := []InstallHint{}
+	var registries []string
+	for _, pkg := range packages {
+		command := installCommand(pkg)
+		if command == "" {
+			continue
+		}
+		hint := InstallHint{RegistryType: pkg.RegistryType, Identifier: pkg.Identifier, Command: command}
+		if preferRegistry == "" {
+			hints = append(hints, hint)
+		} else if strings.EqualFold(pkg.RegistryType, preferRegistry) {
+			return []InstallHint{hint}, nil
+		}
+		registries = append(registries, pkg.RegistryType)
+	}
+	if preferRegistry != "" {
+		if len(registries) == 0 {
+			return nil, fmt.Errorf("no %s package with an install command", preferRegistry)
+		}
+		return nil, fmt.Errorf("no %s package with an install command (available: %s)", preferRegistry, strings.Join(registries, ", "))
+	}
+	return hints, nil
+}
+
+// printInstallHints prints install commands labeled by their package registry
+func printInstallHints(hints []InstallHint) {
+	if len(hints) == 0 {
+		return
+	}
+	fmt.Printf("\nInstall Commands (heuristic):\n")
+	for _, hint := range hints {
+		fmt.Printf("  %s: %s\n", hint.RegistryType, hint.Command)
+	}
+}
+
+// ServerDescription is the composite document printed by describe --json
+type ServerDescription struct {
+	Server          ServerDetail  `json:"server"`
+	InstallCommands []InstallHint `json:"installCommands"`
+	Versions        []Server      `json:"versions"`
+}
+
+// InstallHint is the heuristic install command derived for one package
+type InstallHint struct {
+	RegistryType string `json:"registryType"`
+	Identifier   string `json:"identifier"`
+	Command      string `json:"command"`
+}
+
+// DescribeServer prints everything about a server in one report: the latest detail, an install command per
+// package and the full version history, newest first
+func (c *MCPXClient) DescribeServer(serverName string, jsonOutput bool, preferRegistry string) error {
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return err
+	}
+	if statusCode != 200 {
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	versions, _, err := c.fetchAllPages(serverVersionsEndpoint(serverName), "", 0)
+	if err != nil {
+		return fmt.Errorf("failed to list versions: %w", err)
+	}
+	sortVersions(versions, VersionOrderDesc)
+
+	hints, err := selectInstallHints(detail.Packages, preferRegistry)
+	if err != nil {
+		return err
+	}
+	description := ServerDescription{Server: *detail, InstallCommands: hints, Versions: versions}
+	if description.Versions == nil {
+		description.Versions = []Server{}
+	}
+
+	if jsonOutput {
+		prettyJSON, err := json.MarshalIndent(description, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(prettyJSON))
+		return nil
+	}
+
+	fmt.Printf("=== Describe Server (Name: %s) ===\n", serverName)
+	printServerDetail(*detail)
+	printInstallHints(description.InstallCommands)
+
+	fmt.Printf("\nVersion History (%d):\n", len(versions))
+	for _, server := range versions {
+		line := fmt.Sprintf("  %s", server.Version)
+		if published := publishedAt(server); published != "" {
+			line += fmt.Sprintf("  published %s", published)
+		}
+		if server.Status != "" {
+			line += fmt.Sprintf(" (%s)", server.Status)
+		}
+		if isLatest(server) {
+			line += " [latest]"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// GetServerInstallCommand prints only the derived install command of a server, for easy copy-paste. A server
+// with several packages gets one line per package, prefixed with its registry, unless preferRegistry picks one.
+func (c *MCPXClient) GetServerInstallCommand(serverName, preferRegistry string) error {
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 200 {
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	hints, err := selectInstallHints(detail.Packages, preferRegistry)
+	if err != nil {
+		return fmt.Errorf("server %s has %w", serverName, err)
+	}
+	if len(hints) == 0 {
+		return fmt.Errorf("no install command could be derived for server %s", serverName)
+	}
+
+	if len(hints) == 1 {
+		fmt.Println(hints[0].Command)
+		return nil
+	}
+	for _, hint := range hints {
+		fmt.Printf("%s: %s\n", hint.RegistryType, hint.Command)
+	}
+	return nil
+}
+
+// formatServerShort summarizes a server in at most three lines: name and version, description, repository
+func formatServerShort(server ServerDetail) string {
+	header := server.Name + " " + server.Version
+	if server.Status != "" && server.Status != "active" {
+		header += " [" + server.Status + "]"
+	}
+	lines := []string{header}
+	if server.Description != "" {
+		lines = append(lines, server.Description)
+	}
+	if server.Repository.URL != "" {
+		lines = append(lines, server.Repository.URL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetServerShort prints a compact summary of the latest version of a server
+func (c *MCPXClient) GetServerShort(serverName string) error {
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 200 {
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	fmt.Println(formatServerShort(*detail))
+	return nil
+}
+
+// OpenServerRepository opens the repository URL of a server in the default browser, or only prints it
+func (c *MCPXClient) OpenServerRepository(serverName string, printOnly bool) error {
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 200 {
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	repoURL := detail.Repository.URL
+	if repoURL == "" {
+		return fmt.Errorf("%s has no repository URL", serverName)
+	}
+	if printOnly {
+		fmt.Println(repoURL)
+		return nil
+	}
+
+	cmd, err := browserCommand(runtime.GOOS, repoURL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Opening %s\n", repoURL)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open a browser (use --print to show the URL instead): %w", err)
+	}
+	return nil
+}
+
+// browserCommand returns the command that opens target in the default browser on goos. The repository URL
+// comes from the registry, so only http and https URLs are opened; anything else could name a local file
+// or program for the opener to run.
+func browserCommand(goos, target string) (*exec.Cmd, error) {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("refusing to open %q: not an http or https URL (use --print to show it)", target)
+	}
+	target = u.String()
+	switch goos {
+	case "darwin":
+		return exec.Command("open", target), nil
+	case "windows":
+		// cmd /c start would interpret shell metacharacters such as & in the URL
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", target), nil
+	default:
+		return exec.Command("xdg-open", target), nil
+	}
+}
+
+// Output formats accepted by --tee. YAML is reserved; this build has no YAML encoder.
+const (
+	OutputFormatJSON = "json"
+	OutputFormatYAML = "yaml"
+)
+
+// teeCommands are the commands that hand their result to --tee sinks
+var teeCommands = map[string]bool{"servers": true, "search": true, "server": true}
+
+// outputSink is an extra destination for a command's result, given as --tee <format>:<path>
+type outputSink struct {
+	Format string
+	Path   string
+}
+
+// parseOutputSink parses a --tee value such as json:out.json
+func parseOutputSink(spec string) (outputSink, error) {
+	format, path, ok := strings.Cut(spec, ":")
+	if !ok || path == "" {
+		return outputSink{}, fmt.Errorf("invalid --tee %q (expected <format>:<path>, e.g. json:out.json)", spec)
+	}
+	switch format {
+	case OutputFormatJSON:
+	case OutputFormatYAML:
+		return outputSink{}, fmt.Errorf("--tee format yaml is not supported by this build")
+	default:
+		return outputSink{}, fmt.Errorf("unsupported --tee format %q (expected json)", format)
+	}
+	return outputSink{Format: format, Path: path}, nil
+}
+
+// writeToSinks writes v, the document the command prints with --json, to every --tee sink. Existing
+// files are replaced, as with tee(1).
+func (c *MCPXClient) writeToSinks(v any) error {
+	if len(c.sinks) == 0 {
+		return nil
+	}
+	c.sinksWritten = true
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format JSON: %w", err)
+	}
+	data = append(data, '\n')
+	for _, sink := range c.sinks {
+		if err := writeFile(sink.Path, data, true); err != nil {
+			return fmt.Errorf("--tee: %w", err)
+		}
+		c.logger.Debug("wrote --tee output", "format", sink.Format, "path", sink.Path)
+	}
+	return nil
+}
+
+// writeFile writes a file the user asked for, such as an exported manifest. Missing parent directories are
+// created, and an existing file is only replaced when force is set.
+func writeFile(path string, data []byte, force bool) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists; pass --force to overwrite it", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// CopyServer derives a ready-to-publish manifest from the latest published version of a server,
+// bumping its version and clearing registry-managed fields
+func (c *MCPXClient) CopyServer(serverName, newVersion, outputFile string, force bool) error {
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != 200 {
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	oldVersion := detail.Version
+	detail.Version = newVersion
+	data, err := manifestJSON(*detail)
+	if err != nil {
+		return err
+	}
+
+	if outputFile == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := writeFile(outputFile, data, force); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Copied %s %s -> %s into %s\n", serverName, oldVersion, newVersion, outputFile)
+	fmt.Printf("Publish it with: mcpx-cli publish %s\n", outputFile)
+	return nil
+}
+
+// RenameServer publishes the latest manifest of serverName under newName. The registry has no rename
+// operation, so this creates a new server entry; the old one is left in place for the caller to deprecate.
+func (c *MCPXClient) RenameServer(serverName, newName, token string, opts PublishOptions) error {
+	if newName == serverName {
+		return fmt.Errorf("new name must differ from %s", serverName)
+	}
+	if !strings.Contains(newName, "/") {
+		return fmt.Errorf("new name %q must have the form <namespace>/<name>", newName)
+	}
+
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return err
+	}
+	if statusCode != 200 {
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	detail.Name = newName
+	data, err := manifestJSON(*detail)
+	if err != nil {
+		return err
+	}
+
+	if !opts.JSON {
+		fmt.Printf("=== Rename Server %s -> %s ===\n", serverName, newName)
+	}
+	c.logger.Warn(fmt.Sprintf("the registry cannot rename servers; %s %s is published as a new entry and %s is left unchanged", newName, detail.Version, serverName))
+	if _, _, err := c.publishManifestData("rename of "+serverName, data, token, opts); err != nil {
+		return err
+	}
+	if !opts.JSON {
+		fmt.Printf("Retire the old entry with: mcpx-cli deprecate %s --reason \"renamed to %s\"\n", serverName, newName)
+	}
+	return nil
+}
+
+// manifestJSON encodes a server as a publishable manifest. Registry-managed fields are assigned on publish
+// and must not be sent back, so id, status and _meta are cleared.
+func manifestJSON(detail ServerDetail) ([]byte, error) {
+	detail.ID = ""
+	detail.Status = ""
+	detail.Meta = nil
+	data, err := json.MarshalIndent(detail, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server config: %w", err)
+	}
+	return data, nil
+}
+
+// ExportServer writes the latest manifest of a server to outputFile, or to stdout when it is empty
+func (c *MCPXClient) ExportServer(serverName, outputFile string, force bool) error {
+	detail, statusCode, body, err := c.fetchServerDetail(serverName)
+	if err != nil {
+		return err
+	}
+	if statusCode != 200 {
+		return &APIError{Op: "get server", StatusCode: statusCode, Body: body}
+	}
+
+	data, err := manifestJSON(*detail)
+	if err != nil {
+		return err
+	}
+	if outputFile == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := writeFile(outputFile, data, force); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Exported %s %s to %s\n", serverName, detail.Version, outputFile)
+	return nil
+}
+
+// sanitizeFileName turns a server name into a safe file name by replacing every character other than
+// letters, digits, '.', '-' and '_' with '_', e.g. io.github.user/server becomes io.github.user_server
+func sanitizeFileName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	sanitized = strings.TrimLeft(sanitized, ".")
+	if sanitized == "" {
+		sanitized = "_"
+	}
+	return sanitized
+}
+
+// ExportAllServers writes the manifest of every server in the registry to <dir>/<sanitized-name>.json.
+// With detailed set, each server's full ServerDetail is fetched so packages and remotes are included even
+// when the listing omits them. When two entries map to the same file (several versions of a server, or
+// names that only differ in replaced characters), the version and then a counter are appended. Files left
+// by an earlier export are only replaced when force is set.
+func (c *MCPXClient) ExportAllServers(dir string, detailed, force bool) error {
+	fmt.Printf("=== Export All Servers (Directory: %s) ===\n", dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	used := map[string]bool{}
+	exported := 0
+	progress := c.newProgress("Exporting", 0)
+	err := c.iterateServers("", 0, func(server Server) error {
+		detail := ServerDetail{Server: server}
+		if detailed {
+			var err error
+			if detail, _, err = c.fetchListedServerDetail(server); err != nil {
+				return err
+			}
+		}
+
+		base := sanitizeFileName(detail.Name)
+		if used[base] {
+			base = sanitizeFileName(detail.Name + "-" + detail.Version)
+		}
+		for i := 2; used[base]; i++ {
+			base = sanitizeFileName(fmt.Sprintf("%s-%s-%d", detail.Name, detail.Version, i))
+		}
+		used[base] = true
+
+		data, err := manifestJSON(detail)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, base+".json")
+		if err := writeFile(path, data, force); err != nil {
+			return err
+		}
+		exported++
+		progress.Clear()
+		fmt.Printf("[%d] %s %s -> %s\n", exported, detail.Name, detail.Version, path)
+		progress.Add(1)
+		return nil
+	})
+	progress.Done()
+	if err != nil {
+		return fmt.Errorf("export stopped after %d server(s): %w", exported, err)
+	}
+
+	fmt.Printf("✅ Exported %d server(s) to %s\n", exported, dir)
+	return nil
+}
+
+// semanticVersion is a parsed semantic version (https://semver.org)
+type semanticVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      string
+}
+
+// parseSemver parses a MAJOR.MINOR.PATCH[-prerelease][+build] version string
+func parseSemver(v string) (semanticVersion, error) {
+	var sv semanticVersion
+
+	rest := v
+	if i := strings.Index(rest, "+"); i >= 0 {
+		sv.Build = rest[i+1:]
+		rest = rest[:i]
+		if sv.Build == "" {
+			return sv, fmt.Errorf("invalid semantic version %q: empty build metadata", v)
+		}
+	}
+	if i := strings.Index(rest, "-"); i >= 0 {
+		pre := rest[i+1:]
+		rest = rest[:i]
+		if pre == "" {
+			return sv, fmt.Errorf("invalid semantic version %q: empty pre-release", v)
+		}
+		sv.Prerelease = strings.Split(pre, ".")
+		for _, id := range sv.Prerelease {
+			if id == "" {
+				return sv, fmt.Errorf("invalid semantic version %q: empty pre-release identifier", v)
+			}
+		}
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return sv, fmt.Errorf("invalid semantic version %q: expected MAJOR.MINOR.PATCH", v)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || part == "" || (len(part) > 1 && part[0] == '0') {
+			return sv, fmt.Errorf("invalid semantic version %q: %q is not a valid version number", v, part)
+		}
+		numbers[i] = n
+	}
+	sv.Major, sv.Minor, sv.Patch = numbers[0], numbers[1], numbers[2]
+
+	return sv, nil
+}
+
+// compareSemver orders two semantic versions by precedence, returning -1, 0 or 1. Build metadata is ignored.
+func compareSemver(a, b semanticVersion) int {
+	for _, pair := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+
+	// A release has higher precedence than its pre-releases
+	switch {
+	case len(a.Prerelease) == 0 && len(b.Prerelease) == 0:
+		return 0
+	case len(a.Prerelease) == 0:
+		return 1
+	case len(b.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.Prerelease) && i < len(b.Prerelease); i++ {
+		x, y := a.Prerelease[i], b.Prerelease[i]
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		switch {
+		case xErr == nil && yErr == nil:
+			if xn != yn {
+				return compareInts(xn, yn)
+			}
+		case xErr == nil:
+			return -1 // numeric identifiers sort before alphanumeric ones
+		case yErr == nil:
+			return 1
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return compareInts(len(a.Prerelease), len(b.Prerelease))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Version orders accepted by the versions command
+const (
+	VersionOrderAsc  = "asc"
+	VersionOrderDesc = "desc"
+)
+
+// sortVersions orders the versions of a server in place. When every version is a semantic version they are
+// compared by precedence; otherwise the whole list is ordered by publish date. The sort is stable.
+func sortVersions(servers []Server, order string) {
+	parsed := make(map[string]semanticVersion, len(servers))
+	allSemver := true
+	for _, server := range servers {
+		sv, err := parseSemver(server.Version)
+		if err != nil {
+			allSemver = false
+			break
+		}
+		parsed[server.Version] = sv
+	}
+
+	compare := func(a, b Server) int {
+		if allSemver {
+			return compareSemver(parsed[a.Version], parsed[b.Version])
+		}
+		return strings.Compare(publishedAt(a), publishedAt(b))
+	}
+	sort.SliceStable(servers, func(i, j int) bool {
+		if order == VersionOrderAsc {
+			return compare(servers[i], servers[j]) < 0
+		}
+		return compare(servers[i], servers[j]) > 0
+	})
+}
+
+// publishedAt returns the registry publish timestamp of a server in a sortable form, or ""
+func publishedAt(server Server) string {
+	if server.Meta == nil || server.Meta.Official == nil {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, server.Meta.Official.PublishedAt)
+	if err != nil {
+		return server.Meta.Official.PublishedAt
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func isLatest(server Server) bool {
+	return server.Meta != nil && server.Meta.Official != nil && server.Meta.Official.IsLatest
+}
+
+// repositoryConsistencyProblems reports an io.github.<owner>/* name whose repository is not a GitHub repository
+// of <owner>, which the registry is likely to reject. GitHub repositories under other namespaces are normal
+// (e.g. io.modelcontextprotocol/*) and are not reported.
+func repositoryConsistencyProblems(server ServerDetail) []string {
+	rest, ok := strings.CutPrefix(server.Name, "io.github.")
+	if !ok {
+		return nil
+	}
+	owner, _, _ := strings.Cut(rest, "/")
+
+	if server.Repository.Source != "github" {
+		return []string{fmt.Sprintf("name %s is in the io.github namespace, but repository.source is %q instead of \"github\"", server.Name, server.Repository.Source)}
+	}
+
+	repoOwner, _, _ := strings.Cut(server.Repository.ID, "/")
+	if repoOwner == "" {
+		if parsed, err := url.Parse(server.Repository.URL); err == nil {
+			repoOwner, _, _ = strings.Cut(strings.TrimPrefix(parsed.Path, "/"), "/")
+		}
+	}
+	if repoOwner != "" && !strings.EqualFold(repoOwner, owner) {
+		return []string{fmt.Sprintf("name %s belongs to GitHub user %s, but the repository belongs to %s", server.Name, owner, repoOwner)}
+	}
+	return nil
+}
+
+// validateServerDetail checks a server manifest for problems the registry would reject
+func validateServerDetail(server ServerDetail, allowNonSemver bool) []string {
+	var problems []string
+
+	if strings.TrimSpace(server.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if strings.TrimSpace(server.Description) == "" {
+		problems = append(problems, "description is required")
+	}
+	if strings.TrimSpace(server.Version) == "" {
+		problems = append(problems, "version is required")
+	} else if _, err := parseSemver(server.Version); err != nil && !allowNonSemver {
+		problems = append(problems, fmt.Sprintf("version: %v (use --allow-nonsemver to permit it)", err))
+	}
+	for i, pkg := range server.Packages {
+		if pkg.RegistryType == "" {
+			problems = append(problems, fmt.Sprintf("packages[%d].registryType is required", i))
+		}
+		if pkg.Identifier == "" {
+			problems = append(problems, fmt.Sprintf("packages[%d].identifier is required", i))
+		}
+	}
+
+	return problems
+}
+
+// ManifestFormatJSON is the manifest format reported when reading a server file
+const ManifestFormatJSON = "json"
+
+// readManifest reads a server manifest and checks that it is JSON, whatever the file extension. A syntax
+// error is reported with its line and column.
+func (c *MCPXClient) readManifest(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server file: %w", err)
+	}
+
+	var probe json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid JSON in server file: %w", prettyJSONError(data, err))
+	}
+	c.logger.Debug("detected manifest format", "file", path, "format", ManifestFormatJSON)
+	return data, nil
+}
+
+// jsonErrorContext is how many characters of a long line are shown on each side of a JSON error
+const jsonErrorContext = 60
+
+// prettyJSONError adds the line and column of a JSON syntax or type error in data to the message, followed
+// by the offending line with a caret under the position, so a typo in a hand-edited file is easy to find.
+// Other errors are returned unchanged.
+func prettyJSONError(data []byte, err error) error {
+	return prettyJSONErrorAt(data, 0, err)
+}
+
+// prettySubJSONError is prettyJSONError for an error decoding sub, a part of data such as its "server"
+// object, so the position is reported in data rather than in sub
+func prettySubJSONError(data, sub []byte, err error) error {
+	base := bytes.Index(data, sub)
+	if base < 0 {
+		return prettyJSONError(sub, err)
+	}
+	return prettyJSONErrorAt(data, base, err)
+}
+
+func prettyJSONErrorAt(data []byte, base int, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+	// Offsets count the bytes read, so the offending character is the one before
+	return jsonErrorAt(data, base+int(offset)-1, err)
+}
+
+// jsonErrorAt adds the line and column of byte pos in data and an excerpt of that line to err. A position
+// past the end of the input, or on whitespace, moves back to the last character that is not whitespace.
+func jsonErrorAt(data []byte, pos int, err error) error {
+	if len(data) == 0 {
+		return err
+	}
+	for pos > 0 && (pos >= len(data) || strings.ContainsRune(" \t\r\n", rune(data[pos]))) {
+		pos--
+	}
+	pos = max(pos, 0)
+
+	start := bytes.LastIndexByte(data[:pos], '\n') + 1
+	end := len(data)
+	if i := bytes.IndexByte(data[pos:], '\n'); i >= 0 {
+		end = pos + i
+	}
+	line := []rune(strings.TrimRight(string(data[start:end]), "\r"))
+	column := len([]rune(string(data[start:pos])))
+	lineNumber := bytes.Count(data[:start], []byte("\n")) + 1
+
+	// Show a window around the column of long lines, e.g. minified JSON
+	lo, hi := max(column-jsonErrorContext, 0), min(column+jsonErrorContext, len(line))
+	excerpt := string(line[lo:hi])
+	caretPrefix := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, string(line[lo:min(column, len(line))]))
+	if lo > 0 {
+		excerpt = "..." + excerpt
+		caretPrefix = "   " + caretPrefix
+	}
+	if hi < len(line) {
+		excerpt += "..."
+	}
+
+	gutter := strings.Repeat(" ", len(strconv.Itoa(lineNumber)))
+	return fmt.Errorf("%w at line %d, column %d:\n  %d | %s\n  %s | %s^", err, lineNumber, column+1, lineNumber, excerpt, gutter, caretPrefix)
+}
+
+// strictServerManifest is what --strict accepts: the fields the CLI models, plus the schema fields it passes
+// through without modeling. _meta holds free-form extensions and is not checked.
+type strictServerManifest struct {
+	ServerDetail
+	Schema     string                     `json:"$schema,omitempty"`
+	Title      string                     `json:"title,omitempty"`
+	WebsiteURL string                     `json:"websiteUrl,omitempty"`
+	Meta       map[string]json.RawMessage `json:"_meta,omitempty"`
+}
+
+// checkStrictManifest decodes a manifest, bare or wrapped in a PublishRequest, rejecting keys the CLI does not
+// know. Lenient decoding silently drops them, so a typo such as "repostiory" would lose data.
+func checkStrictManifest(data []byte) error {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return prettyJSONError(data, err)
+	}
+	var target interface{} = &strictServerManifest{}
+	if _, wrapped := probe["server"]; wrapped {
+		target = &struct {
+			Server     strictServerManifest   `json:"server"`
+			XPublisher map[string]interface{} `json:"x-publisher,omitempty"`
+		}{}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(target)
+	if err == nil {
+		return nil
+	}
+	// Unknown field errors carry no offset, so point at the first place the key is used
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if key, unquoteErr := strconv.Unquote(field); unquoteErr == nil {
+			if pos := jsonKeyOffset(data, key); pos >= 0 {
+				return fmt.Errorf("strict parsing failed: %w", jsonErrorAt(data, pos, err))
+			}
+		}
+	}
+	return fmt.Errorf("strict parsing failed: %w", prettyJSONError(data, err))
+}
+
+// jsonKeyOffset returns the offset of the first "key": in data, or -1
+func jsonKeyOffset(data []byte, key string) int {
+	quoted := []byte(strconv.Quote(key))
+	for from := 0; ; {
+		i := bytes.Index(data[from:], quoted)
+		if i < 0 {
+			return -1
+		}
+		pos := from + i
+		if rest := bytes.TrimLeft(data[pos+len(quoted):], " \t\r\n"); len(rest) > 0 && rest[0] == ':' {
+			return pos
+		}
+		from = pos + len(quoted)
+	}
+}
+
+// ValidateOptions controls the checks of ValidateServerFile
+type ValidateOptions struct {
+	// AllowNonSemver accepts versions that are not semantic versions
+	AllowNonSemver bool
+	// CheckURLs requests package download URLs to confirm they exist
+	CheckURLs bool
+	// ConsistencyChecks reports io.github.* names that do not match the repository
+	ConsistencyChecks bool
+	// Strict rejects keys the CLI does not know
+	Strict bool
+}
+
+// ValidateServerFile validates a server manifest locally without contacting the registry
+func (c *MCPXClient) ValidateServerFile(serverFile string, opts ValidateOptions) error {
+	fmt.Printf("=== Validate Server (File: %s) ===\n", serverFile)
+
+	data, err := c.readManifest(serverFile)
+	if err != nil {
+		return err
+	}
+	if opts.Strict {
+		if err := checkStrictManifest(data); err != nil {
+			return err
+		}
+	}
+
+	var serverDetail ServerDetail
+	if err := json.Unmarshal(data, &serverDetail); err != nil {
+		return fmt.Errorf("invalid server data in server file: %w", prettyJSONError(data, err))
+	}
+
+	problems := validateServerDetail(serverDetail, opts.AllowNonSemver)
+	if opts.ConsistencyChecks {
+		problems = append(problems, repositoryConsistencyProblems(serverDetail)...)
+	}
+	if opts.CheckURLs {
+		problems = append(problems, c.checkPackageURLs(serverDetail)...)
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Printf("❌ %s\n", problem)
+		}
+		return fmt.Errorf("%d validation problem(s) found", len(problems))
+	}
+
+	fmt.Println("✅ Server manifest is valid")
+	return nil
+}
+
+// RoundTripManifest decodes a manifest into ServerDetail, encodes it again and prints how the result differs
+// from the file: fields the CLI drops (-), changes (~) or adds (+). Dropped or changed fields mean the CLI
+// would lose data when it rewrites the manifest, e.g. in copy or interactive publish, and make it fail.
+func (c *MCPXClient) RoundTripManifest(serverFile string) error {
+	fmt.Printf("=== Round Trip (File: %s) ===\n", serverFile)
+
+	data, err := c.readManifest(serverFile)
+	if err != nil {
+		return err
+	}
+	server := json.RawMessage(data)
+	if schemaVersion, err := detectSchemaVersion(data); err != nil {
+		return err
+	} else if schemaVersion == SchemaVersionV2 {
+		var request struct {
+			Server json.RawMessage `json:"server"`
+		}
+		if err := json.Unmarshal(data, &request); err != nil {
+			return fmt.Errorf("invalid JSON in server file: %w", prettyJSONError(data, err))
+		}
+		server = request.Server
+	}
+
+	var serverDetail ServerDetail
+	if err := json.Unmarshal(server, &serverDetail); err != nil {
+		return fmt.Errorf("invalid server data in server file: %w", prettySubJSONError(data, server, err))
+	}
+	encoded, err := json.Marshal(serverDetail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal server: %w", err)
+	}
+
+	var before, after interface{}
+	if err := decodeJSONNumbers(server, &before); err != nil {
+		return err
+	}
+	if err := decodeJSONNumbers(encoded, &after); err != nil {
+		return err
+	}
+	diffs := diffJSON("", before, after)
+	lossy := 0
+	for _, d := range diffs {
+		fmt.Println(d)
+		if d.lossy() {
+			lossy++
+		}
+	}
+	if lossy > 0 {
+		return fmt.Errorf("round trip is lossy: %d field(s) dropped or changed", lossy)
+	}
+	if len(diffs) > 0 {
+		fmt.Println("✅ No data is lost; the other differences only add or omit empty and default values")
+	} else {
+		fmt.Println("✅ Manifest survives the round trip unchanged")
+	}
+	return nil
+}
+
+// decodeJSONNumbers decodes data keeping numbers as json.Number, so 1.0 and 1 are not told apart by float rounding
+func decodeJSONNumbers(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
+// jsonDiff is one difference found by diffJSON. Op is '-' for a field only in Before, '+' for one only in
+// After and '~' for a changed value.
+type jsonDiff struct {
+	Op     byte
+	Path   string
+	Before interface{}
+	After  interface{}
+}
+
+// lossy reports whether the difference loses data; omitting a false, empty or zero field does not
+func (d jsonDiff) lossy() bool {
+	return d.Op == '~' || (d.Op == '-' && !isZeroJSON(d.Before))
+}
+
+func (d jsonDiff) String() string {
+	switch d.Op {
+	case '-':
+		if !d.lossy() {
+			return fmt.Sprintf("- %s: %s (empty or default, no data lost)", d.Path, compactJSON(d.Before))
+		}
+		return fmt.Sprintf("- %s: %s", d.Path, compactJSON(d.Before))
+	case '+':
+		return fmt.Sprintf("+ %s: %s", d.Path, compactJSON(d.After))
+	default:
+		return fmt.Sprintf("~ %s: %s → %s", d.Path, compactJSON(d.Before), compactJSON(d.After))
+	}
+}
+
+// isZeroJSON reports whether a decoded JSON value is null, false, zero, "" or an empty list or object
+func isZeroJSON(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !value
+	case string:
+		return value == ""
+	case json.Number:
+		f, err := value.Float64()
+		return err == nil && f == 0
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+// diffJSON lists the differences between two decoded JSON values, in sorted path order
+func diffJSON(path string, before, after interface{}) []jsonDiff {
+	child := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(b)+len(a))
+		for key := range b {
+			keys = append(keys, key)
+		}
+		for key := range a {
+			if _, seen := b[key]; !seen {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		var diffs []jsonDiff
+		for _, key := range keys {
+			bv, inBefore := b[key]
+			av, inAfter := a[key]
+			switch {
+			case !inAfter:
+				diffs = append(diffs, jsonDiff{Op: '-', Path: child(key), Before: bv})
+			case !inBefore:
+				diffs = append(diffs, jsonDiff{Op: '+', Path: child(key), After: av})
+			default:
+				diffs = append(diffs, diffJSON(child(key), bv, av)...)
+			}
+		}
+		return diffs
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+		var diffs []jsonDiff
+		for i := 0; i < len(b) || i < len(a); i++ {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(a):
+				diffs = append(diffs, jsonDiff{Op: '-', Path: elementPath, Before: b[i]})
+			case i >= len(b):
+				diffs = append(diffs, jsonDiff{Op: '+', Path: elementPath, After: a[i]})
+			default:
+				diffs = append(diffs, diffJSON(elementPath, b[i], a[i])...)
+			}
+		}
+		return diffs
+	}
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return []jsonDiff{{Op: '~', Path: path, Before: before, After: after}}
+}
+
+// compactJSON formats a decoded JSON value on one line
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// checkPackageURLs sends a HEAD request to every wheelUrl and binaryUrl of the packages and returns a
+// problem for each one that does not answer 200. Reachable URLs are reported as they are checked.
+// Requests go through the client's transport, so proxy and timeout settings apply.
+func (c *MCPXClient) checkPackageURLs(server ServerDetail) []string {
+	var problems []string
+	check := func(field, rawURL string) {
+		if rawURL == "" {
+			return
+		}
+		req, err := http.NewRequest("HEAD", rawURL, nil)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s %s is not a valid URL: %v", field, rawURL, err))
+			return
+		}
+		req.Header.Set("User-Agent", "mcpx-cli/1.0")
+		req, cancel := c.attemptRequest(req)
+		defer cancel()
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s %s is unreachable: %v", field, rawURL, newRequestError(req.URL.Host, err)))
+			return
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			problems = append(problems, fmt.Sprintf("%s %s returned %s", field, rawURL, resp.Status))
+			return
+		}
+		fmt.Printf("✅ %s %s: %s\n", field, rawURL, resp.Status)
+	}
+
+	for i, pkg := range server.Packages {
+		check(fmt.Sprintf("packages[%d].wheelUrl", i), pkg.WheelURL)
+		check(fmt.Sprintf("packages[%d].binaryUrl", i), pkg.BinaryURL)
+	}
+	return problems
+}
+
+// Lint severities, in increasing order of importance
+const (
+	LintSeverityInfo    = "info"
+	LintSeverityWarning = "warning"
+	LintSeverityError   = "error"
+)
+
+var lintSeverityRank = map[string]int{
+	LintSeverityInfo:    1,
+	LintSeverityWarning: 2,
+	LintSeverityError:   3,
+}
+
+// lintFinding is an advisory problem reported by lintServerDetail
+type lintFinding struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// secretNameHints are substrings of environment variable and header names that usually hold credentials
+var secretNameHints = []string{"TOKEN", "SECRET", "PASSWORD", "API_KEY", "APIKEY", "PRIVATE_KEY", "CREDENTIAL", "AUTHORIZATION"}
+
+func looksSecret(name string) bool {
+	upper := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	for _, hint := range secretNameHints {
+		if strings.Contains(upper, hint) {
+			return true
+		}
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
+	return false
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+// isPinnedVersion reports whether a package version refers to a single release rather than a range or tag
+func isPinnedVersion(version string) bool {
+	v := strings.TrimSpace(version)
+	if v == "" || strings.EqualFold(v, "latest") {
+		return false
 	}
+	return !strings.ContainsAny(v, "^~*<>= |") && !strings.HasSuffix(v, ".x")
+}
 
-	fmt.Printf("Status Code: %d\n", resp.StatusCode)
+// lintServerDetail reports best-practice issues in a server manifest. Unlike validateServerDetail these
+// are opinions: the registry accepts the manifest either way.
+func lintServerDetail(server ServerDetail) []lintFinding {
+	var findings []lintFinding
+	add := func(code, severity, format string, args ...interface{}) {
+		findings = append(findings, lintFinding{Code: code, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
 
-	if resp.StatusCode == 200 {
-		var healthResp HealthResponse
-		if err := json.Unmarshal(body, &healthResp); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
+	if strings.TrimSpace(server.Description) == "" {
+		add("MCPX001", LintSeverityWarning, "description is empty")
+	}
+	if len(server.Packages) == 0 && len(server.Remotes) == 0 {
+		add("MCPX002", LintSeverityWarning, "server has no packages and no remotes, so clients cannot run it")
+	}
+	for i, pkg := range server.Packages {
+		for _, env := range pkg.EnvironmentVariables {
+			if looksSecret(env.Name) && !env.IsSecret {
+				add("MCPX003", LintSeverityError, "packages[%d] environment variable %s looks like a secret but isSecret is not set", i, env.Name)
+			}
 		}
-		fmt.Printf("Status: %s\n", healthResp.Status)
-		if healthResp.GitHubClientID != "" {
-			fmt.Printf("GitHub Client ID: %s\n", healthResp.GitHubClientID)
+		if !isPinnedVersion(pkg.Version) {
+			add("MCPX004", LintSeverityWarning, "packages[%d] version %q is not pinned to a single release", i, pkg.Version)
+		} else if server.Version != "" && pkg.Version != server.Version {
+			add("MCPX006", LintSeverityInfo, "packages[%d] version %s differs from server version %s", i, pkg.Version, server.Version)
 		}
-	} else {
-		fmt.Printf("Error: %s\n", string(body))
+	}
+	for i, remote := range server.Remotes {
+		for _, header := range remote.Headers {
+			if looksSecret(header.Name) && !header.IsSecret {
+				add("MCPX003", LintSeverityError, "remotes[%d] header %s looks like a secret but isSecret is not set", i, header.Name)
+			}
+		}
+	}
+	if strings.TrimSpace(server.Repository.URL) == "" {
+		add("MCPX005", LintSeverityInfo, "repository.url is empty")
 	}
 
-	return nil
+	return findings
 }
 
-func (c *MCPXClient) ListServers(cursor string, limit int, jsonOutput bool, detailed bool) error {
-	var params []string
+// LintServerFile prints best-practice findings for a server manifest. When failOn is a severity,
+// an error is returned if any finding is at least that severe.
+// With checkURLs, unreachable package download URLs are reported as MCPX007 errors.
+func (c *MCPXClient) LintServerFile(serverFile, failOn string, checkURLs bool) error {
+	fmt.Printf("=== Lint Server (File: %s) ===\n", serverFile)
 
-	if !jsonOutput {
-		fmt.Println("=== List Servers ===")
+	data, err := c.readManifest(serverFile)
+	if err != nil {
+		return err
 	}
 
-	endpoint := "/v0/servers"
-
-	if cursor != "" {
-		params = append(params, "cursor="+cursor)
+	var serverDetail ServerDetail
+	if err := json.Unmarshal(data, &serverDetail); err != nil {
+		return fmt.Errorf("invalid server data in server file: %w", prettyJSONError(data, err))
 	}
 
-	if limit > 0 {
-		params = append(params, "limit="+strconv.Itoa(limit))
+	findings := lintServerDetail(serverDetail)
+	if checkURLs {
+		for _, problem := range c.checkPackageURLs(serverDetail) {
+			findings = append(findings, lintFinding{Code: "MCPX007", Severity: LintSeverityError, Message: problem})
+		}
+	}
+	if len(findings) == 0 {
+		fmt.Println("✅ No lint findings")
+		return nil
 	}
 
-	if len(params) > 0 {
-		endpoint += "?" + strings.Join(params, "&")
+	failing := 0
+	for _, finding := range findings {
+		fmt.Printf("⚠️  %s [%s] %s\n", finding.Code, finding.Severity, finding.Message)
+		if failOn != "" && lintSeverityRank[finding.Severity] >= lintSeverityRank[failOn] {
+			failing++
+		}
 	}
+	fmt.Printf("\n%d finding(s)\n", len(findings))
 
-	resp, err := c.makeRequest("GET", endpoint, nil, "")
-	if err != nil {
-		return fmt.Errorf("list servers request failed: %w", err)
+	if failing > 0 {
+		return fmt.Errorf("%d finding(s) at or above %s severity", failing, failOn)
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
+	return nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+// PublishOptions holds the optional behaviour of PublishServer
+type PublishOptions struct {
+	// AllowNonSemver suppresses the warning for versions that are not semantic versions
+	AllowNonSemver bool
+	// IdempotencyKey is sent as the Idempotency-Key header; a random key is generated when empty
+	IdempotencyKey string
+	// Raw sends the file bytes verbatim, e.g. a pre-built PublishRequest with x-publisher extensions
+	Raw bool
+	// PublisherMeta entries are added to the x-publisher metadata of the request
+	PublisherMeta map[string]string
+	// JSON prints the outcome as a PublishResult document instead of text
+	JSON bool
+	// SchemaVersion forces the manifest to be read as v1 (bare manifest) or v2 (PublishRequest); empty detects it
+	SchemaVersion string
+	// NoConsistencyChecks skips the pre-flight warning about a namespace that does not match the repository
+	NoConsistencyChecks bool
+	// Strict rejects manifests with keys the CLI does not know instead of silently dropping them
+	Strict bool
+	// Overrides set server fields in memory before publishing, e.g. the version from a git tag
+	Overrides []fieldOverride
+	// PreviewFull prints the complete assembled request before the interactive publish confirmation
+	PreviewFull bool
+	// Quiet prints nothing about the outcome, e.g. while a bulk publish collects a JSON summary
+	Quiet bool
+	// NoNormalize sends the server object as written instead of tidying it with normalizeServer
+	NoNormalize bool
+	// AcceptDefaults answers every interactive prompt with its default, without reading stdin, and publishes
+	AcceptDefaults bool
+}
+
+// fieldOverride sets one field of a server manifest, e.g. --set repository.url=... or --set-version. Field is
+// a dotted path of JSON names; list elements are addressed by index, as in packages.0.version.
+type fieldOverride struct {
+	Field string
+	Value string
+}
+
+// applyFieldOverrides sets fields of a server object by dotted path. The value is converted to the type of
+// the field in ServerDetail (string, bool or number). Fields the CLI does not model keep the JSON type of
+// their current value, and a missing top-level one is added as a string. A path through a missing object
+// of the model creates it; any other missing path, a list index out of range, a value that does not
+// convert, or a path ending at an object or list is an error.
+func applyFieldOverrides(server json.RawMessage, overrides []fieldOverride) (json.RawMessage, error) {
+	if len(overrides) == 0 {
+		return server, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(server))
+	decoder.UseNumber()
+	var root map[string]interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid server object: %w", err)
 	}
+	if root == nil {
+		return nil, fmt.Errorf("server object is null")
+	}
+	for _, override := range overrides {
+		if err := setFieldPath(root, reflect.TypeOf(ServerDetail{}), override); err != nil {
+			return nil, fmt.Errorf("cannot set %s: %w", override.Field, err)
+		}
+	}
+	return json.Marshal(root)
+}
 
-	if !jsonOutput {
-		fmt.Printf("Status Code: %d\n", resp.StatusCode)
+// normalizedEnumFields are the fields whose values the registry only accepts in lower case, e.g. "npm" or
+// "streamable-http", as paths of JSON names where "*" stands for every list element
+var normalizedEnumFields = [][]string{
+	{"repository", "source"},
+	{"packages", "*", "registryType"},
+	{"packages", "*", "transport", "type"},
+	{"remotes", "*", "type"},
+}
+
+// normalizeServer tidies a server object before publishing so manifests written by different tools are sent
+// alike: string values are trimmed, empty lists are dropped (an empty list means the same as none), and
+// registry types, transport types and the repository source are lower-cased. Keys come out sorted. The _meta
+// extension is publisher data and is left alone.
+func normalizeServer(server json.RawMessage) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(server))
+	decoder.UseNumber()
+	var root map[string]interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid server object: %w", err)
 	}
+	if root == nil {
+		return nil, fmt.Errorf("server object is null")
+	}
+	normalizeValue(root)
+	for _, path := range normalizedEnumFields {
+		lowercasePath(root, path)
+	}
+	return json.Marshal(root)
+}
 
-	if resp.StatusCode == 200 {
-		// First try to unmarshal and check what format we have
-		var rawResponse map[string]interface{}
-		if err := json.Unmarshal(body, &rawResponse); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
+// normalizeValue trims the strings and drops the empty lists of a decoded JSON value, in place where possible
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeValue(v[i])
 		}
-
-		var servers []Server
-		var metadata Metadata
-
-		// Check if response has 'servers' array with wrapper format
-		if serversArray, ok := rawResponse["servers"].([]interface{}); ok && len(serversArray) > 0 {
-			if firstServer, ok := serversArray[0].(map[string]interface{}); ok {
-				if _, hasServerField := firstServer["server"]; hasServerField {
-					// New wrapper format: {"servers": [{"server": {...}, "x-io.modelcontextprotocol.registry": {...}}]}
-					var serversResp ServersResponse
-					if err := json.Unmarshal(body, &serversResp); err == nil {
-						for _, wrapper := range serversResp.Servers {
-							server := wrapper.Server
-							// Extract server ID from wrapper metadata and set it in the server object
-							if serverID := wrapper.GetServerID(); serverID != "" {
-								server.ID = serverID
-							}
-							servers = append(servers, server)
-						}
-						metadata = serversResp.Metadata
-					}
-				} else {
-					// Legacy format: {"servers": [{"name": "...", "_meta": {...} OR "id": "..."}]}
-					var legacyResp LegacyServersResponse
-					if err := json.Unmarshal(body, &legacyResp); err == nil {
-						// Server IDs are now extracted automatically via the _meta field in the Server struct
-						servers = legacyResp.Servers
-						metadata = legacyResp.Metadata
-					}
-				}
+	case map[string]interface{}:
+		for key, child := range v {
+			if key == "_meta" {
+				continue
 			}
-		} else {
-			// Fallback: try simple legacy format without servers array inspection
-			var legacyResp LegacyServersResponse
-			if err := json.Unmarshal(body, &legacyResp); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
+			if list, ok := child.([]interface{}); ok && len(list) == 0 {
+				delete(v, key)
+				continue
 			}
-			servers = legacyResp.Servers
-			metadata = legacyResp.Metadata
+			v[key] = normalizeValue(child)
+		}
+	}
+	return value
+}
+
+// lowercasePath lower-cases the string values found at path below node
+func lowercasePath(node interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	switch v := node.(type) {
+	case []interface{}:
+		if path[0] == "*" {
+			for _, element := range v {
+				lowercasePath(element, path[1:])
+			}
+		}
+	case map[string]interface{}:
+		child, ok := v[path[0]]
+		if !ok {
+			return
+		}
+		if text, isString := child.(string); isString && len(path) == 1 {
+			v[path[0]] = strings.ToLower(text)
+			return
+		}
+		lowercasePath(child, path[1:])
+	}
+}
+
+// setFieldPath applies one override to the decoded server object, whose Go model is typ
+func setFieldPath(root map[string]interface{}, typ reflect.Type, override fieldOverride) error {
+	segments := strings.Split(override.Field, ".")
+	var container interface{} = root
+	for i, segment := range segments {
+		if segment == "" {
+			return fmt.Errorf("empty path segment")
+		}
+		last := i == len(segments)-1
+		if typ != nil {
+			typ = jsonChildType(typ, segment)
 		}
 
-		if detailed && jsonOutput {
-			var detailedServers []ServerDetail
-			for _, server := range servers {
-				detailResp, err := c.makeRequest("GET", "/v0/servers/"+server.ID, nil, "")
+		switch node := container.(type) {
+		case map[string]interface{}:
+			if last {
+				value, err := coerceOverride(override.Value, typ, node[segment], i == 0)
 				if err != nil {
-					return fmt.Errorf("failed to get details for server %s: %w", server.ID, err)
+					return err
+				}
+				node[segment] = value
+				return nil
+			}
+			child, ok := node[segment]
+			if !ok || child == nil {
+				if typ == nil || derefType(typ).Kind() != reflect.Struct {
+					return fmt.Errorf("path %s does not exist", strings.Join(segments[:i+1], "."))
 				}
-				detailBody, err := io.ReadAll(detailResp.Body)
-				_ = detailResp.Body.Close()
+				child = map[string]interface{}{}
+				node[segment] = child
+			}
+			container = child
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return fmt.Errorf("path %s does not exist (the list has %d element(s))", strings.Join(segments[:i+1], "."), len(node))
+			}
+			if last {
+				value, err := coerceOverride(override.Value, typ, node[index], false)
 				if err != nil {
-					return fmt.Errorf("failed to read detail response for server %s: %w", server.ID, err)
+					return err
 				}
-				if detailResp.StatusCode == 200 {
-					var serverDetail ServerDetail
-					// Try new wrapper format first
-					var detailWrapper ServerDetailWrapper
-					if err := json.Unmarshal(detailBody, &detailWrapper); err == nil && (detailWrapper.Server.ID != "" || detailWrapper.RegistryMeta != nil) {
-						serverDetail = detailWrapper.Server
-						// Extract ID from registry metadata using wrapper method
-						if serverID := detailWrapper.GetServerID(); serverID != "" {
-							serverDetail.ID = serverID
-						}
-					} else {
-						// Try legacy format
-						if err := json.Unmarshal(detailBody, &serverDetail); err != nil {
-							return fmt.Errorf("failed to parse detail response for server %s: %w", server.ID, err)
-						}
-					}
-					detailedServers = append(detailedServers, serverDetail)
-				} else {
-					serverDetail := ServerDetail{
-						Server: server,
-					}
-					detailedServers = append(detailedServers, serverDetail)
+				node[index] = value
+				return nil
+			}
+			container = node[index]
+		default:
+			return fmt.Errorf("%s is not an object or list", strings.Join(segments[:i], "."))
+		}
+	}
+	return nil
+}
+
+// jsonChildType returns the type of the field with JSON name name in struct t, or of the elements of list t.
+// Embedded structs without a JSON name are searched too, as encoding/json promotes their fields. The result
+// is nil when the CLI does not model the child.
+func jsonChildType(t reflect.Type, name string) reflect.Type {
+	t = derefType(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if _, err := strconv.Atoi(name); err == nil {
+			return t.Elem()
+		}
+		return nil
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tagName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if field.Anonymous && tagName == "" {
+				if child := jsonChildType(field.Type, name); child != nil {
+					return child
 				}
+				continue
 			}
-			detailedResp := LegacyDetailedServersResponse{
-				Servers:  detailedServers,
-				Metadata: metadata,
+			if tagName == "" {
+				tagName = field.Name
 			}
-			prettyJSON, err := json.MarshalIndent(detailedResp, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to format JSON: %w", err)
+			if tagName == name && tagName != "-" {
+				return field.Type
 			}
-			fmt.Println(string(prettyJSON))
-		} else if jsonOutput {
-			// Convert back to legacy format for output
-			legacyResp := LegacyServersResponse{
-				Servers:  servers,
-				Metadata: metadata,
+		}
+	}
+	return nil
+}
+
+// derefType strips pointer indirections from t
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// coerceOverride converts a flag value to the JSON value stored at a path. typ is the modelled type of the
+// field, or nil when the CLI does not model it; current is the value in the manifest, if any. An
+// unmodelled field that does not exist yet may only be added at the top level, as a string.
+func coerceOverride(value string, typ reflect.Type, current interface{}, topLevel bool) (interface{}, error) {
+	kind := reflect.Invalid
+	if typ != nil {
+		kind = derefType(typ).Kind()
+	} else {
+		switch current.(type) {
+		case string:
+			kind = reflect.String
+		case bool:
+			kind = reflect.Bool
+		case json.Number:
+			kind = reflect.Float64
+		case nil:
+			if !topLevel {
+				return nil, fmt.Errorf("path does not exist")
 			}
-			prettyJSON, err := json.MarshalIndent(legacyResp, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to format JSON: %w", err)
+			kind = reflect.String
+		default:
+			return nil, fmt.Errorf("the field is an object or list; set one of its fields instead")
+		}
+	}
+
+	switch kind {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", value)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("expected an integer, got %q", value)
+		}
+		return json.Number(value), nil
+	case reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", value)
+		}
+		return json.Number(value), nil
+	default:
+		return nil, fmt.Errorf("the field is an object or list; set one of its fields instead")
+	}
+}
+
+// defaultPublisherMeta describes the CLI build publishing a server
+func defaultPublisherMeta() map[string]interface{} {
+	return map[string]interface{}{
+		"tool":    "mcpx-cli",
+		"version": version,
+		"build_info": map[string]interface{}{
+			"timestamp": time.Now().Format(time.RFC3339),
+		},
+	}
+}
+
+// Manifest schema versions accepted by publish
+const (
+	// SchemaVersionV1 is a bare (legacy) ServerDetail manifest
+	SchemaVersionV1 = "v1"
+	// SchemaVersionV2 is a PublishRequest wrapper: {"server": {...}, "x-publisher": {...}}
+	SchemaVersionV2 = "v2"
+)
+
+// detectSchemaVersion tells a PublishRequest wrapper (v2) from a bare manifest (v1) by its "server" object
+func detectSchemaVersion(data []byte) (string, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("invalid JSON in server file: %w", prettyJSONError(data, err))
+	}
+	if server, ok := probe["server"]; ok && strings.HasPrefix(strings.TrimSpace(string(server)), "{") {
+		return SchemaVersionV2, nil
+	}
+	return SchemaVersionV1, nil
+}
+
+// buildPublishBody turns a server file, either a bare manifest (v1) or a PublishRequest (v2), into a
+// PublishRequest body. An empty schemaVersion is detected from the content. The server object is passed
+// through unchanged, apart from overrides, so fields the CLI does not model survive. x-publisher metadata
+// from the file is kept; the CLI's own is added only when the file has none. extra entries are set on top.
+func buildPublishBody(data []byte, extra map[string]string, schemaVersion string, overrides []fieldOverride, normalize bool) ([]byte, ServerDetail, error) {
+	if schemaVersion == "" {
+		detected, err := detectSchemaVersion(data)
+		if err != nil {
+			return nil, ServerDetail{}, err
+		}
+		schemaVersion = detected
+	}
+
+	var request struct {
+		Server     json.RawMessage        `json:"server"`
+		XPublisher map[string]interface{} `json:"x-publisher,omitempty"`
+	}
+	switch schemaVersion {
+	case SchemaVersionV1:
+		request.Server = data
+	case SchemaVersionV2:
+		if err := json.Unmarshal(data, &request); err != nil {
+			return nil, ServerDetail{}, fmt.Errorf("invalid JSON in server file: %w", prettyJSONError(data, err))
+		}
+		if request.Server == nil {
+			return nil, ServerDetail{}, fmt.Errorf("schema version v2 requires a \"server\" object in the server file")
+		}
+	default:
+		return nil, ServerDetail{}, fmt.Errorf("unknown schema version %q (expected v1 or v2)", schemaVersion)
+	}
+
+	var serverDetail ServerDetail
+	if err := json.Unmarshal(request.Server, &serverDetail); err != nil {
+		return nil, ServerDetail{}, fmt.Errorf("invalid server in server file: %w", prettySubJSONError(data, request.Server, err))
+	}
+	if len(overrides) > 0 || normalize {
+		server, err := applyFieldOverrides(request.Server, overrides)
+		if err != nil {
+			return nil, ServerDetail{}, err
+		}
+		if normalize {
+			if server, err = normalizeServer(server); err != nil {
+				return nil, ServerDetail{}, err
 			}
-			fmt.Println(string(prettyJSON))
-		} else {
-			fmt.Printf("Total Servers: %d\n", len(servers))
-			if metadata.NextCursor != "" {
-				fmt.Printf("Next Cursor: %s\n", metadata.NextCursor)
-			}
-			for i, server := range servers {
-				fmt.Printf("\n--- Server %d ---\n", i+1)
-				fmt.Printf("ID: %s\n", server.GetServerID())
-				if versionID := server.GetVersionID(); versionID != "" {
-					fmt.Printf("Version ID: %s\n", versionID)
-				}
-				fmt.Printf("Name: %s\n", server.Name)
-				fmt.Printf("Description: %s\n", server.Description)
-				if server.Status != "" {
-					fmt.Printf("Status: %s\n", server.Status)
-				}
-				fmt.Printf("Repository: %s (%s)\n", server.Repository.URL, server.Repository.Source)
-				fmt.Printf("Version: %s\n", server.Version)
+		}
+		request.Server = server
+		serverDetail = ServerDetail{}
+		if err := json.Unmarshal(server, &serverDetail); err != nil {
+			return nil, ServerDetail{}, fmt.Errorf("invalid server after overrides: %w", err)
+		}
+	}
+
+	if request.XPublisher == nil {
+		request.XPublisher = defaultPublisherMeta()
+	}
+	for key, value := range extra {
+		request.XPublisher[key] = value
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, ServerDetail{}, fmt.Errorf("failed to marshal publish request: %w", err)
+	}
+	return body, serverDetail, nil
+}
+
+// parseKeyValue splits a key=value flag argument
+func parseKeyValue(arg string) (string, string, error) {
+	key, value, ok := strings.Cut(arg, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return "", "", fmt.Errorf("expected key=value, got %q", arg)
+	}
+	return strings.TrimSpace(key), value, nil
+}
+
+// rawBodyServerName extracts the server name from a raw publish body, which is either a PublishRequest
+// ({"server": {...}}) or a bare server manifest
+func rawBodyServerName(data []byte) (string, error) {
+	server, err := rawBodyServer(data)
+	return server.Name, err
+}
+
+// rawBodyServer decodes the server of a raw publish body in either format
+func rawBodyServer(data []byte) (Server, error) {
+	var probe struct {
+		Server json.RawMessage `json:"server"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return Server{}, fmt.Errorf("invalid JSON in body file: %w", prettyJSONError(data, err))
+	}
+	var server Server
+	if probe.Server == nil {
+		if err := json.Unmarshal(data, &server); err != nil {
+			return Server{}, fmt.Errorf("invalid JSON in body file: %w", prettyJSONError(data, err))
+		}
+		return server, nil
+	}
+	if err := json.Unmarshal(probe.Server, &server); err != nil {
+		return Server{}, fmt.Errorf("invalid server in body file: %w", prettySubJSONError(data, probe.Server, err))
+	}
+	return server, nil
+}
+
+// idempotencyHeaders returns the Idempotency-Key header for a publish attempt, generating a key if none is given.
+// The same headers must be reused for every retry of the attempt so the registry can deduplicate them.
+func idempotencyHeaders(key string) map[string]string {
+	if key == "" {
+		key = uuid.NewString()
+	}
+	return map[string]string{"Idempotency-Key": key}
+}
+
+func (c *MCPXClient) PublishServer(serverFile string, token string, opts PublishOptions) error {
+	result, err := c.publishServerFile(serverFile, token, opts)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		// The failure has already been printed; the error only sets the exit status
+		return fmt.Errorf("publish failed with status %d", result.StatusCode)
+	}
+	return nil
+}
+
+// publishServerFile publishes one manifest and returns the final outcome, including failed responses
+func (c *MCPXClient) publishServerFile(serverFile string, token string, opts PublishOptions) (PublishResult, error) {
+	if !opts.JSON && !opts.Quiet {
+		fmt.Printf("=== Publish Server (File: %s) ===\n", serverFile)
+	}
+
+	var data []byte
+	var err error
+	if opts.Raw {
+		// Raw bodies are sent byte-for-byte, so no format conversion is applied
+		data, err = os.ReadFile(serverFile)
+		if err != nil {
+			err = fmt.Errorf("failed to read server file: %w", err)
+		}
+	} else {
+		data, err = c.readManifest(serverFile)
+	}
+	if err != nil {
+		return PublishResult{}, err
+	}
+	if opts.Strict {
+		if err := checkStrictManifest(data); err != nil {
+			return PublishResult{}, err
+		}
+	}
+
+	result, body, err := c.publishManifestData(serverFile, data, token, opts)
+	if err != nil {
+		return result, err
+	}
+	if result.StatusCode == http.StatusUnprocessableEntity && !opts.JSON && !opts.Quiet && offerEditAndRetry(serverFile, body) {
+		// The edited manifest is a new publish attempt, so it gets a new idempotency key
+		opts.IdempotencyKey = ""
+		return c.publishServerFile(serverFile, token, opts)
+	}
+	return result, nil
+}
+
+// publishManifestData sends an already-read manifest to /v0/publish and prints the outcome. source names
+// where the manifest came from in log messages. The raw response body is returned alongside the result.
+func (c *MCPXClient) publishManifestData(source string, data []byte, token string, opts PublishOptions) (PublishResult, []byte, error) {
+	var err error
+	var serverName string
+	if opts.Raw {
+		// Only look at the name; the body is sent exactly as it is on disk
+		if serverName, err = rawBodyServerName(data); err != nil {
+			return PublishResult{}, nil, err
+		}
+	} else {
+		schemaVersion := opts.SchemaVersion
+		if schemaVersion == "" {
+			if schemaVersion, err = detectSchemaVersion(data); err != nil {
+				return PublishResult{}, nil, err
 			}
+			c.logger.Debug("detected manifest schema version", "file", source, "schemaVersion", schemaVersion)
+		} else {
+			c.logger.Debug("using manifest schema version from --schema-version", "file", source, "schemaVersion", schemaVersion)
+		}
+		body, serverDetail, err := buildPublishBody(data, opts.PublisherMeta, schemaVersion, opts.Overrides, !opts.NoNormalize)
+		if err != nil {
+			return PublishResult{}, nil, err
+		}
+		data = body
+
+		if _, err := parseSemver(serverDetail.Version); err != nil && !opts.AllowNonSemver {
+			c.logger.Warn(fmt.Sprintf("%v; the registry may reject it or sort it incorrectly", err))
+		}
+		if !opts.NoConsistencyChecks {
+			c.warnRepositoryConsistency(serverDetail)
+		}
+		serverName = serverDetail.Name
+	}
+
+	if token, err = c.namespaceToken(serverName, token); err != nil {
+		return PublishResult{}, nil, err
+	}
+
+	headers := idempotencyHeaders(opts.IdempotencyKey)
+	c.logger.Debug("publishing", "idempotency_key", headers["Idempotency-Key"])
+	resp, err := c.makeRequestWithHeaders("POST", "/v0/publish", data, token, headers)
+	if err != nil {
+		return PublishResult{}, nil, fmt.Errorf("publish request failed: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return PublishResult{}, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if !opts.JSON && !opts.Quiet {
+		fmt.Printf("Status Code: %d\n", resp.StatusCode)
+	}
+
+	result := parsePublishResponse(resp.StatusCode, body)
+	result.Hint = authFailureHint(resp.StatusCode, serverName)
+	if resp.StatusCode == 422 && token == "" && !c.noAuth {
+		// If we get 422 with no token, try to re-authenticate and retry once
+		c.logger.Info("Authentication failed. Trying to re-authenticate...")
+		if err := c.loginAnonymous(); err != nil {
+			return PublishResult{}, nil, fmt.Errorf("failed to re-authenticate: %w", err)
+		}
+
+		// Get the fresh token for retry
+		config, err := c.loadAuthConfig()
+		if err != nil {
+			return PublishResult{}, nil, fmt.Errorf("failed to load fresh auth config: %w", err)
+		}
+
+		// Retry the request with fresh token
+		// Reuse the idempotency key so a first attempt that actually succeeded is not duplicated
+		retryResp, err := c.makeRequestWithHeaders("POST", "/v0/publish", data, config.Token, headers)
+		if err != nil {
+			return PublishResult{}, nil, fmt.Errorf("retry publish request failed: %w", err)
+		}
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(retryResp.Body)
+
+		retryBody, err := c.readResponseBody(retryResp)
+		if err != nil {
+			return PublishResult{}, nil, fmt.Errorf("failed to read retry response: %w", err)
 		}
-	} else {
-		if jsonOutput {
-			fmt.Println(string(body))
-		} else {
-			fmt.Printf("Error: %s\n", string(body))
+
+		if !opts.JSON && !opts.Quiet {
+			fmt.Printf("Retry Status Code: %d\n", retryResp.StatusCode)
 		}
+		resp, body = retryResp, retryBody
+		result = parsePublishResponse(retryResp.StatusCode, retryBody)
+		result.Hint = authFailureHint(retryResp.StatusCode, serverName)
+		if opts.Quiet {
+			return result, body, nil
+		}
+		if err := printPublishResult(result, opts.JSON, "Retry failed"); err != nil {
+			return result, body, err
+		}
+	} else if opts.Quiet {
+		return result, body, nil
+	} else if err := printPublishResult(result, opts.JSON, "Error"); err != nil {
+		return result, body, err
 	}
-
-	return nil
+	return result, body, nil
 }
 
-func (c *MCPXClient) GetServer(serverName string, jsonOutput bool) error {
-	if !jsonOutput {
-		fmt.Printf("=== Get Server Details (Name: %s) ===\n", serverName)
+// warnRepositoryConsistency logs repositoryConsistencyProblems as warnings before a publish is sent
+func (c *MCPXClient) warnRepositoryConsistency(server ServerDetail) {
+	for _, problem := range repositoryConsistencyProblems(server) {
+		c.logger.Warn(fmt.Sprintf("%s; the registry will likely reject it (use --no-consistency-checks to skip this check)", problem))
 	}
+}
 
-	// URL encode the server name for the API (use PathEscape for path segments)
-	// Note: We need to double-encode slashes because Go's HTTP server decodes %2F to / before routing
-	encodedName := url.PathEscape(serverName)
-	// Double-encode the % in %2F to %252F so it survives Go's URL decoding
-	encodedName = strings.ReplaceAll(encodedName, "%2F", "%252F")
-	endpoint := "/v0/servers/" + encodedName + "/versions/latest"
-
-	if !jsonOutput {
-		fmt.Printf("Request URL: %s%s\n", c.baseURL, endpoint)
+// manifestFiles lists the *.json files in dir, descending into subdirectories when recursive is set
+func manifestFiles(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
 	}
+	sort.Strings(files)
+	return files, nil
+}
 
-	resp, err := c.makeRequest("GET", endpoint, nil, "")
+// PublishDirectory publishes every manifest in dir and prints a summary. Files that are not server
+// manifests are skipped with a warning; an error is returned if any publish failed.
+func (c *MCPXClient) PublishDirectory(dir string, recursive bool, token string, opts PublishOptions, summary string) error {
+	files, err := manifestFiles(dir, recursive)
 	if err != nil {
-		return fmt.Errorf("get server request failed: %w", err)
+		return err
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
+	return c.publishManifests(files, token, opts, bulkPublishOptions{ContinueOnError: true, Summary: summary})
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+// Bulk publish summary formats accepted by --summary
+const (
+	SummaryText = "text"
+	SummaryJSON = "json"
+)
+
+// bulkPublishOptions controls how publishManifests treats individual files
+type bulkPublishOptions struct {
+	// ContinueOnError publishes the remaining files after a failure instead of stopping
+	ContinueOnError bool
+	// IfNotExists skips manifests whose name and version are already in the registry
+	IfNotExists bool
+	// Summary is SummaryText (the default when empty) or SummaryJSON. With SummaryJSON the per-file output
+	// is suppressed and stdout holds only the BulkPublishSummary document.
+	Summary string
+}
+
+// BulkPublishSummary is the outcome of publishing a set of manifests, as printed by --summary json
+type BulkPublishSummary struct {
+	Total     int                `json:"total"`
+	Succeeded []BulkPublishEntry `json:"succeeded"`
+	Skipped   []BulkPublishEntry `json:"skipped"`
+	Failed    []BulkPublishEntry `json:"failed"`
+	// NotAttempted counts the files left over when the publish stopped at the first failure
+	NotAttempted int `json:"notAttempted,omitempty"`
+}
+
+// BulkPublishEntry is one manifest of a BulkPublishSummary. Reason explains a skip or a failure.
+type BulkPublishEntry struct {
+	File      string `json:"file"`
+	Name      string `json:"name,omitempty"`
+	Version   string `json:"version,omitempty"`
+	ServerID  string `json:"serverId,omitempty"`
+	VersionID string `json:"versionId,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// validateSummaryFormat checks a --summary value
+func validateSummaryFormat(format string) error {
+	if format != "" && format != SummaryText && format != SummaryJSON {
+		return fmt.Errorf("--summary must be %s or %s", SummaryText, SummaryJSON)
 	}
+	return nil
+}
 
-	if !jsonOutput {
-		fmt.Printf("Status Code: %d\n", resp.StatusCode)
+// ImportDirectory publishes every manifest in dir, e.g. one written by export --all, to the client's
+// registry. It stops at the first failure unless ContinueOnError is set.
+func (c *MCPXClient) ImportDirectory(dir, token string, opts PublishOptions, bulk bulkPublishOptions) error {
+	if bulk.Summary != SummaryJSON {
+		fmt.Printf("=== Import Servers (Directory: %s) ===\n", dir)
+	}
+	files, err := manifestFiles(dir, false)
+	if err != nil {
+		return err
 	}
+	return c.publishManifests(files, token, opts, bulk)
+}
 
-	if resp.StatusCode == 200 {
-		var serverDetail ServerDetail
-
-		// Try new wrapper format first
-		var detailWrapper ServerDetailWrapper
-		if err := json.Unmarshal(body, &detailWrapper); err == nil && (detailWrapper.Server.Name != "" || detailWrapper.RegistryMeta != nil) {
-			serverDetail = detailWrapper.Server
-			// Extract server ID from wrapper metadata
-			if serverID := detailWrapper.GetServerID(); serverID != "" {
-				serverDetail.ID = serverID
-			}
-		} else {
-			// Try legacy format
-			if err := json.Unmarshal(body, &serverDetail); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
+// publishManifests publishes files one by one and prints a summary. Files that are not server manifests are
+// skipped with a warning, and io.github.* manifests fail without a request when no token is given.
+// An error is returned if any publish failed.
+func (c *MCPXClient) publishManifests(files []string, token string, opts PublishOptions, bulk bulkPublishOptions) error {
+	jsonSummary := bulk.Summary == SummaryJSON
+	opts.Quiet = opts.Quiet || jsonSummary
+	summary := BulkPublishSummary{
+		Total:     len(files),
+		Succeeded: []BulkPublishEntry{},
+		Skipped:   []BulkPublishEntry{},
+		Failed:    []BulkPublishEntry{},
+	}
+	var skipped, existing int
+	progress := c.newProgress("Publishing", len(files))
+	for i, file := range files {
+		progress.Clear()
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read server file: %w", err)
 		}
+		server, err := rawBodyServer(data)
+		if err != nil || server.Name == "" {
+			c.logger.Warn("skipping file that is not a server manifest", "file", file)
+			summary.Skipped = append(summary.Skipped, BulkPublishEntry{File: file, Reason: "not a server manifest"})
+			skipped++
+			progress.Add(1)
+			continue
+		}
+		entry := BulkPublishEntry{File: file, Name: server.Name, Version: server.Version}
 
-		if jsonOutput {
-			prettyJSON, err := json.MarshalIndent(serverDetail, "", "  ")
+		if bulk.IfNotExists {
+			_, statusCode, body, err := c.fetchServerVersion(server.Name, server.Version)
 			if err != nil {
-				return fmt.Errorf("failed to format JSON: %w", err)
+				return err
 			}
-			fmt.Println(string(prettyJSON))
-		} else {
-			fmt.Printf("Name: %s\n", serverDetail.Name)
-			if serverID := serverDetail.GetServerID(); serverID != "" {
-				fmt.Printf("Server ID: %s\n", serverID)
-			}
-			if versionID := serverDetail.GetVersionID(); versionID != "" {
-				fmt.Printf("Version ID: %s\n", versionID)
-			}
-			fmt.Printf("Description: %s\n", serverDetail.Description)
-			if serverDetail.Status != "" {
-				fmt.Printf("Status: %s\n", serverDetail.Status)
-			}
-			fmt.Printf("Repository: %s (%s)\n", serverDetail.Repository.URL, serverDetail.Repository.Source)
-			fmt.Printf("Version: %s\n", serverDetail.Version)
-			if len(serverDetail.Packages) > 0 {
-				fmt.Printf("\nPackages:\n")
-				for i, pkg := range serverDetail.Packages {
-					fmt.Printf("  Package %d:\n", i+1)
-					fmt.Printf("    Registry: %s\n", pkg.RegistryType)
-					fmt.Printf("    Identifier: %s\n", pkg.Identifier)
-					fmt.Printf("    Version: %s\n", pkg.Version)
-					if pkg.WheelURL != "" {
-						fmt.Printf("    Wheel URL: %s\n", pkg.WheelURL)
-					}
-					if pkg.BinaryURL != "" {
-						fmt.Printf("    Binary URL: %s\n", pkg.BinaryURL)
-					}
-					if pkg.RuntimeHint != "" {
-						fmt.Printf("    Runtime Hint: %s\n", pkg.RuntimeHint)
-					}
-					if len(pkg.EnvironmentVariables) > 0 {
-						fmt.Printf("    Environment Variables:\n")
-						for _, env := range pkg.EnvironmentVariables {
-							required := "optional"
-							if env.IsRequired {
-								required = "required"
-							}
-							fmt.Printf("      - %s: %s (%s)\n", env.Name, env.Description, required)
-						}
-					}
-					if len(pkg.RuntimeArguments) > 0 {
-						fmt.Printf("    Runtime Arguments:\n")
-						for _, arg := range pkg.RuntimeArguments {
-							required := "optional"
-							if arg.IsRequired {
-								required = "required"
-							}
-							nameInfo := arg.Type
-							if arg.Name != "" {
-								nameInfo = fmt.Sprintf("%s:%s", arg.Type, arg.Name)
-							}
-							fmt.Printf("      - %s (%s): %s\n", nameInfo, required, arg.Description)
-						}
-					}
+			if statusCode == http.StatusOK {
+				if !jsonSummary {
+					fmt.Printf("⏭️  %s %s already exists, skipping %s\n", server.Name, server.Version, file)
 				}
+				entry.Reason = "already exists in the registry"
+				summary.Skipped = append(summary.Skipped, entry)
+				existing++
+				progress.Add(1)
+				continue
 			}
-			if len(serverDetail.Remotes) > 0 {
-				fmt.Printf("\nRemotes:\n")
-				for i, remote := range serverDetail.Remotes {
-					fmt.Printf("  Remote %d:\n", i+1)
-					transportType := remote.Type
-					if transportType == "" {
-						transportType = "unknown"
-					}
-					fmt.Printf("    Transport: %s\n", transportType)
-					fmt.Printf("    URL: %s\n", remote.URL)
-				}
+			if statusCode != http.StatusNotFound {
+				return &APIError{Op: fmt.Sprintf("get %s/%s", server.Name, server.Version), StatusCode: statusCode, Body: body}
 			}
 		}
-	} else {
-		if jsonOutput {
-			fmt.Println(string(body))
+
+		if classifyNamespace(server.Name) == NamespaceGitHub && token == "" {
+			c.logger.Error("authentication token is required for GitHub namespaced servers (io.github.*); pass --token", "file", file)
+			entry.Reason = "authentication token is required for GitHub namespaced servers (io.github.*)"
+			summary.Failed = append(summary.Failed, entry)
+		} else if result, err := c.publishServerFile(file, token, opts); err != nil {
+			c.logger.Error(err.Error(), "file", file)
+			entry.Reason = err.Error()
+			summary.Failed = append(summary.Failed, entry)
+		} else if !result.Success {
+			entry.Reason = fmt.Sprintf("HTTP %d: %s", result.StatusCode, result.Error)
+			summary.Failed = append(summary.Failed, entry)
 		} else {
-			fmt.Printf("Error: %s\n", string(body))
+			entry.ServerID, entry.VersionID = result.ServerID, result.VersionID
+			summary.Succeeded = append(summary.Succeeded, entry)
+		}
+		if !jsonSummary {
+			fmt.Println()
+		}
+		progress.Add(1)
+
+		if len(summary.Failed) > 0 && !bulk.ContinueOnError {
+			if remaining := len(files) - i - 1; remaining > 0 {
+				c.logger.Warn(fmt.Sprintf("stopping after the first failure, %d file(s) not attempted (use --continue-on-error to publish them)", remaining))
+				summary.NotAttempted = remaining
+			}
+			break
+		}
+	}
+	progress.Done()
+
+	if jsonSummary {
+		if err := printIndentedJSON(summary); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("=== Publish Summary ===")
+		fmt.Printf("Total: %d\n", summary.Total)
+		fmt.Printf("Published: %d\n", len(summary.Succeeded))
+		fmt.Printf("Failed: %d\n", len(summary.Failed))
+		fmt.Printf("Skipped: %d\n", skipped)
+		if bulk.IfNotExists {
+			fmt.Printf("Already present: %d\n", existing)
+		}
+		if summary.NotAttempted > 0 {
+			fmt.Printf("Not attempted: %d\n", summary.NotAttempted)
+		}
+		for _, entry := range summary.Succeeded {
+			fmt.Printf("  ✅ %s (%s %s", entry.File, entry.Name, entry.Version)
+			if entry.ServerID != "" {
+				fmt.Printf(", server ID: %s", entry.ServerID)
+			}
+			fmt.Println(")")
+		}
+		for _, entry := range summary.Failed {
+			fmt.Printf("  ❌ %s: %s\n", entry.File, entry.Reason)
 		}
 	}
 
+	if len(summary.Failed) > 0 {
+		return fmt.Errorf("%d of %d manifests failed to publish", len(summary.Failed), len(summary.Failed)+len(summary.Succeeded))
+	}
 	return nil
 }
 
-func (c *MCPXClient) PublishServer(serverFile string, token string) error {
-	fmt.Printf("=== Publish Server (File: %s) ===\n", serverFile)
+// publishFieldErrors extracts the manifest field errors ("body.*" locations) of a 422 problem response
+func publishFieldErrors(body []byte) []string {
+	var problem struct {
+		Errors []struct {
+			Message  string `json:"message"`
+			Location string `json:"location"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &problem); err != nil {
+		return nil
+	}
+
+	var fieldErrors []string
+	for _, e := range problem.Errors {
+		if strings.HasPrefix(e.Location, "body") {
+			fieldErrors = append(fieldErrors, fmt.Sprintf("%s: %s", strings.TrimPrefix(strings.TrimPrefix(e.Location, "body"), "."), e.Message))
+		}
+	}
+	return fieldErrors
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// pagerCommand returns the pager to run from $PAGER, defaulting to less
+func pagerCommand() *exec.Cmd {
+	fields := strings.Fields(os.Getenv("PAGER"))
+	if len(fields) == 0 {
+		fields = []string{"less"}
+	}
+	cmd := exec.Command(fields[0], fields[1:]...)
+	if fields[0] == "less" && os.Getenv("LESS") == "" {
+		// Quit if one screen is enough, keep colours, and leave the output on screen after quitting
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	return cmd
+}
+
+// startPager sends everything written to os.Stdout through the pager until the returned func is called,
+// which waits for the user to quit it. Nothing is paged when stdout is not a terminal.
+func startPager() (func(), error) {
+	if !isTerminal(os.Stdout) {
+		return func() {}, nil
+	}
 
-	data, err := os.ReadFile(serverFile)
+	r, w, err := os.Pipe()
 	if err != nil {
-		return fmt.Errorf("failed to read server file: %w", err)
+		return nil, fmt.Errorf("failed to create pager pipe: %w", err)
+	}
+	cmd := pagerCommand()
+	cmd.Stdin = r
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		return nil, fmt.Errorf("failed to start pager %s: %w", cmd.Path, err)
 	}
 
-	// Parse as ServerDetail directly (the API expects this format)
-	var serverDetail ServerDetail
-	if err := json.Unmarshal(data, &serverDetail); err != nil {
-		return fmt.Errorf("invalid JSON in server file: %w", err)
+	stdout := os.Stdout
+	os.Stdout = w
+	return func() {
+		os.Stdout = stdout
+		_ = w.Close()
+		_ = cmd.Wait()
+		_ = r.Close()
+	}, nil
+}
+
+// offerEditAndRetry lists the field errors of a rejected manifest and, when running in a terminal, offers to
+// open the manifest in $EDITOR. It returns true if the file was edited and the publish should be retried.
+// Outside a terminal nothing is asked, so scripted publishes behave as before.
+func offerEditAndRetry(serverFile string, body []byte) bool {
+	fieldErrors := publishFieldErrors(body)
+	if len(fieldErrors) == 0 || !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return false
 	}
 
-	// Check if GitHub namespace requires authentication
-	if strings.HasPrefix(serverDetail.Name, "io.github.") && token == "" {
-		return fmt.Errorf("authentication token is required for GitHub namespaced servers (io.github.*)")
+	fmt.Println("\nThe registry rejected these fields:")
+	for _, fieldError := range fieldErrors {
+		fmt.Printf("  - %s\n", fieldError)
+	}
+	if promptChoice("Edit "+serverFile+" and retry?", []string{"yes", "no"}, "yes") != "yes" {
+		return false
 	}
 
-	// If no token provided, check if we have a valid stored token
-	if token == "" {
-		config, err := c.loadAuthConfig()
-		if err != nil || config.Token == "" {
-			// Try to auto-authenticate anonymously
-			fmt.Println("No valid authentication found. Attempting anonymous authentication...")
-			if err := c.loginAnonymous(); err != nil {
-				return fmt.Errorf("failed to authenticate: %w", err)
-			}
-		}
+	if err := editFile(serverFile); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return false
 	}
+	return true
+}
 
-	// Send the server data directly as expected by the API
-	resp, err := c.makeRequest("POST", "/v0/publish", data, token)
-	if err != nil {
-		return fmt.Errorf("publish request failed: %w", err)
+// editFile opens path in $VISUAL or $EDITOR (default vi) and waits for the editor to exit
+func editFile(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+	// The editor variable may carry arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	return nil
+}
+
+// PublishResult is the outcome of a publish, normalized across the response shapes the registry may use
+type PublishResult struct {
+	Success    bool   `json:"success"`
+	StatusCode int    `json:"statusCode"`
+	ServerID   string `json:"serverId,omitempty"`
+	VersionID  string `json:"versionId,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Hint is guidance for authentication and authorization failures
+	Hint string `json:"hint,omitempty"`
+	// Response holds the raw body when it matched none of the known shapes
+	Response string `json:"response,omitempty"`
+}
+
+// parsePublishResponse maps a PublishResponse, a wrapper or a legacy Server response onto a PublishResult.
+// IDs are only reported when the registry returned them; none are derived locally.
+func parsePublishResponse(statusCode int, body []byte) PublishResult {
+	result := PublishResult{StatusCode: statusCode, Success: statusCode == 200 || statusCode == 201}
+	if !result.Success {
+		result.Error = strings.TrimSpace(string(body))
+		return result
 	}
 
-	fmt.Printf("Status Code: %d\n", resp.StatusCode)
+	var publishResp PublishResponse
+	if err := json.Unmarshal(body, &publishResp); err == nil && publishResp.Message != "" {
+		result.Message = publishResp.Message
+		result.ServerID = publishResp.ID
+		return result
+	}
 
-	if resp.StatusCode == 200 || resp.StatusCode == 201 {
-		// Try to parse as PublishResponse first
-		var publishResp PublishResponse
-		if err := json.Unmarshal(body, &publishResp); err == nil && publishResp.Message != "" {
-			fmt.Printf("✅ Success: %s\n", publishResp.Message)
-			fmt.Printf("Server ID: %s\n", publishResp.ID)
-		} else {
-			// Try new wrapper format
-			var serverWrapper ServerDetailWrapper
-			if err := json.Unmarshal(body, &serverWrapper); err == nil && serverWrapper.Server.ID != "" {
-				fmt.Printf("✅ Server published successfully\n")
-				fmt.Printf("Server ID: %s\n", serverWrapper.Server.ID)
-			} else {
-				// Try legacy Server response (200 case)
-				var serverResp Server
-				if err := json.Unmarshal(body, &serverResp); err == nil && serverResp.ID != "" {
-					fmt.Printf("✅ Server published successfully\n")
-					fmt.Printf("Server ID: %s\n", serverResp.ID)
-				} else {
-					// Fallback: just show the response
-					fmt.Printf("✅ Success\n")
-					fmt.Printf("Response: %s\n", string(body))
-				}
-			}
+	var serverWrapper ServerDetailWrapper
+	if err := json.Unmarshal(body, &serverWrapper); err == nil {
+		serverID := serverWrapper.Server.ID
+		official := serverWrapper.RegistryMeta.official()
+		if official != nil && official.ServerID != "" {
+			serverID = official.ServerID
 		}
-	} else if resp.StatusCode == 422 && token == "" {
-		// If we get 422 with no token, try to re-authenticate and retry once
-		fmt.Println("Authentication failed. Trying to re-authenticate...")
-		if err := c.loginAnonymous(); err != nil {
-			return fmt.Errorf("failed to re-authenticate: %w", err)
+		if serverID != "" {
+			result.ServerID = serverID
+			if official != nil {
+				result.VersionID = official.VersionID
+			}
+			return result
 		}
+	}
 
-		// Get the fresh token for retry
-		config, err := c.loadAuthConfig()
-		if err != nil {
-			return fmt.Errorf("failed to load fresh auth config: %w", err)
+	var serverResp Server
+	if err := json.Unmarshal(body, &serverResp); err == nil {
+		if serverResp.Meta != nil && serverResp.Meta.Official != nil {
+			result.ServerID = serverResp.Meta.Official.ServerID
+			result.VersionID = serverResp.Meta.Official.VersionID
+		} else {
+			result.ServerID = serverResp.ID
 		}
-
-		// Retry the request with fresh token
-		retryResp, err := c.makeRequest("POST", "/v0/publish", data, config.Token)
-		if err != nil {
-			return fmt.Errorf("retry publish request failed: %w", err)
+		if result.ServerID != "" {
+			return result
 		}
-		defer func(Body io.ReadCloser) {
-			_ = Body.Close()
-		}(retryResp.Body)
+	}
 
-		retryBody, err := io.ReadAll(retryResp.Body)
+	result.VersionID = ""
+	result.Response = strings.TrimSpace(string(body))
+	return result
+}
+
+// printPublishResult prints a publish outcome as JSON or in the emoji text format; failurePrefix labels errors
+func printPublishResult(result PublishResult, jsonOutput bool, failurePrefix string) error {
+	if jsonOutput && !result.Success {
+		doc := newJSONError(result.StatusCode, []byte(result.Error))
+		doc.Hint = result.Hint
+		return writeJSONError(doc)
+	}
+	if jsonOutput {
+		prettyJSON, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to read retry response: %w", err)
+			return fmt.Errorf("failed to format JSON: %w", err)
 		}
+		fmt.Println(string(prettyJSON))
+		return nil
+	}
 
-		fmt.Printf("Retry Status Code: %d\n", retryResp.StatusCode)
-
-		if retryResp.StatusCode == 200 || retryResp.StatusCode == 201 {
-			// Try to parse as PublishResponse first
-			var publishResp PublishResponse
-			if err := json.Unmarshal(retryBody, &publishResp); err == nil && publishResp.Message != "" {
-				fmt.Printf("✅ Success: %s\n", publishResp.Message)
-				fmt.Printf("Server ID: %s\n", publishResp.ID)
-			} else {
-				fmt.Printf("✅ Success\n")
-				fmt.Printf("Response: %s\n", string(retryBody))
-			}
-		} else {
-			fmt.Printf("❌ Retry failed: %s\n", string(retryBody))
+	switch {
+	case !result.Success:
+		fmt.Printf("❌ %s: %s\n", failurePrefix, result.Error)
+		if result.Hint != "" {
+			fmt.Printf("Hint: %s\n", result.Hint)
 		}
-	} else {
-		fmt.Printf("❌ Error: %s\n", string(body))
+	case result.Message != "":
+		fmt.Printf("✅ Success: %s\n", result.Message)
+		fmt.Printf("Server ID: %s\n", result.ServerID)
+	case result.ServerID != "":
+		fmt.Printf("✅ Server published successfully\n")
+		fmt.Printf("Server ID: %s\n", result.ServerID)
+	default:
+		fmt.Printf("✅ Success\n")
+		fmt.Printf("Response: %s\n", result.Response)
 	}
-
 	return nil
 }
 
+// promptAcceptDefaults makes promptUser and promptChoice answer every prompt with its default instead of
+// reading stdin, as for publish --interactive --accept-defaults
+var promptAcceptDefaults bool
+
 func promptUser(prompt string, defaultValue string) string {
 	if defaultValue != "" {
 		fmt.Printf("%s [%s]: ", prompt, defaultValue)
 	} else {
 		fmt.Printf("%s: ", prompt)
 	}
+	if promptAcceptDefaults {
+		fmt.Println(defaultValue)
+		return defaultValue
+	}
 
 	reader := bufio.NewReader(os.Stdin)
 	input, _ := reader.ReadString('\n')
@@ -1026,6 +6088,10 @@ func promptChoice(prompt string, choices []string, defaultChoice string) string
 		}
 		fmt.Printf("  %s %d) %s\n", marker, i+1, choice)
 	}
+	if promptAcceptDefaults {
+		fmt.Printf("Using default: %s\n", defaultChoice)
+		return defaultChoice
+	}
 
 	for {
 		input := promptUser("Enter choice (1-"+strconv.Itoa(len(choices))+")", "")
@@ -1172,28 +6238,48 @@ func createInteractiveServer() (*ServerDetail, error) {
 	return &server, nil
 }
 
-func (c *MCPXClient) PublishServerInteractive(token string) error {
+// errInteractiveNeedsTTY stops interactive publish when stdin is piped or closed, as in CI, where the prompts
+// would otherwise wait for input forever
+var errInteractiveNeedsTTY = errors.New("interactive mode requires a TTY (stdin is not a terminal); publish a server file instead: mcpx-cli publish server.json")
+
+func (c *MCPXClient) PublishServerInteractive(token string, opts PublishOptions) error {
+	if opts.AcceptDefaults {
+		promptAcceptDefaults = true
+		defer func() { promptAcceptDefaults = false }()
+	} else if !isTerminal(os.Stdin) {
+		return errInteractiveNeedsTTY
+	}
 	fmt.Println("=== Interactive Publish Server ===")
 
 	server, err := createInteractiveServer()
 	if err != nil {
 		return fmt.Errorf("failed to create server config: %w", err)
 	}
+	if len(opts.Overrides) > 0 {
+		raw, err := json.Marshal(server)
+		if err != nil {
+			return fmt.Errorf("failed to marshal server config: %w", err)
+		}
+		if raw, err = applyFieldOverrides(raw, opts.Overrides); err != nil {
+			return err
+		}
+		server = &ServerDetail{}
+		if err := json.Unmarshal(raw, server); err != nil {
+			return fmt.Errorf("invalid server after overrides: %w", err)
+		}
+	}
+	if !opts.NoConsistencyChecks {
+		c.warnRepositoryConsistency(*server)
+	}
 
-	if strings.HasPrefix(server.Name, "io.github.") && token == "" {
+	if classifyNamespace(server.Name) == NamespaceGitHub && token == "" {
 		return fmt.Errorf("authentication token is required for GitHub namespaced servers (io.github.*)")
 	}
 
 	// Create PublishRequest wrapper
 	publishReq := PublishRequest{
-		Server: *server,
-		XPublisher: map[string]interface{}{
-			"tool":    "mcpx-cli",
-			"version": version,
-			"build_info": map[string]interface{}{
-				"timestamp": time.Now().Format(time.RFC3339),
-			},
-		},
+		Server:     *server,
+		XPublisher: defaultPublisherMeta(),
 	}
 
 	data, err := json.MarshalIndent(publishReq, "", "  ")
@@ -1207,7 +6293,11 @@ func (c *MCPXClient) PublishServerInteractive(token string) error {
 		if !strings.HasSuffix(filename, ".json") {
 			filename += ".json"
 		}
-		if err := os.WriteFile(filename, data, 0644); err != nil {
+		_, statErr := os.Stat(filename)
+		exists := statErr == nil
+		if exists && promptChoice(fmt.Sprintf("%s already exists. Overwrite it?", filename), []string{"yes", "no"}, "no") != "yes" {
+			fmt.Printf("Configuration not saved; %s is unchanged\n", filename)
+		} else if err := writeFile(filename, data, exists); err != nil {
 			fmt.Printf("Warning: Failed to save config to %s: %v\n", filename, err)
 		} else {
 			fmt.Printf("Configuration saved to %s\n", filename)
@@ -1219,14 +6309,27 @@ func (c *MCPXClient) PublishServerInteractive(token string) error {
 	fmt.Printf("Description: %s\n", server.Description)
 	fmt.Printf("Version: %s\n", server.Version)
 	fmt.Printf("Repository: %s\n", server.Repository.URL)
+	if opts.PreviewFull {
+		fmt.Println("\n=== Full Manifest ===")
+		fmt.Println(string(data))
+	}
 
-	publish := promptChoice("Proceed with publishing?", []string{"yes", "no"}, "no")
+	// Accepting defaults is the consent to publish; there is nobody to answer the confirmation
+	proceedDefault := "no"
+	if opts.AcceptDefaults {
+		proceedDefault = "yes"
+	}
+	publish := promptChoice("Proceed with publishing?", []string{"yes", "no"}, proceedDefault)
 	if publish != "yes" {
 		fmt.Println("Publishing cancelled.")
 		return nil
 	}
 
-	resp, err := c.makeRequest("POST", "/v0/publish", data, token)
+	if token, err = c.namespaceToken(server.Name, token); err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequestWithHeaders("POST", "/v0/publish", data, token, idempotencyHeaders(""))
 	if err != nil {
 		return fmt.Errorf("publish request failed: %w", err)
 	}
@@ -1234,7 +6337,7 @@ func (c *MCPXClient) PublishServerInteractive(token string) error {
 		_ = Body.Close()
 	}(resp.Body)
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readResponseBody(resp)
 	if err != nil {
 		return fmt.Errorf("failed to read response: %w", err)
 	}
@@ -1266,20 +6369,43 @@ func (c *MCPXClient) PublishServerInteractive(token string) error {
 	return nil
 }
 
-func (c *MCPXClient) UpdateServer(serverName, serverFile, token string, jsonOutput bool) error {
+// UpdateOptions holds the optional behaviour of UpdateServer
+type UpdateOptions struct {
+	// JSON prints the registry response, or a JSON error document, instead of text
+	JSON bool
+	// Strict rejects manifests with keys the CLI does not know instead of silently dropping them
+	Strict bool
+	// Overrides set server fields in memory before sending, as for publish
+	Overrides []fieldOverride
+	// Patch sends only the overrides, as a JSON merge patch, instead of a full manifest
+	Patch bool
+	// Version is the server version a patch applies to; empty means "latest"
+	Version string
+}
+
+func (c *MCPXClient) UpdateServer(serverName, serverFile, token string, opts UpdateOptions) error {
+	jsonOutput := opts.JSON
 	if !jsonOutput {
 		fmt.Printf("=== Update Server %s ===\n", serverName)
 	}
+	if opts.Patch {
+		return c.patchServer(serverName, token, opts)
+	}
 
-	data, err := os.ReadFile(serverFile)
+	data, err := c.readManifest(serverFile)
 	if err != nil {
-		return fmt.Errorf("failed to read server file: %w", err)
+		return err
+	}
+	if opts.Strict {
+		if err := checkStrictManifest(data); err != nil {
+			return err
+		}
 	}
 
 	// Try to detect if this is a PublishRequest format and unwrap it
 	var rawData map[string]interface{}
 	if err := json.Unmarshal(data, &rawData); err != nil {
-		return fmt.Errorf("invalid JSON in server file: %w", err)
+		return fmt.Errorf("invalid JSON in server file: %w", prettyJSONError(data, err))
 	}
 
 	var serverDetail ServerDetail
@@ -1289,68 +6415,246 @@ func (c *MCPXClient) UpdateServer(serverName, serverFile, token string, jsonOutp
 		// Unwrap the server object from PublishRequest format
 		serverBytes, err := json.Marshal(serverData)
 		if err != nil {
-			return fmt.Errorf("failed to marshal server data: %w", err)
+			return fmt.Errorf("failed to marshal server data: %w", err)
+		}
+		if err := json.Unmarshal(serverBytes, &serverDetail); err != nil {
+			return fmt.Errorf("invalid server data in PublishRequest: %w", prettyJSONError(serverBytes, err))
+		}
+		// Use the unwrapped server data
+		data = serverBytes
+	} else {
+		// Direct ServerDetail format
+		if err := json.Unmarshal(data, &serverDetail); err != nil {
+			return fmt.Errorf("invalid JSON in server file: %w", prettyJSONError(data, err))
+		}
+	}
+	if len(opts.Overrides) > 0 {
+		if data, err = applyFieldOverrides(data, opts.Overrides); err != nil {
+			return err
+		}
+		serverDetail = ServerDetail{}
+		if err := json.Unmarshal(data, &serverDetail); err != nil {
+			return fmt.Errorf("invalid server after overrides: %w", err)
+		}
+	}
+
+	if token, err = c.namespaceToken(serverDetail.Name, token); err != nil {
+		return err
+	}
+
+	statusCode, body, err := c.putServerVersion(serverName, serverDetail.Version, data, nil, token)
+	if err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		fmt.Printf("Status Code: %d\n", statusCode)
+	}
+
+	if statusCode == 200 {
+		if jsonOutput {
+			fmt.Println(string(body))
+		} else {
+			var updateResp map[string]string
+			if err := json.Unmarshal(body, &updateResp); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			fmt.Printf("✅ %s\n", updateResp["message"])
+			fmt.Printf("Server Name: %s\n", serverName)
+		}
+	} else {
+		hint := authFailureHint(statusCode, serverName)
+		if jsonOutput {
+			doc := newJSONError(statusCode, body)
+			doc.Hint = hint
+			return writeJSONError(doc)
+		}
+		return &APIError{Op: "update server", StatusCode: statusCode, Body: body, Hint: hint}
+	}
+
+	return nil
+}
+
+// mergePatchContentType is the media type of a JSON merge patch (RFC 7396)
+const mergePatchContentType = "application/merge-patch+json"
+
+// buildMergePatch turns --set overrides into a JSON merge patch that holds only the fields they set, e.g.
+// {"status":"deprecated"} or {"repository":{"url":"..."}}. A merge patch replaces lists whole, so paths into
+// a list element cannot be expressed and are rejected.
+func buildMergePatch(overrides []fieldOverride) (json.RawMessage, error) {
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("--patch needs at least one --set path=value")
+	}
+	for _, override := range overrides {
+		for _, segment := range strings.Split(override.Field, ".") {
+			if _, err := strconv.Atoi(segment); err == nil {
+				return nil, fmt.Errorf("cannot patch %s: a merge patch replaces whole lists; update the full manifest instead", override.Field)
+			}
+		}
+	}
+	return applyFieldOverrides(json.RawMessage(`{}`), overrides)
+}
+
+// patchServer sends the overrides of opts to one server version as a JSON merge patch. A registry without
+// PATCH support answers 405; the server is then fetched, changed and sent back whole with PUT, as
+// SetServerStatus does, so the same command works against both.
+func (c *MCPXClient) patchServer(serverName, token string, opts UpdateOptions) error {
+	version := opts.Version
+	if version == "" {
+		version = "latest"
+	}
+	patch, err := buildMergePatch(opts.Overrides)
+	if err != nil {
+		return err
+	}
+	if token, err = c.namespaceToken(serverName, token); err != nil {
+		return err
+	}
+
+	statusCode, body, err := c.sendServerVersion("PATCH", serverName, version, patch, nil, token, map[string]string{"Content-Type": mergePatchContentType})
+	if err != nil {
+		return err
+	}
+	if statusCode == http.StatusMethodNotAllowed {
+		if !opts.JSON {
+			fmt.Println("Note: the registry does not support PATCH (405); fetching the server and sending it whole with PUT instead")
+		}
+		detail, fetchStatus, fetchBody, err := c.fetchServerVersion(serverName, version)
+		if err != nil {
+			return err
+		}
+		if fetchStatus != http.StatusOK {
+			return &APIError{Op: fmt.Sprintf("get %s/%s", serverName, version), StatusCode: fetchStatus, Body: fetchBody}
+		}
+		// Registry-managed fields are not part of the manifest that is sent back
+		detail.ID = ""
+		detail.Meta = nil
+		data, err := json.Marshal(detail)
+		if err != nil {
+			return fmt.Errorf("failed to marshal server config: %w", err)
 		}
-		if err := json.Unmarshal(serverBytes, &serverDetail); err != nil {
-			return fmt.Errorf("invalid server data in PublishRequest: %w", err)
+		if data, err = applyFieldOverrides(data, opts.Overrides); err != nil {
+			return err
 		}
-		// Use the unwrapped server data
-		data = serverBytes
-	} else {
-		// Direct ServerDetail format
-		if err := json.Unmarshal(data, &serverDetail); err != nil {
-			return fmt.Errorf("invalid JSON in server file: %w", err)
+		// The edit endpoint takes a status change as a query parameter, as for deprecate and delete
+		var query url.Values
+		for _, override := range opts.Overrides {
+			if override.Field == "status" {
+				query = url.Values{"status": {override.Value}}
+			}
+		}
+		version = detail.Version
+		if statusCode, body, err = c.putServerVersion(serverName, version, data, query, token); err != nil {
+			return err
 		}
 	}
 
-	if strings.HasPrefix(serverDetail.Name, "io.github.") && token == "" {
-		return fmt.Errorf("authentication token is required for GitHub namespaced servers (io.github.*)")
+	if !opts.JSON {
+		fmt.Printf("Status Code: %d\n", statusCode)
+	}
+	if statusCode != http.StatusOK {
+		return &APIError{Op: "update server", StatusCode: statusCode, Body: body, Hint: authFailureHint(statusCode, serverName)}
+	}
+	if opts.JSON {
+		fmt.Println(string(body))
+		return nil
+	}
+	fields := make([]string, 0, len(opts.Overrides))
+	for _, override := range opts.Overrides {
+		fields = append(fields, override.Field)
 	}
+	fmt.Printf("✅ Server version '%s/%s' updated: %s\n", serverName, version, strings.Join(fields, ", "))
+	return nil
+}
+
+// putServerVersion sends a server manifest to the edit endpoint of one version, with optional query
+// parameters such as status. It returns the response status code and body.
+func (c *MCPXClient) putServerVersion(serverName, version string, data []byte, query url.Values, token string) (int, []byte, error) {
+	return c.sendServerVersion("PUT", serverName, version, data, query, token, nil)
+}
 
+// sendServerVersion sends data to the edit endpoint of one version with the given method and headers
+func (c *MCPXClient) sendServerVersion(method, serverName, version string, data []byte, query url.Values, token string, headers map[string]string) (int, []byte, error) {
 	// URL encode the server name and version for the API (use PathEscape for path segments)
 	// Note: We need to double-encode slashes because Go's HTTP server decodes %2F to / before routing
 	encodedName := url.PathEscape(serverName)
 	// Double-encode the % in %2F to %252F so it survives Go's URL decoding
 	encodedName = strings.ReplaceAll(encodedName, "%2F", "%252F")
-	encodedVersion := url.PathEscape(serverDetail.Version)
+	encodedVersion := url.PathEscape(version)
 	endpoint := fmt.Sprintf("/v0/servers/%s/versions/%s", encodedName, encodedVersion)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
 
-	resp, err := c.makeRequest("PUT", endpoint, data, token)
+	resp, err := c.makeRequestWithHeaders(method, endpoint, data, token, headers)
 	if err != nil {
-		return fmt.Errorf("update server request failed: %w", err)
+		return 0, nil, fmt.Errorf("update server request failed: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readResponseBody(resp)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	return resp.StatusCode, body, nil
+}
 
+// Server statuses set through the edit endpoint
+const (
+	ServerStatusActive     = "active"
+	ServerStatusDeprecated = "deprecated"
+	ServerStatusDeleted    = "deleted"
+)
+
+// SetServerStatus fetches a server version (or "latest"), sets its status and PUTs the manifest back.
+// The server schema has no field for a reason, so it is passed as a query parameter next to the status.
+func (c *MCPXClient) SetServerStatus(serverName, version, status, reason, token string, jsonOutput bool) error {
+	if version == "" {
+		version = "latest"
+	}
 	if !jsonOutput {
-		fmt.Printf("Status Code: %d\n", resp.StatusCode)
+		fmt.Printf("=== Set Server Status %s/%s: %s ===\n", serverName, version, status)
 	}
 
-	if resp.StatusCode == 200 {
-		if jsonOutput {
-			fmt.Println(string(body))
-		} else {
-			var updateResp map[string]string
-			if err := json.Unmarshal(body, &updateResp); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
-			fmt.Printf("✅ %s\n", updateResp["message"])
-			fmt.Printf("Server Name: %s\n", serverName)
-		}
-	} else {
-		if jsonOutput {
-			fmt.Println(string(body))
-		} else {
-			fmt.Printf("❌ Update failed: %s\n", string(body))
-		}
+	detail, statusCode, body, err := c.fetchServerVersion(serverName, version)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return &APIError{Op: fmt.Sprintf("get %s/%s", serverName, version), StatusCode: statusCode, Body: body}
+	}
+
+	// Registry-managed fields are not part of the manifest that is sent back
+	detail.ID = ""
+	detail.Meta = nil
+	detail.Status = status
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal server config: %w", err)
+	}
+
+	query := url.Values{"status": {status}}
+	if reason != "" {
+		query.Set("reason", reason)
+	}
+	statusCode, body, err = c.putServerVersion(serverName, detail.Version, data, query, token)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return &APIError{Op: "set status", StatusCode: statusCode, Body: body, Hint: authFailureHint(statusCode, serverName)}
 	}
 
+	if jsonOutput {
+		fmt.Println(string(body))
+		return nil
+	}
+	fmt.Printf("✅ Server version '%s/%s' is now %s\n", serverName, detail.Version, status)
+	if reason != "" {
+		fmt.Printf("Reason: %s\n", reason)
+	}
 	return nil
 }
 
@@ -1393,26 +6697,605 @@ func (c *MCPXClient) DeleteServer(serverName, version, token string, jsonOutput
 		_ = Body.Close()
 	}(response.Body)
 
-	body, err := io.ReadAll(response.Body)
+	body, err := c.readResponseBody(response)
 	if err != nil {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if response.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("server version not found: %s/%s", serverName, version)
+		return &APIError{Op: fmt.Sprintf("delete %s/%s", serverName, version), StatusCode: response.StatusCode, Body: body}
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("delete version failed with status %d: %s", response.StatusCode, string(body))
+		return &APIError{Op: "delete version", StatusCode: response.StatusCode, Body: body, Hint: authFailureHint(response.StatusCode, serverName)}
+	}
+
+	if jsonOutput {
+		fmt.Printf("{\"message\": \"Server version %s/%s deleted successfully\"}\n", serverName, version)
+	} else {
+		fmt.Printf("✅ Server version '%s/%s' deleted successfully\n", serverName, version)
+	}
+
+	return nil
+}
+
+// RestoreServer sets a soft-deleted or deprecated server version back to active.
+// A version the registry no longer returns cannot be restored, since it was removed rather than marked deleted.
+func (c *MCPXClient) RestoreServer(serverName, version, token string, jsonOutput bool) error {
+	err := c.SetServerStatus(serverName, version, ServerStatusActive, "", token, jsonOutput)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s was not found; the registry may hard-delete servers, which cannot be restored: %w", serverName, err)
+	}
+	return err
+}
+
+// runTokenCommand implements "token print [--yes-really]" and "token expiry" for moving a locally minted
+// token into CI secrets. Printing to a terminal requires --yes-really, since the token would end up on screen.
+func (c *MCPXClient) runTokenCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: mcpx-cli token print [--yes-really] | expiry")
+	}
+
+	config, err := c.loadAuthConfig()
+	if err != nil {
+		return err
+	}
+	if config.Token == "" {
+		return fmt.Errorf("no valid stored token (not logged in, or the token expired); run 'mcpx-cli login' first")
+	}
+
+	switch args[0] {
+	case "print":
+		var yesReally bool
+		printFlags := flag.NewFlagSet("token print", flag.ContinueOnError)
+		printFlags.BoolVar(&yesReally, "yes-really", false, "Print the token even when stdout is a terminal")
+		if err := printFlags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if isTerminal(os.Stdout) && !yesReally {
+			return fmt.Errorf("refusing to print the token to a terminal; pipe it into your secret store or pass --yes-really")
+		}
+		fmt.Fprintln(os.Stderr, "⚠️  WARNING: this token grants publish access as you. Anyone who sees it can act on your behalf.")
+		fmt.Fprintln(os.Stderr, "⚠️  Store it only in a secret manager, never in logs, shell history or source control.")
+		fmt.Println(config.Token)
+	case "expiry":
+		if config.ExpiresAt == 0 {
+			return fmt.Errorf("the stored token has no recorded expiry")
+		}
+		fmt.Println(time.Unix(config.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	default:
+		return fmt.Errorf("unknown token subcommand %q (expected print or expiry)", args[0])
+	}
+	return nil
+}
+
+// runConfigCommand implements "config set <key> <value>", "config get <key>" and "config list"
+func runConfigCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: mcpx-cli config set <key> <value> | get <key> | list")
+	}
+
+	settings, err := loadSettings()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "set":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: mcpx-cli config set <key> <value>")
+		}
+		if err := settings.Set(args[1], args[2]); err != nil {
+			return err
+		}
+		if err := saveSettings(settings); err != nil {
+			return fmt.Errorf("failed to save settings: %w", err)
+		}
+		fmt.Printf("✅ %s = %s\n", args[1], args[2])
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: mcpx-cli config get <key>")
+		}
+		value, ok := settings.Get(args[1])
+		if !ok {
+			return fmt.Errorf("%s is not set", args[1])
+		}
+		fmt.Println(value)
+	case "list":
+		for _, entry := range settings.Entries() {
+			fmt.Println(entry)
+		}
+	default:
+		return fmt.Errorf("unknown config subcommand %q (expected set, get or list)", args[0])
+	}
+	return nil
+}
+
+// splitArgs separates leading positional arguments from the flags that follow them
+func splitArgs(args []string) ([]string, []string) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
+
+// addListFlags registers the pagination and output flags shared by the list-style commands
+func addListFlags(fs *flag.FlagSet, opts *ListServersOptions) {
+	fs.StringVar(&opts.Cursor, "cursor", "", "Pagination cursor")
+	fs.IntVar(&opts.Limit, "page-size", defaultPageSize, "Number of servers the registry returns per page (overrides $"+defaultPageSizeEnvVar+" and the default-limit setting)")
+	fs.Func("limit", "Deprecated alias for --page-size", func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid page size %q", value)
+		}
+		fmt.Fprintln(os.Stderr, "Warning: --limit is deprecated, use --page-size (per page) or --count (total)")
+		opts.Limit = n
+		return nil
+	})
+	fs.IntVar(&opts.Count, "count", 0, "Total number of servers to fetch across pages (implies following cursors)")
+	fs.BoolVar(&opts.NoClientLimit, "no-client-limit", false, "Show a whole page even if the registry returned more servers than --page-size")
+	fs.BoolVar(&opts.All, "all", false, "Follow pagination cursors and return every page")
+	fs.BoolVar(&opts.JSON, "json", false, "Output in JSON format")
+}
+
+// defaultUpdateURL is the GitHub API endpoint of the latest CLI release, used by self-update
+const defaultUpdateURL = "https://api.github.com/repos/ai-mcpx/mcpx-cli/releases/latest"
+
+// Release is the part of a GitHub release used by self-update
+type Release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a downloadable file of a release
+type ReleaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// asset returns the release asset with the given name
+func (r Release) asset(name string) (ReleaseAsset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+// checksumsAsset returns the checksums file goreleaser attaches to every release
+func (r Release) checksumsAsset() (ReleaseAsset, bool) {
+	for _, asset := range r.Assets {
+		if strings.HasSuffix(asset.Name, "checksums.txt") {
+			return asset, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+// releaseArchiveName is the goreleaser archive of a release for one platform, e.g. mcpx-cli_1.2.0_linux_amd64.tar.gz
+func releaseArchiveName(tag, goos, goarch string) string {
+	return fmt.Sprintf("mcpx-cli_%s_%s_%s.tar.gz", strings.TrimPrefix(tag, "v"), goos, goarch)
+}
+
+// updateAvailable reports whether the release tag latest is newer than the current version. A current
+// version that is not a release, such as "dev", is always older.
+func updateAvailable(current, latest string) (bool, error) {
+	latestVersion, err := parseSemver(strings.TrimPrefix(latest, "v"))
+	if err != nil {
+		return false, fmt.Errorf("latest release has an invalid tag: %w", err)
+	}
+	currentVersion, err := parseSemver(strings.TrimPrefix(current, "v"))
+	if err != nil {
+		return true, nil
+	}
+	return compareSemver(latestVersion, currentVersion) > 0, nil
+}
+
+// fetchLatestRelease reads the release document at updateURL
+func (c *MCPXClient) fetchLatestRelease(updateURL string) (Release, error) {
+	body, err := c.download(updateURL)
+	if err != nil {
+		return Release{}, err
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return Release{}, fmt.Errorf("failed to parse release from %s: %w", updateURL, err)
+	}
+	if release.TagName == "" {
+		return Release{}, fmt.Errorf("release from %s has no tag_name", updateURL)
+	}
+	return release, nil
+}
+
+// download fetches an absolute URL, e.g. a release asset
+func (c *MCPXClient) download(rawURL string) ([]byte, error) {
+	resp, err := c.makeRequest("GET", rawURL, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("download of %s failed: %w", rawURL, err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Op: "download " + rawURL, StatusCode: resp.StatusCode, Body: body}
+	}
+	return body, nil
+}
+
+// verifyChecksum checks data against the SHA-256 recorded for name in a sha256sum-style checksums file
+func verifyChecksum(checksums []byte, name string, data []byte) error {
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if actual := fmt.Sprintf("%x", sum); !strings.EqualFold(actual, fields[0]) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, fields[0], actual)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum for %s in the release checksums", name)
+}
+
+// extractBinary returns the file named binaryName from a .tar.gz archive
+func extractBinary(archive []byte, binaryName string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open release archive: %w", err)
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("release archive does not contain %s", binaryName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read release archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == binaryName {
+			return io.ReadAll(reader)
+		}
+	}
+}
+
+// replaceExecutable atomically replaces the file at path with data, keeping its permissions. The new binary
+// is written next to it and renamed over it, so an interrupted update leaves the old binary in place.
+func replaceExecutable(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".mcpx-cli-update-*")
+	if err != nil {
+		return replaceError(path, err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return replaceError(path, err)
+	}
+
+	if runtime.GOOS == "windows" {
+		// A running executable cannot be overwritten on Windows, but it can be moved aside
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return replaceError(path, err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return replaceError(path, err)
+	}
+	return nil
+}
+
+// replaceError explains a failure to replace the executable, suggesting elevated rights for permission errors
+func replaceError(path string, err error) error {
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("permission denied replacing %s; re-run with elevated rights (e.g. sudo mcpx-cli self-update) or reinstall to a writable location: %w", path, err)
+	}
+	return fmt.Errorf("failed to replace %s: %w", path, err)
+}
+
+// SelfUpdate checks updateURL for a release newer than the running version and, unless checkOnly is set,
+// installs it over the running executable after verifying its checksum
+func (c *MCPXClient) SelfUpdate(updateURL string, checkOnly bool) error {
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to locate the running executable: %w", err)
+	}
+	return c.selfUpdate(updateURL, checkOnly, executable)
+}
+
+func (c *MCPXClient) selfUpdate(updateURL string, checkOnly bool, executable string) error {
+	fmt.Println("=== Self Update ===")
+	release, err := c.fetchLatestRelease(updateURL)
+	if err != nil {
+		return err
+	}
+	newer, err := updateAvailable(version, release.TagName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Current version: %s\n", version)
+	fmt.Printf("Latest version: %s\n", release.TagName)
+	if !newer {
+		fmt.Println("✅ mcpx-cli is up to date")
+		return nil
+	}
+	if checkOnly {
+		fmt.Printf("An update to %s is available; install it with: mcpx-cli self-update\n", release.TagName)
+		return nil
+	}
+
+	archiveName := releaseArchiveName(release.TagName, runtime.GOOS, runtime.GOARCH)
+	archiveAsset, ok := release.asset(archiveName)
+	if !ok {
+		return fmt.Errorf("release %s has no build for %s/%s (expected %s)", release.TagName, runtime.GOOS, runtime.GOARCH, archiveName)
+	}
+	checksumsAsset, ok := release.checksumsAsset()
+	if !ok {
+		return fmt.Errorf("release %s has no checksums file; refusing to install an unverified binary", release.TagName)
+	}
+
+	archive, err := c.download(archiveAsset.BrowserDownloadURL)
+	if err != nil {
+		return err
+	}
+	checksums, err := c.download(checksumsAsset.BrowserDownloadURL)
+	if err != nil {
+		return err
+	}
+	if err := verifyChecksum(checksums, archiveName, archive); err != nil {
+		return err
+	}
+
+	binaryName := "mcpx-cli"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	binary, err := extractBinary(archive, binaryName)
+	if err != nil {
+		return err
+	}
+	if err := replaceExecutable(executable, binary); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Updated %s from %s to %s\n", executable, version, release.TagName)
+	return nil
+}
+
+// Update notices: the latest release is checked in the background at most once per updateCheckInterval
+const (
+	updateCheckInterval = 24 * time.Hour
+	updateCheckTimeout  = 5 * time.Second
+	// updateCheckWait is how long a finished command waits for a check that is still in flight
+	updateCheckWait     = 300 * time.Millisecond
+	noUpdateCheckEnvVar = "MCPX_NO_UPDATE_CHECK"
+)
+
+// updateNotifier checks for a newer release while a command runs and reports it once the command is done
+type updateNotifier struct {
+	// latest is the release tag known from the last completed check
+	latest string
+	// result receives the tag found by a check started by this run; nil when no check is due
+	result chan string
+	// started is when that check started, recorded as the time of the check once it completes
+	started time.Time
+}
+
+// startUpdateCheck starts a background release check if none ran within updateCheckInterval. Checks use their
+// own client with a short timeout, so they never share retries or the deadline of the command.
+func startUpdateCheck(updateURL string, now time.Time) *updateNotifier {
+	state, err := loadState()
+	if err != nil {
+		return nil
+	}
+	notifier := &updateNotifier{latest: state.LatestVersion}
+	if now.Sub(time.Unix(state.LastUpdateCheck, 0)) < updateCheckInterval {
+		return notifier
+	}
+
+	checker := NewMCPXClient("")
+	checker.httpClient.Timeout = updateCheckTimeout
+	checker.logger = NewLogger(io.Discard, LogFormatText, false)
+	checker.cacheDir = ""
+	notifier.result = make(chan string, 1)
+	notifier.started = now
+	go func() {
+		release, err := checker.fetchLatestRelease(updateURL)
+		if err != nil {
+			close(notifier.result)
+			return
+		}
+		notifier.result <- release.TagName
+	}()
+	return notifier
+}
+
+// finish records the check started by this run and prints a notice when a newer release is known. A check
+// still in flight is given updateCheckWait to complete; after that it is abandoned and not recorded, so the
+// next run checks again instead of waiting a day without a result.
+func (n *updateNotifier) finish(logger *Logger) {
+	if n == nil {
+		return
+	}
+	if n.result != nil {
+		timer := time.NewTimer(updateCheckWait)
+		defer timer.Stop()
+		select {
+		case latest, ok := <-n.result:
+			// A failed check is recorded too, so an unreachable release server is not retried on every run
+			if state, err := loadState(); err == nil {
+				state.LastUpdateCheck = n.started.Unix()
+				if ok {
+					state.LatestVersion = latest
+					n.latest = latest
+				}
+				_ = saveState(state)
+			}
+		case <-timer.C:
+		}
+	}
+	if n.latest == "" {
+		return
+	}
+	if newer, err := updateAvailable(version, n.latest); err == nil && newer {
+		logger.Info(fmt.Sprintf("A new version %s is available (current: %s); upgrade with: mcpx-cli self-update", n.latest, version))
+	}
+}
+
+// updateCheckEnabled reports whether background update checks may run. They are skipped for development
+// builds, which every release would look newer than, and when disabled by flag or environment.
+func updateCheckEnabled(noUpdateCheck bool, command string) bool {
+	if noUpdateCheck || os.Getenv(noUpdateCheckEnvVar) != "" {
+		return false
+	}
+	if _, err := parseSemver(strings.TrimPrefix(version, "v")); err != nil {
+		return false
+	}
+	return command != "self-update"
+}
+
+// commandHelp is the focused usage of one subcommand, printed by "mcpx-cli <command> --help"
+type commandHelp struct {
+	Synopsis    string
+	Description string
+	Examples    []string
+}
+
+var commandHelps = map[string]commandHelp{
+	"login": {"login [--method <method>]", "Log in and store a registry token.",
+		[]string{"mcpx-cli login --method anonymous", "mcpx-cli login --method github-oauth"}},
+	"logout": {"logout", "Clear stored credentials.", []string{"mcpx-cli logout"}},
+	"token": {"token print [--yes-really] | expiry", "Print the stored token (for CI secrets) or its expiry time.",
+		[]string{"mcpx-cli token print | gh secret set MCPX_TOKEN", "mcpx-cli token expiry"}},
+	"config": {"config set|get|list [key] [value]", "Manage settings such as registry aliases (registry.<name>), the default page size (default-limit) and retries (retries, retry-backoff).",
+		[]string{"mcpx-cli config set registry.prod https://registry.example.com", "mcpx-cli config set default-limit 100", "mcpx-cli config set retries 5", "mcpx-cli config list"}},
+	"self-update": {"self-update [--check-only] [--url <release-url>]", "Install the latest mcpx-cli release over the running binary after verifying its checksum.",
+		[]string{"mcpx-cli self-update --check-only", "sudo mcpx-cli self-update"}},
+	"check": {"check --endpoint <path> [--token <token>]", "Check that the registry accepts the current credentials, not just that a token is stored. The registry API has no standard endpoint for this, so --endpoint names a GET endpoint of the registry that requires authentication.",
+		[]string{"mcpx-cli check --endpoint \"$PROTECTED_PATH\"", "mcpx-cli --registry prod check --endpoint \"$PROTECTED_PATH\" --token \"$MCPX_TOKEN\""}},
+	"health": {"health [--exit-code-only]", "Check api health status.",
+		[]string{"mcpx-cli health", "until mcpx-cli health --exit-code-only; do sleep 1; done"}},
+	"servers": {"servers [flags]", "List servers in the registry.",
+		[]string{"mcpx-cli servers --count 100 --page-size 50", "mcpx-cli servers --all --filter foo --id-only", "mcpx-cli servers --json --detailed", "mcpx-cli servers --all --output csv > servers.csv", "mcpx-cli servers --all --field-selector status=active,source=github"}},
+	"search": {"search <query> [flags]", "Search servers by name or description.",
+		[]string{"mcpx-cli search filesystem --count 5"}},
+	"find": {"find --repo <owner/repo> [--json]", "Find the servers published from a repository.",
+		[]string{"mcpx-cli find --repo example/test-server-node"}},
+	"versions": {"versions <name> [flags]", "List all versions of a server.",
+		[]string{"mcpx-cli versions <name> --all", "mcpx-cli versions <name> --order asc"}},
+	"server": {"server <name> [flags] | server --name-like <text> [flags]", "Get server details by name, or pick the server from those whose name contains some text.",
+		[]string{"mcpx-cli server <name> --json", "mcpx-cli server <name> --short", "mcpx-cli server <name> --install-command", "mcpx-cli server <name> --install-command --prefer-registry docker", "mcpx-cli server <name> --latest", "mcpx-cli server --name-like filesystem"}},
+	"describe": {"describe <name> [--json] [--prefer-registry <type>]", "Show a server's details, install commands and full version history.",
+		[]string{"mcpx-cli describe <name>", "mcpx-cli describe <name> --json"}},
+	"first": {"first [--filter <text>] [--repository-url <text>] [--json]", "Show the details of the first listed server, or of the first one matching the filters, as a single object.",
+		[]string{"mcpx-cli first --json", "mcpx-cli first --filter filesystem --json | jq -r .name"}},
+	"count": {"count [--json]", "Print the total number of servers. One server is requested when the registry reports metadata.total; otherwise every page is fetched and counted.",
+		[]string{"mcpx-cli count", "mcpx-cli count --json | jq .total"}},
+	"packages": {"packages <name> [--json]", "Show only the packages of a server, with their environment variables and arguments.",
+		[]string{"mcpx-cli packages <name>", "mcpx-cli packages <name> --json | jq '.[0].identifier'"}},
+	"remotes": {"remotes <name> [--json]", "Show only the remote endpoints of a server, with their transport, URL and headers.",
+		[]string{"mcpx-cli remotes <name>", "mcpx-cli remotes <name> --json | jq -r '.[0].url'"}},
+	"open": {"open <name> [--print]", "Open the server's repository in the default browser.",
+		[]string{"mcpx-cli open <name>", "mcpx-cli open <name> --print"}},
+	"exists": {"exists <id>", "Check whether a server exists (exit code 0 = exists, 4 = not found).",
+		[]string{"mcpx-cli exists <id>"}},
+	"copy": {"copy <name> --new-version <version> [--output <server.json>] [--force]", "Copy the latest manifest of a server with a new version.",
+		[]string{"mcpx-cli copy <name> --new-version 2.0.0 --output server.json"}},
+	"rename": {"rename <name> --to <new-name> --yes [flags]", "Republish the latest manifest of a server under a new name.",
+		[]string{"mcpx-cli rename io.modelcontextprotocol.anonymous/weather --to io.github.owner/weather --yes"}},
+	"export": {"export <name> [--output <server.json>] | export --all --output-dir <dir> [--detailed] [--force]", "Write the latest manifest of a server, or of every server, to disk.",
+		[]string{"mcpx-cli export <name> --output server.json", "mcpx-cli export --all --output-dir ./backup --detailed"}},
+	"validate": {"validate <server.json> [flags]", "Validate a server manifest locally.",
+		[]string{"mcpx-cli validate server.json", "mcpx-cli validate server.json --check-urls"}},
+	"roundtrip": {"roundtrip <server.json>", "Decode a manifest the way the CLI does, encode it again and show the fields that are dropped (-), changed (~) or added (+). Exits non-zero when data would be lost.",
+		[]string{"mcpx-cli roundtrip server.json"}},
+	"lint": {"lint <server.json> [flags]", "Report best-practice warnings for a server manifest.",
+		[]string{"mcpx-cli lint server.json --fail-on warning"}},
+	"update": {"update <name> <server.json> [flags] | update <name> --patch --set <path=value> [--version <v>]", "Update a server version from a manifest, or change single fields with a JSON merge patch.",
+		[]string{"mcpx-cli update <name> server.json --json", "mcpx-cli update <name> server.json --set packages.0.version=2.0.0", "mcpx-cli update <name> --patch --set status=deprecated --version 1.0.0"}},
+	"publish": {"publish <server.json> [flags] | publish --interactive | publish --dir <dir>", "Publish a server to the registry.",
+		[]string{"mcpx-cli publish server.json", "mcpx-cli publish server.json --set-version 1.2.3", "mcpx-cli publish --dir ./manifests --recursive", "mcpx-cli publish --dir ./manifests --summary json", "mcpx-cli publish --interactive", "mcpx-cli publish --interactive --accept-defaults --set name=io.github.me/my-server"}},
+	"import": {"import --dir <dir> [flags]", "Publish every manifest in a directory, e.g. one written by export --all.",
+		[]string{"mcpx-cli --base-url https://new.example.com import --dir ./backup --if-not-exists --continue-on-error"}},
+	"deprecate": {"deprecate <name> --reason <text> [flags]", "Mark a server version (default: latest) deprecated.",
+		[]string{"mcpx-cli deprecate <name> --reason \"Replaced by <other>\""}},
+	"restore": {"restore <name> [flags]", "Set a deleted or deprecated server version back to active.",
+		[]string{"mcpx-cli restore <name> --version 1.0.0"}},
+	"delete": {"delete <server-name> <version> [flags]", "Delete a server version by name and version.",
+		[]string{"mcpx-cli delete <server-name> <version> --json"}},
+}
+
+// printCommandUsage prints the usage, flags and examples of the command fs belongs to
+func printCommandUsage(fs *flag.FlagSet) {
+	out := fs.Output()
+	help, ok := commandHelps[fs.Name()]
+	if !ok {
+		help = commandHelp{Synopsis: fs.Name() + " [flags]"}
+	}
+	_, _ = fmt.Fprintf(out, "Usage: mcpx-cli [global flags] %s\n", help.Synopsis)
+	if help.Description != "" {
+		_, _ = fmt.Fprintf(out, "\n%s\n", help.Description)
+	}
+
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		_, _ = fmt.Fprintln(out, "\nFlags:")
+		fs.PrintDefaults()
+	}
+
+	if len(help.Examples) > 0 {
+		_, _ = fmt.Fprintln(out, "\nExamples:")
+		for _, example := range help.Examples {
+			_, _ = fmt.Fprintf(out, "  %s\n", example)
+		}
 	}
+	_, _ = fmt.Fprintln(out, "\nRun 'mcpx-cli help' for global flags and all commands.")
+}
 
-	if jsonOutput {
-		fmt.Printf("{\"message\": \"Server version %s/%s deleted successfully\"}\n", serverName, version)
-	} else {
-		fmt.Printf("✅ Server version '%s/%s' deleted successfully\n", serverName, version)
+// handleHelp makes fs print its command's focused usage, and prints it to stdout and exits when args ask
+// for help. It runs before positional arguments are checked, so "mcpx-cli publish --help" needs no file.
+func handleHelp(fs *flag.FlagSet, args []string) {
+	fs.Usage = func() { printCommandUsage(fs) }
+	for _, arg := range args {
+		if arg == "--" {
+			return
+		}
+		if arg == "-h" || arg == "-help" || arg == "--help" {
+			fs.SetOutput(os.Stdout)
+			fs.Usage()
+			os.Exit(0)
+		}
 	}
-
-	return nil
 }
 
 func printUsage() {
@@ -1422,41 +7305,131 @@ func printUsage() {
 	fmt.Println("  mcpx-cli [global flags] <command> [command flags]")
 	fmt.Println()
 	fmt.Println("Global Flags:")
-	fmt.Println("  --base-url=string    Base url of the mcpx api (default: http://localhost:8080)")
+	fmt.Println("  --base-url=string    Base url of the mcpx api (default: $MCPX_BASE_URL, or http://localhost:8080)")
+	fmt.Println("  --auth-url=string    Base url of the token endpoints when auth is a separate service (default: <base-url>/v0/auth)")
+	fmt.Println("  --max-response-size=size  Maximum response body size to read, e.g. 512KB, 64MiB (default: 64MiB)")
+	fmt.Println("  --log-format=string  Format of log messages on stderr: text or json (default: text)")
+	fmt.Println("  --verbose            Log requests and other diagnostic messages to stderr")
+	fmt.Println("  --no-cache           Do not use or store ETag-cached responses")
+	fmt.Println("  --no-compression     Send no Accept-Encoding and read uncompressed responses (for debugging corrupt bodies)")
+	fmt.Println("  --quiet              Do not report progress of bulk operations (publish --dir, import, export --all, --detailed)")
+	fmt.Println("  --registry=string    Registry alias defined with 'config set registry.<name>', or a base url")
+	fmt.Println("  --no-auth            Send requests without a token, even with --token or a stored login (debug 401/403)")
+	fmt.Println("  --tls-min-version=version  Refuse registries that do not support at least TLS 1.2 or 1.3 (default: Go's secure default)")
+	fmt.Println("  --client-cert=path --client-key=path  Present this PEM certificate and key to registries that require mutual TLS")
+	fmt.Println("  --no-update-check    Do not check for a newer mcpx-cli release (also MCPX_NO_UPDATE_CHECK=1)")
+	fmt.Println("  --retries int        Retry requests that time out or are refused, with exponential backoff; POST only before it is sent (default: retries setting, or 0)")
+	fmt.Println("  --retry-backoff=duration  Delay before the first retry, doubled for each further one (default: retry-backoff setting, or 500ms)")
+	fmt.Println("  --timeout-per-retry duration  Time limit for each request attempt; a slow attempt is retried (default: 30s total per request)")
+	fmt.Println("  --deadline duration  Time limit for the whole command across all attempts (default: none)")
+	fmt.Println("  --tee=json:path      Also write the JSON document of servers, search or server to a file, next to the text output")
+	fmt.Println("  --env-file=path      Load KEY=VALUE pairs (MCPX_BASE_URL, MCPX_TIMEOUT, ...) from a .env file; the real environment wins")
 	fmt.Println("  --version            Show version information")
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  help                                Show this help message")
 	fmt.Println("  version                             Show version information")
+	fmt.Println("  self-update [--check-only]          Install the latest release of mcpx-cli over this binary")
 	fmt.Println("  login [--method]                    Login with specified method (anonymous, github-oauth, github-oidc)")
 	fmt.Println("  logout                              Logout and clear stored credentials")
-	fmt.Println("  health                              Check api health status")
+	fmt.Println("  token print [--yes-really] | expiry Print the stored token (for CI secrets) or its expiry time")
+	fmt.Println("  health [--exit-code-only]           Check api health status (exit code only: 0 healthy, 1 unhealthy, 3 unreachable)")
+	fmt.Println("  check --endpoint [--token]          Check that the registry accepts the current credentials (token or stored login)")
+	fmt.Println("  config set|get|list [key] [value]   Manage settings: registry aliases (registry.<name>), default-limit, retries, retry-backoff, update-url")
 	fmt.Println("  servers                             List all servers")
+	fmt.Println("  search <query>                      Search servers by name or description")
+	fmt.Println("  versions <name>                     List all versions of a server")
+	fmt.Println("  find --repo <owner/repo> [--json]   Find the servers published from a repository")
 	fmt.Println("  server <name> [--json]              Get server details by name")
+	fmt.Println("  server --name-like <text>           Pick a server whose name contains <text> and show its details")
+	fmt.Println("  describe <name> [--json]            Show details, install commands and version history of a server")
+	fmt.Println("  first [--filter] [--json]           Show the details of the first (matching) server as a single object")
+	fmt.Println("  count [--json]                      Print the total number of servers without downloading every page")
+	fmt.Println("  packages <name> [--json]            Show only the packages of a server (install details)")
+	fmt.Println("  remotes <name> [--json]             Show only the remote endpoints of a server (transport, URL, headers)")
+	fmt.Println("  open <name> [--print]               Open the server's repository in the default browser")
+	fmt.Println("  exists <id>                         Check whether a server exists (exit code 0 = exists, 4 = not found)")
+	fmt.Println("  copy <name> --new-version <version> [--output]  Copy the latest manifest of a server with a new version")
+	fmt.Println("  rename <name> --to <new-name> --yes        Republish the latest manifest of a server under a new name")
+	fmt.Println("  export <name> [--output] | export --all --output-dir <dir>  Write server manifests to disk, e.g. for backups")
+	fmt.Println("  import --dir <dir> [--continue-on-error] [--if-not-exists] [--summary json]  Publish every manifest in a directory")
 	fmt.Println("  update <name> <server.json> [--token] [--json]  Update a server by name")
+	fmt.Println("  update <name> --patch --set <path=value>  Change single fields with a JSON merge patch (PUT fallback on 405)")
+	fmt.Println("  deprecate <name> --reason <text> [--version] [--token] [--json]  Mark a server version (default: latest) deprecated")
+	fmt.Println("  restore <name> [--version] [--token] [--json]  Set a deleted or deprecated server version back to active")
 	fmt.Println("  delete <server-name> <version> [--token] [--json] Delete a server version by name and version (uses stored token if available)")
+	fmt.Println("  validate <server.json> [--check-urls]  Validate a server manifest locally")
+	fmt.Println("  roundtrip <server.json>             Show the manifest fields the CLI would drop or change when rewriting it")
+	fmt.Println("  lint <server.json> [--fail-on] [--check-urls]  Report best-practice warnings for a server manifest")
 	fmt.Println("  publish <server.json>               Publish a server to the registry")
 	fmt.Println("  publish --interactive               Interactive mode to create and publish a server (supports npm, PyPI, wheel, binary, docker, oci, mcpb)")
 	fmt.Println()
 	fmt.Println("Authentication Flags:")
-	fmt.Println("  --method string      Authentication method (anonymous, github-oauth, github-oidc) (default: anonymous)")
+	fmt.Println("  --method string      Authentication method (anonymous, github-oauth, github-oidc) (default: $MCPX_AUTH_METHOD or anonymous)")
 	fmt.Println()
-	fmt.Println("Server List Flags:")
+	fmt.Println("Server List Flags (servers, search, versions):")
 	fmt.Println("  --cursor string      Pagination cursor")
-	fmt.Println("  --limit int          Maximum number of servers to return (default: 30)")
+	fmt.Println("  --page-size int      Number of servers the registry returns per page (default: 30)")
+	fmt.Println("  --count int          Total number of servers to fetch, following cursors until reached")
+	fmt.Println("  --limit int          Deprecated alias for --page-size")
+	fmt.Println("  --no-client-limit    Do not truncate a page to --page-size when the registry ignores the limit")
+	fmt.Println("  --all                Follow pagination cursors and return every page")
 	fmt.Println("  --json               Output servers details in JSON format")
 	fmt.Println("  --detailed           Include packages and remotes in JSON output (requires --json)")
+	fmt.Println("  --filter string      Only show servers whose name or description contains this text (servers)")
+	fmt.Println("  --id-only            Print only server IDs, one per line (servers)")
+	fmt.Println("  --repository-url string  Only show servers whose repository URL contains this text (servers)")
+	fmt.Println("  --updated-after/--updated-before time  Only show servers updated in this range; RFC3339, a date, or e.g. 7d ago (servers)")
+	fmt.Println("  --field-selector key=value  Filter server-side by status or source, falling back to client-side on 400 (servers)")
+	fmt.Println("  --group-by string    Group output by repository (servers)")
+	fmt.Println("  --save-cursor        Remember the next cursor for this registry (servers)")
+	fmt.Println("  --resume             Continue from the cursor saved with --save-cursor (servers)")
+	fmt.Println("  --pager              Page text output through $PAGER (default: less) when stdout is a terminal (servers)")
+	fmt.Println("  --head int           Show only the first N servers after fetching, unlike --page-size (servers)")
+	fmt.Println("  --tail int           Show only the last N servers after fetching (servers)")
+	fmt.Println("  --order string       Sort versions by semantic version: desc or asc (default: desc) (versions)")
+	fmt.Println("  --watch              Re-run the listing every --interval until Ctrl-C")
+	fmt.Println("  --interval duration  Refresh interval for --watch (default: 10s, minimum: 5s)")
+	fmt.Println("  --no-clear           In --watch mode, append timestamped output instead of clearing the screen")
 	fmt.Println()
 	fmt.Println("Server Detail Flags:")
 	fmt.Println("  --json               Output server details in JSON format")
+	fmt.Println("  --install-command    Print only the derived (heuristic) install command, one per package")
+	fmt.Println("  --prefer-registry    Keep only the install command of this package registry, e.g. docker")
+	fmt.Println("  --short              Print only name and version, description and repository")
+	fmt.Println()
+	fmt.Println("Copy Flags:")
+	fmt.Println("  --new-version string Version to set on the copied manifest (required)")
+	fmt.Println("  --output string      File to write the manifest to (default: stdout)")
 	fmt.Println()
 	fmt.Println("Update Flags:")
 	fmt.Println("  --token string       Authentication token (required for io.github.* servers)")
 	fmt.Println("  --json               Output result in JSON format")
+	fmt.Println("  --set path=value     Set a server field by dotted path before sending (repeatable)")
 	fmt.Println()
 	fmt.Println("Publish Flags:")
 	fmt.Println("  --token string       Authentication token (required for io.github.* servers)")
 	fmt.Println("  --interactive        Interactive mode to create server configuration")
+	fmt.Println("  --preview-full       With --interactive, print the complete manifest before the publish confirmation")
+	fmt.Println("  --accept-defaults    With --interactive, take every prompt's default without reading stdin and publish")
+	fmt.Println("  --json               Output the result (success, statusCode, serverId, ...) in JSON format")
+	fmt.Println("  --dir string         Publish every *.json manifest in a directory and print a summary")
+	fmt.Println("  --recursive          With --dir, include subdirectories")
+	fmt.Println("  --allow-nonsemver    Do not warn when the version is not a semantic version")
+	fmt.Println("  --idempotency-key string  Idempotency-Key header value, for retries across processes (default: new UUID)")
+	fmt.Println("  --body-file string   Pre-built request body (e.g. a PublishRequest with x-publisher) to publish")
+	fmt.Println("  --raw                Send the file verbatim, without parsing or re-encoding it")
+	fmt.Println("  --schema-version v1|v2  Read the manifest as a bare server manifest (v1) or a PublishRequest (v2) (default: detect)")
+	fmt.Println("  --publisher-meta key=value  Add an entry to the x-publisher metadata (repeatable)")
+	fmt.Println("  --set-version string Publish with this version instead of the one in the file")
+	fmt.Println("  --set-name string    Publish under this name instead of the one in the file")
+	fmt.Println("  --set path=value     Set a server field by dotted path, e.g. repository.url=..., before publishing (repeatable)")
+	fmt.Println()
+	fmt.Println("Validate Flags:")
+	fmt.Println("  --allow-nonsemver    Accept versions that are not semantic versions")
+	fmt.Println()
+	fmt.Println("Lint Flags:")
+	fmt.Println("  --fail-on string     Exit non-zero when a finding has at least this severity: info, warning, error (default: never)")
 	fmt.Println()
 	fmt.Println("Delete Flags:")
 	fmt.Println("  --token string       Authentication token (optional)")
@@ -1467,20 +7440,39 @@ func printUsage() {
 	fmt.Println("  mcpx-cli login --method github-oauth                        # Login with GitHub OAuth")
 	fmt.Println("  mcpx-cli logout                                             # Logout and clear credentials")
 	fmt.Println("  mcpx-cli health")
-	fmt.Println("  mcpx-cli servers --limit 10")
+	fmt.Println("  mcpx-cli servers --count 100 --page-size 50")
 	fmt.Println("  mcpx-cli servers --json --detailed")
+	fmt.Println("  mcpx-cli servers --watch --interval 30s")
+	fmt.Println("  mcpx-cli servers --all --json")
+	fmt.Println("  mcpx-cli servers --all --filter foo --id-only")
+	fmt.Println("  mcpx-cli servers --all --tail 5")
+	fmt.Println("  mcpx-cli servers --page-size 10 --save-cursor && mcpx-cli servers --resume --save-cursor")
+	fmt.Println("  mcpx-cli search filesystem --count 5")
+	fmt.Println("  mcpx-cli versions <name> --all")
+	fmt.Println("  mcpx-cli find --repo example/test-server-node               # Is my repo already registered?")
 	fmt.Println("  mcpx-cli server <name> [--json]")
+	fmt.Println("  mcpx-cli server <name> --short                              # Quick glance: name, version, repo")
+	fmt.Println("  mcpx-cli server <name> --install-command                    # e.g. npx @scope/pkg@1.0.0")
+	fmt.Println("  mcpx-cli exists <id>                                        # Exit code 0 = exists, 4 = not found")
+	fmt.Println("  mcpx-cli copy <name> --new-version 2.0.0 --output server.json  # Bump and republish")
 	fmt.Println("  mcpx-cli update <name> server.json --token your_token       # With authentication")
 	fmt.Println("  mcpx-cli update <name> server.json                          # Without authentication")
 	fmt.Println("  mcpx-cli update <name> server.json --json                   # JSON output")
+	fmt.Println("  mcpx-cli update <name> --patch --set status=deprecated      # Change one field only")
 	fmt.Println("  mcpx-cli delete <server-name> <version> --token your_token  # With authentication")
 	fmt.Println("  mcpx-cli delete <server-name> <version>                     # Without authentication")
 	fmt.Println("  mcpx-cli delete <server-name> <version> --json              # JSON output")
+	fmt.Println("  mcpx-cli validate server.json                               # Check a manifest before publishing")
+	fmt.Println("  mcpx-cli lint server.json --fail-on warning                 # Strict best-practice check for CI")
 	fmt.Println("  mcpx-cli publish server.json --token your_github_token      # GitHub projects")
 	fmt.Println("  mcpx-cli publish server.json                                # Non-GitHub projects")
+	fmt.Println("  mcpx-cli publish --dir ./manifests --recursive              # Publish a monorepo's manifests")
+	fmt.Println("  mcpx-cli publish --body-file request.json --raw             # Send a pre-built body as-is")
 	fmt.Println("  mcpx-cli publish --interactive --token your_github_token    # GitHub projects")
 	fmt.Println("  mcpx-cli publish --interactive                              # Non-GitHub projects")
 	fmt.Println("  mcpx-cli --base-url=http://localhost:8080 servers")
+	fmt.Println("  mcpx-cli config set registry.prod https://registry.example.com")
+	fmt.Println("  mcpx-cli --registry prod servers")
 }
 
 func main() {
@@ -1502,12 +7494,59 @@ func main() {
 	}
 
 	var baseURL string
+	var maxResponseSize string
+	var logFormat string
+	var verbose bool
 	var globalFlags = flag.NewFlagSet("global", flag.ContinueOnError)
-	globalFlags.StringVar(&baseURL, "base-url", defaultBaseURL, "Base url of the mcpx api")
+	globalFlags.StringVar(&baseURL, "base-url", defaultBaseURL, "Base url of the mcpx api; $"+baseURLEnvVar+" replaces the default")
+	globalFlags.StringVar(&maxResponseSize, "max-response-size", "64MiB", "Maximum size of a response body the CLI will read")
+	globalFlags.StringVar(&logFormat, "log-format", LogFormatText, "Format of log messages on stderr (text, json)")
+	globalFlags.BoolVar(&verbose, "verbose", false, "Log requests and other diagnostic messages to stderr")
+	var noCache bool
+	globalFlags.BoolVar(&noCache, "no-cache", false, "Do not use or store ETag-cached responses")
+	var registry string
+	globalFlags.StringVar(&registry, "registry", "", "Registry alias from the settings file, or a base url")
+	var noCompression, quiet bool
+	globalFlags.BoolVar(&quiet, "quiet", false, "Do not report progress of bulk operations on stderr")
+	globalFlags.BoolVar(&noCompression, "no-compression", false, "Do not negotiate gzip; request and read uncompressed responses")
+	var authURL string
+	globalFlags.StringVar(&authURL, "auth-url", "", "Base url of the token endpoints when auth is served separately (default: <base-url>/v0/auth)")
+	var noAuth bool
+	globalFlags.BoolVar(&noAuth, "no-auth", false, "Send requests without a token, ignoring --token and the stored login")
+	var noUpdateCheck bool
+	globalFlags.BoolVar(&noUpdateCheck, "no-update-check", false, "Do not check for a newer mcpx-cli release (also $"+noUpdateCheckEnvVar+")")
+	var retries int
+	globalFlags.IntVar(&retries, "retries", 0, "Retry requests that time out or are refused this many times (default: the retries setting, or 0)")
+	var retryBackoff time.Duration
+	globalFlags.DurationVar(&retryBackoff, "retry-backoff", defaultRetryBackoff, "Delay before the first retry, doubled for every further one; the retry-backoff setting replaces the default")
+	var tlsMinVersion string
+	globalFlags.StringVar(&tlsMinVersion, "tls-min-version", "", "Refuse registries that do not support at least this TLS version (1.2 or 1.3; default: Go's secure default)")
+	var clientCert, clientKey string
+	globalFlags.StringVar(&clientCert, "client-cert", "", "PEM client certificate for registries that require mutual TLS (needs --client-key)")
+	globalFlags.StringVar(&clientKey, "client-key", "", "PEM private key of --client-cert")
+	var timeoutPerRetry, deadline time.Duration
+	globalFlags.DurationVar(&timeoutPerRetry, "timeout-per-retry", 0, "Time limit for each individual request attempt (e.g. 5s)")
+	globalFlags.DurationVar(&deadline, "deadline", 0, "Time limit for the whole command, across all attempts and retries (e.g. 1m)")
+
+	var sinks []outputSink
+	globalFlags.Func("tee", "Also write the command's JSON document to a file, as json:<path> (repeatable; servers, search, server)", func(spec string) error {
+		sink, err := parseOutputSink(spec)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+		return nil
+	})
+	var envFile string
+	globalFlags.StringVar(&envFile, "env-file", "", "Load KEY=VALUE pairs such as "+baseURLEnvVar+" from this file; variables already set in the environment win")
 
+	if path := envFileArg(globalFlags, os.Args[1:]); path != "" {
+		if err := loadEnvFile(path); err != nil {
+			log.Fatalf("Error: --env-file: %v", err)
+		}
+	}
 	if err := globalFlags.Parse(os.Args[1:]); err != nil {
-		fmt.Printf("Error parsing global flags: %v\n", err)
-		os.Exit(1)
+		log.Fatalf("Error parsing global flags: %v", err)
 	}
 	args := globalFlags.Args()
 
@@ -1516,54 +7555,382 @@ func main() {
 		os.Exit(1)
 	}
 
-	client := NewMCPXClient(baseURL)
+	if logFormat != LogFormatText && logFormat != LogFormatJSON {
+		log.Fatalf("Error: unsupported --log-format %q (expected text or json)", logFormat)
+	}
+	// Errors reported with the log package, including fatal ones, go through the logger from here on
+	logger := NewLogger(os.Stderr, logFormat, verbose)
+	if logFormat == LogFormatJSON {
+		log.SetFlags(0)
+		log.SetOutput(logWriter{logger: logger})
+	}
+
+	baseURLSet := false
+	globalFlags.Visit(func(f *flag.Flag) {
+		baseURLSet = baseURLSet || f.Name == "base-url"
+	})
+	if !baseURLSet && registry == "" && os.Getenv(baseURLEnvVar) != "" {
+		baseURL = os.Getenv(baseURLEnvVar)
+	}
+	if registry != "" {
+		if baseURLSet {
+			log.Fatalf("Error: --registry and --base-url cannot be combined")
+		}
+		settings, err := loadSettings()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		baseURL = resolveRegistry(settings, registry)
+	}
+
+	normalizedURL, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	client := NewMCPXClient(normalizedURL)
+	client.logger = logger
+	if authURL != "" {
+		if client.authURL, err = normalizeBaseURL(authURL); err != nil {
+			log.Fatalf("Error: --auth-url: %v", err)
+		}
+	}
+	if noCache {
+		client.cacheDir = ""
+	}
+	if noCompression {
+		client.disableCompression()
+	}
+	client.quiet = quiet
+	if len(sinks) > 0 && !teeCommands[args[0]] {
+		log.Fatalf("Error: --tee is not supported by %s (supported: servers, search, server)", args[0])
+	}
+	client.sinks = sinks
+	if tlsMinVersion != "" {
+		if err := client.setTLSMinVersion(tlsMinVersion); err != nil {
+			log.Fatalf("Error: --tls-min-version: %v", err)
+		}
+	}
+	if clientCert != "" || clientKey != "" {
+		if err := client.setClientCertificate(clientCert, clientKey); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if noAuth {
+		client.noAuth = true
+		client.logger.Debug("--no-auth: requests are sent without a token, overriding --token and the stored login")
+	}
+	maxSize, err := parseByteSize(maxResponseSize)
+	if err != nil {
+		log.Fatalf("Error: --max-response-size: %v", err)
+	}
+	client.maxResponseSize = maxSize
+	if err := applyRetrySettings(globalFlags, &retries, &retryBackoff); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	client.retries = retries
+	client.retryBackoff = retryBackoff
+	if timeoutPerRetry < 0 || deadline < 0 {
+		log.Fatalf("Error: --timeout-per-retry and --deadline must not be negative")
+	}
+	if value := os.Getenv(timeoutEnvVar); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			log.Fatalf("Error: %s must be a positive duration such as 45s, got %q", timeoutEnvVar, value)
+		}
+		client.httpClient.Timeout = timeout
+	}
+	if timeoutPerRetry > 0 {
+		// The per-attempt limit replaces the client's fixed timeout
+		client.httpClient.Timeout = 0
+		client.attemptTimeout = timeoutPerRetry
+	}
+	if deadline > 0 {
+		client.deadline = time.Now().Add(deadline)
+	}
 	command := args[0]
 
+	var notifier *updateNotifier
+	if updateCheckEnabled(noUpdateCheck, command) {
+		updateURL := defaultUpdateURL
+		if settings, err := loadSettings(); err == nil && settings.UpdateURL != "" {
+			updateURL = settings.UpdateURL
+		}
+		notifier = startUpdateCheck(updateURL, time.Now())
+	}
+
 	switch command {
 	case "help", "--help", "-h":
 		printUsage()
 	case "login":
 		var authMethod string
 		loginFlags := flag.NewFlagSet("login", flag.ExitOnError)
-		loginFlags.StringVar(&authMethod, "method", AuthMethodAnonymous, "Authentication method (anonymous, github-oauth, github-oidc)")
+		loginFlags.StringVar(&authMethod, "method", "", "Authentication method (anonymous, github-oauth, github-oidc) (default: $"+authMethodEnvVar+" or anonymous)")
+		handleHelp(loginFlags, args[1:])
 		if err := loginFlags.Parse(args[1:]); err != nil {
 			log.Fatalf("Error parsing login flags: %v", err)
 		}
+		if authMethod == "" {
+			method, err := defaultAuthMethod()
+			if err != nil {
+				log.Fatalf("Login failed: %v", err)
+			}
+			authMethod = method
+		}
 		if err := client.login(authMethod); err != nil {
 			log.Fatalf("Login failed: %v", err)
 		}
 	case "logout":
+		handleHelp(flag.NewFlagSet("logout", flag.ExitOnError), args[1:])
 		if err := client.logout(); err != nil {
 			log.Fatalf("Logout failed: %v", err)
 		}
+	case "config":
+		handleHelp(flag.NewFlagSet("config", flag.ExitOnError), args[1:])
+		if err := runConfigCommand(args[1:]); err != nil {
+			log.Fatalf("Config failed: %v", err)
+		}
+	case "token":
+		handleHelp(flag.NewFlagSet("token", flag.ExitOnError), args[1:])
+		if err := client.runTokenCommand(args[1:]); err != nil {
+			log.Fatalf("Token failed: %v", err)
+		}
+	case "self-update":
+		var checkOnly bool
+		var updateURL string
+		selfUpdateFlags := flag.NewFlagSet("self-update", flag.ExitOnError)
+		selfUpdateFlags.BoolVar(&checkOnly, "check-only", false, "Only report whether a newer release is available")
+		selfUpdateFlags.StringVar(&updateURL, "url", "", "Release document to check (default: the update-url setting, or the GitHub releases of mcpx-cli)")
+		handleHelp(selfUpdateFlags, args[1:])
+		if err := selfUpdateFlags.Parse(args[1:]); err != nil {
+			log.Fatalf("Error parsing self-update flags: %v", err)
+		}
+		if updateURL == "" {
+			settings, err := loadSettings()
+			if err != nil {
+				log.Fatalf("Self-update failed: %v", err)
+			}
+			updateURL = settings.UpdateURL
+		}
+		if updateURL == "" {
+			updateURL = defaultUpdateURL
+		}
+		if err := client.SelfUpdate(updateURL, checkOnly); err != nil {
+			log.Fatalf("Self-update failed: %v", err)
+		}
 	case "health":
+		var exitCodeOnly bool
+		healthFlags := flag.NewFlagSet("health", flag.ExitOnError)
+		healthFlags.BoolVar(&exitCodeOnly, "exit-code-only", false, "Print nothing; exit 0 when healthy, 1 when unhealthy, 3 when unreachable")
+		handleHelp(healthFlags, args[1:])
+		if err := healthFlags.Parse(args[1:]); err != nil {
+			log.Fatalf("Error parsing health flags: %v", err)
+		}
+		if exitCodeOnly {
+			os.Exit(client.HealthExitCode())
+		}
 		if err := client.Health(); err != nil {
 			log.Fatalf("Health check failed: %v", err)
 		}
+	case "check":
+		var token, endpoint string
+		checkFlags := flag.NewFlagSet("check", flag.ExitOnError)
+		checkFlags.StringVar(&token, "token", "", "Token to check (default: the stored login)")
+		checkFlags.StringVar(&endpoint, "endpoint", "", "GET endpoint of the registry that requires authentication (required)")
+		handleHelp(checkFlags, args[1:])
+		if err := checkFlags.Parse(args[1:]); err != nil {
+			log.Fatalf("Error parsing check flags: %v", err)
+		}
+		if err := client.CheckCredentials(token, endpoint); err != nil {
+			log.Fatalf("Check failed: %v", err)
+		}
 	case "servers":
-		var cursor string
-		var limit int
-		var jsonOutput bool
-		var detailed bool
+		var opts ListServersOptions
 		serversFlags := flag.NewFlagSet("servers", flag.ExitOnError)
-		serversFlags.StringVar(&cursor, "cursor", "", "Pagination cursor")
-		serversFlags.IntVar(&limit, "limit", 30, "Maximum number of servers to return")
-		serversFlags.BoolVar(&jsonOutput, "json", false, "Output servers details in JSON format")
-		serversFlags.BoolVar(&detailed, "detailed", false, "Include packages and remotes in JSON output (requires --json)")
+		addListFlags(serversFlags, &opts)
+		serversFlags.BoolVar(&opts.Detailed, "detailed", false, "Include packages and remotes in JSON output (requires --json)")
+		serversFlags.StringVar(&opts.Filter, "filter", "", "Only show servers whose name or description contains this text")
+		serversFlags.BoolVar(&opts.IDOnly, "id-only", false, "Print only server IDs, one per line")
+		serversFlags.StringVar(&opts.RepositoryURL, "repository-url", "", "Only show servers whose repository URL contains this text")
+		serversFlags.StringVar(&opts.GroupBy, "group-by", "", "Group output by field (repository)")
+		now := time.Now()
+		serversFlags.Func("updated-after", "Only show servers updated after this time (RFC3339, a date, or a duration ago such as 7d)", func(value string) (err error) {
+			opts.UpdatedAfter, err = parseTimeBound(value, now)
+			return err
+		})
+		serversFlags.Func("updated-before", "Only show servers updated before this time (RFC3339, a date, or a duration ago such as 7d)", func(value string) (err error) {
+			opts.UpdatedBefore, err = parseTimeBound(value, now)
+			return err
+		})
+		serversFlags.Func("field-selector", "Filter server-side by key=value pairs (status, source), comma-separated or repeated", func(value string) error {
+			selectors, err := parseFieldSelectors(value)
+			opts.FieldSelectors = append(opts.FieldSelectors, selectors...)
+			return err
+		})
+		serversFlags.IntVar(&opts.Head, "head", 0, "Show only the first N servers after fetching and filtering")
+		serversFlags.IntVar(&opts.Tail, "tail", 0, "Show only the last N servers after fetching and filtering")
+		var output string
+		serversFlags.StringVar(&output, "output", "text", "Output format: text or csv")
+		var watch bool
+		var interval time.Duration
+		var noClear bool
+		var usePager bool
+		serversFlags.BoolVar(&opts.SaveCursor, "save-cursor", false, "Remember the next cursor for this registry so --resume continues from it")
+		serversFlags.BoolVar(&opts.Resume, "resume", false, "Continue from the cursor saved with --save-cursor")
+		serversFlags.BoolVar(&usePager, "pager", false, "Page text output through $PAGER (default less) when writing to a terminal")
+		serversFlags.BoolVar(&watch, "watch", false, "Re-run the listing every --interval until interrupted")
+		serversFlags.DurationVar(&interval, "interval", 10*time.Second, "Refresh interval for --watch (minimum 5s)")
+		serversFlags.BoolVar(&noClear, "no-clear", false, "In --watch mode, append timestamped output instead of clearing the screen")
+		handleHelp(serversFlags, args[1:])
 		if err := serversFlags.Parse(args[1:]); err != nil {
 			log.Fatalf("Error parsing servers flags: %v", err)
 		}
-		if detailed && !jsonOutput {
-			fmt.Println("Error: --detailed flag requires --json flag")
+		if err := applyDefaultPageSize(serversFlags, &opts); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if opts.Detailed && !opts.JSON {
+			log.Fatalf("Error: --detailed flag requires --json flag")
+		}
+		if opts.IDOnly && opts.JSON {
+			log.Fatalf("Error: --id-only cannot be combined with --json")
+		}
+		switch output {
+		case "text":
+		case "csv":
+			if opts.JSON || opts.IDOnly || opts.GroupBy != "" {
+				log.Fatalf("Error: --output csv cannot be combined with --json, --id-only or --group-by")
+			}
+			opts.CSV = true
+		default:
+			log.Fatalf("Error: unsupported --output %q (expected text or csv)", output)
+		}
+		if opts.Head < 0 || opts.Tail < 0 || (opts.Head > 0 && opts.Tail > 0) {
+			log.Fatalf("Error: --head and --tail take a positive count and cannot be combined")
+		}
+		if opts.GroupBy != "" && opts.GroupBy != "repository" {
+			log.Fatalf("Error: unsupported --group-by %q (expected repository)", opts.GroupBy)
+		}
+		if opts.GroupBy != "" && opts.Detailed {
+			log.Fatalf("Error: --group-by cannot be combined with --detailed")
+		}
+		if (opts.SaveCursor || opts.Resume) && (opts.All || opts.Count > 0 || watch) {
+			log.Fatalf("Error: --save-cursor and --resume page through one page at a time and cannot be combined with --all, --count or --watch")
+		}
+		if opts.Resume && opts.Cursor != "" {
+			log.Fatalf("Error: --resume cannot be combined with --cursor")
+		}
+		if usePager && watch {
+			log.Fatalf("Error: --pager cannot be combined with --watch")
+		}
+		if watch {
+			if interval < minWatchInterval {
+				client.logger.Warn(fmt.Sprintf("--interval %s is below the minimum, using %s", interval, minWatchInterval))
+				interval = minWatchInterval
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			style := watchClear
+			switch {
+			case opts.JSON:
+				style = watchPlain
+			case noClear:
+				style = watchTimestamp
+			}
+			watchLoop(ctx, interval, style, func() error {
+				return client.ListServers(opts)
+			})
+			break
+		}
+		stopPager := func() {}
+		if usePager && !opts.JSON && !opts.CSV {
+			stop, err := startPager()
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			stopPager = stop
+		}
+		err := client.ListServers(opts)
+		stopPager()
+		if err != nil {
+			fatal(opts.JSON, "List servers failed", err)
+		}
+	case "search":
+		var opts ListServersOptions
+		searchFlags := flag.NewFlagSet("search", flag.ExitOnError)
+		addListFlags(searchFlags, &opts)
+		handleHelp(searchFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: search query is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli search <query> [--page-size <n>] [--count <n>] [--cursor <cursor>] [--all] [--json]")
+			os.Exit(1)
+		}
+		if err := searchFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing search flags: %v", err)
+		}
+		if err := applyDefaultPageSize(searchFlags, &opts); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if err := client.SearchServers(strings.Join(positional, " "), opts); err != nil {
+			fatal(opts.JSON, "Search failed", err)
+		}
+	case "find":
+		var repo string
+		var jsonOutput bool
+		findFlags := flag.NewFlagSet("find", flag.ExitOnError)
+		findFlags.StringVar(&repo, "repo", "", "Repository ID to look up, e.g. owner/repo")
+		findFlags.BoolVar(&jsonOutput, "json", false, "Output matching servers in JSON format")
+		handleHelp(findFlags, args[1:])
+		if err := findFlags.Parse(args[1:]); err != nil {
+			log.Fatalf("Error parsing find flags: %v", err)
+		}
+		if repo == "" {
+			log.Print("Error: --repo is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli find --repo <owner/repo> [--json]")
+			os.Exit(1)
+		}
+		if err := client.FindServersByRepository(repo, jsonOutput); err != nil {
+			fatal(jsonOutput, "Find failed", err)
+		}
+	case "versions":
+		var opts ListServersOptions
+		versionsFlags := flag.NewFlagSet("versions", flag.ExitOnError)
+		addListFlags(versionsFlags, &opts)
+		versionsFlags.StringVar(&opts.Order, "order", VersionOrderDesc, "Sort versions: desc or asc (semantic version, or publish date for other schemes)")
+		handleHelp(versionsFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli versions <name> [--page-size <n>] [--count <n>] [--cursor <cursor>] [--all] [--json]")
 			os.Exit(1)
 		}
-		if err := client.ListServers(cursor, limit, jsonOutput, detailed); err != nil {
-			log.Fatalf("List servers failed: %v", err)
+		if err := versionsFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing versions flags: %v", err)
+		}
+		if err := applyDefaultPageSize(versionsFlags, &opts); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if opts.Order != VersionOrderAsc && opts.Order != VersionOrderDesc {
+			log.Fatalf("Error: --order must be asc or desc")
+		}
+		if err := client.ListServerVersions(positional[0], opts); err != nil {
+			fatal(opts.JSON, "List versions failed", err)
 		}
 	case "server":
 		var jsonOutput bool
+		var installCmd bool
+		var shortOutput bool
 		serverFlags := flag.NewFlagSet("server", flag.ExitOnError)
 		serverFlags.BoolVar(&jsonOutput, "json", false, "Output server details in JSON format")
+		serverFlags.BoolVar(&installCmd, "install-command", false, "Print only the derived install command")
+		serverFlags.BoolVar(&shortOutput, "short", false, "Print a one-to-three line summary")
+		var preferRegistry string
+		serverFlags.StringVar(&preferRegistry, "prefer-registry", "", "Show only the install command of the first package of this registry type (e.g. npm, docker)")
+		var latest bool
+		serverFlags.BoolVar(&latest, "latest", false, "Resolve the latest version from the versions listing and show it")
+		var nameLike string
+		serverFlags.StringVar(&nameLike, "name-like", "", "Pick the server from those whose name contains this text, instead of giving its name")
+		handleHelp(serverFlags, args[1:])
 		var serverName string
 		var flagArgs []string
 		for i, arg := range args[1:] {
@@ -1574,16 +7941,337 @@ func main() {
 				serverName = arg
 			}
 		}
+		if err := serverFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing server flags: %v", err)
+		}
+		if serverName != "" && nameLike != "" {
+			log.Fatalf("Error: --name-like cannot be combined with a server name")
+		}
+		if nameLike != "" {
+			// The choice is prompted on stdout, so it is only offered when it cannot corrupt JSON output
+			selected, err := client.selectServerName(nameLike, isTerminal(os.Stdin) && isTerminal(os.Stdout) && !jsonOutput)
+			if err != nil {
+				fatal(jsonOutput, "Select server failed", err)
+			}
+			serverName = selected
+		}
 		if serverName == "" {
-			fmt.Println("Error: server ID is required")
-			fmt.Println("Usage: mcpx-cli server <name> [--json]")
+			log.Print("Error: server ID is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli server <name> [--json]")
+			fmt.Fprintln(os.Stderr, "   or: mcpx-cli server --name-like <text> [--json]")
 			os.Exit(1)
 		}
-		if err := serverFlags.Parse(flagArgs); err != nil {
-			log.Fatalf("Error parsing server flags: %v", err)
+		if latest && (installCmd || shortOutput) {
+			log.Fatalf("Error: --latest cannot be combined with --install-command or --short")
+		}
+		if installCmd {
+			if err := client.GetServerInstallCommand(serverName, preferRegistry); err != nil {
+				log.Fatalf("Get install command failed: %v", err)
+			}
+			break
+		}
+		if shortOutput {
+			if jsonOutput {
+				log.Fatalf("Error: --short and --json cannot be combined")
+			}
+			if err := client.GetServerShort(serverName); err != nil {
+				log.Fatalf("Get server failed: %v", err)
+			}
+			break
+		}
+		if err := client.GetServer(serverName, GetServerOptions{JSON: jsonOutput, PreferRegistry: preferRegistry, Latest: latest}); err != nil {
+			fatal(jsonOutput, "Get server failed", err)
+		}
+	case "describe":
+		var jsonOutput bool
+		describeFlags := flag.NewFlagSet("describe", flag.ExitOnError)
+		describeFlags.BoolVar(&jsonOutput, "json", false, "Output a composite object with the server, install commands and versions")
+		var preferRegistry string
+		describeFlags.StringVar(&preferRegistry, "prefer-registry", "", "Show only the install command of the first package of this registry type (e.g. npm, docker)")
+		handleHelp(describeFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli describe <name> [--json] [--prefer-registry <type>]")
+			os.Exit(1)
+		}
+		if err := describeFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing describe flags: %v", err)
+		}
+		if err := client.DescribeServer(positional[0], jsonOutput, preferRegistry); err != nil {
+			fatal(jsonOutput, "Describe server failed", err)
+		}
+	case "first":
+		var opts ListServersOptions
+		firstFlags := flag.NewFlagSet("first", flag.ExitOnError)
+		firstFlags.StringVar(&opts.Filter, "filter", "", "Take the first server whose name or description contains this text")
+		firstFlags.StringVar(&opts.RepositoryURL, "repository-url", "", "Take the first server whose repository URL contains this text")
+		firstFlags.StringVar(&opts.Cursor, "cursor", "", "Start listing at this pagination cursor")
+		firstFlags.BoolVar(&opts.JSON, "json", false, "Output the server detail as a JSON object")
+		handleHelp(firstFlags, args[1:])
+		if err := firstFlags.Parse(args[1:]); err != nil {
+			log.Fatalf("Error parsing first flags: %v", err)
+		}
+		if firstFlags.NArg() > 0 {
+			log.Print("Error: first takes no arguments; use --filter to pick a match")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli first [--filter <text>] [--repository-url <text>] [--json]")
+			os.Exit(1)
+		}
+		pageSize, err := configuredPageSize()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		opts.Limit = pageSize
+		if err := client.FirstServer(opts); err != nil {
+			fatal(opts.JSON, "Get first server failed", err)
+		}
+	case "count":
+		var jsonOutput bool
+		countFlags := flag.NewFlagSet("count", flag.ExitOnError)
+		countFlags.BoolVar(&jsonOutput, "json", false, "Output the count as a JSON object")
+		handleHelp(countFlags, args[1:])
+		if err := countFlags.Parse(args[1:]); err != nil {
+			log.Fatalf("Error parsing count flags: %v", err)
 		}
-		if err := client.GetServer(serverName, jsonOutput); err != nil {
-			log.Fatalf("Get server failed: %v", err)
+		pageSize, err := configuredPageSize()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if err := client.CountServers(pageSize, jsonOutput); err != nil {
+			fatal(jsonOutput, "Count servers failed", err)
+		}
+	case "packages":
+		var jsonOutput bool
+		packagesFlags := flag.NewFlagSet("packages", flag.ExitOnError)
+		packagesFlags.BoolVar(&jsonOutput, "json", false, "Output the packages array in JSON format")
+		handleHelp(packagesFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli packages <name> [--json]")
+			os.Exit(1)
+		}
+		if err := packagesFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing packages flags: %v", err)
+		}
+		if err := client.ListServerPackages(positional[0], jsonOutput); err != nil {
+			fatal(jsonOutput, "List packages failed", err)
+		}
+	case "remotes":
+		var jsonOutput bool
+		remotesFlags := flag.NewFlagSet("remotes", flag.ExitOnError)
+		remotesFlags.BoolVar(&jsonOutput, "json", false, "Output the remotes array in JSON format")
+		handleHelp(remotesFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli remotes <name> [--json]")
+			os.Exit(1)
+		}
+		if err := remotesFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing remotes flags: %v", err)
+		}
+		if err := client.ListServerRemotes(positional[0], jsonOutput); err != nil {
+			fatal(jsonOutput, "List remotes failed", err)
+		}
+	case "open":
+		var printOnly bool
+		openFlags := flag.NewFlagSet("open", flag.ExitOnError)
+		openFlags.BoolVar(&printOnly, "print", false, "Print the repository URL instead of opening a browser")
+		handleHelp(openFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli open <name> [--print]")
+			os.Exit(1)
+		}
+		if err := openFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing open flags: %v", err)
+		}
+		if err := client.OpenServerRepository(positional[0], printOnly); err != nil {
+			log.Fatalf("Open failed: %v", err)
+		}
+	case "exists":
+		handleHelp(flag.NewFlagSet("exists", flag.ExitOnError), args[1:])
+		if len(args) < 2 {
+			log.Print("Error: server ID is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli exists <id>")
+			os.Exit(1)
+		}
+		exists, err := client.ServerExists(args[1])
+		if err != nil {
+			log.Fatalf("Exists check failed: %v", err)
+		}
+		if !exists {
+			fmt.Printf("Server %s not found\n", args[1])
+			os.Exit(exitCodeNotFound)
+		}
+		fmt.Printf("Server %s exists\n", args[1])
+	case "copy":
+		var newVersion string
+		var outputFile string
+		copyFlags := flag.NewFlagSet("copy", flag.ExitOnError)
+		copyFlags.StringVar(&newVersion, "new-version", "", "Version to set on the copied manifest (required)")
+		copyFlags.StringVar(&outputFile, "output", "", "File to write the manifest to (default: stdout)")
+		var force bool
+		copyFlags.BoolVar(&force, "force", false, "Overwrite the --output file if it exists")
+		handleHelp(copyFlags, args[1:])
+		var serverName string
+		var flagArgs []string
+		for i, arg := range args[1:] {
+			if strings.HasPrefix(arg, "-") {
+				flagArgs = args[i+1:]
+				break
+			} else {
+				serverName = arg
+			}
+		}
+		if serverName == "" {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli copy <name> --new-version <version> [--output <server.json>] [--force]")
+			os.Exit(1)
+		}
+		if err := copyFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing copy flags: %v", err)
+		}
+		if newVersion == "" {
+			log.Print("Error: --new-version is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli copy <name> --new-version <version> [--output <server.json>] [--force]")
+			os.Exit(1)
+		}
+		if err := client.CopyServer(serverName, newVersion, outputFile, force); err != nil {
+			log.Fatalf("Copy server failed: %v", err)
+		}
+	case "rename":
+		var newName string
+		var token string
+		var yes bool
+		var renameOpts PublishOptions
+		renameFlags := flag.NewFlagSet("rename", flag.ExitOnError)
+		renameFlags.StringVar(&newName, "to", "", "New server name, e.g. io.github.owner/name (required)")
+		renameFlags.StringVar(&token, "token", "", "Authentication token (optional)")
+		renameFlags.BoolVar(&yes, "yes", false, "Confirm that a new server entry is published and the old one is kept")
+		renameFlags.BoolVar(&renameOpts.JSON, "json", false, "Output the publish result in JSON format")
+		renameFlags.BoolVar(&renameOpts.AllowNonSemver, "allow-nonsemver", false, "Do not warn when the version is not a semantic version")
+		renameFlags.BoolVar(&renameOpts.NoConsistencyChecks, "no-consistency-checks", false, "Do not warn when an io.github.* name does not match the repository")
+		handleHelp(renameFlags, args[1:])
+		var serverName string
+		var flagArgs []string
+		for i, arg := range args[1:] {
+			if strings.HasPrefix(arg, "-") {
+				flagArgs = args[i+1:]
+				break
+			} else {
+				serverName = arg
+			}
+		}
+		if err := renameFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing rename flags: %v", err)
+		}
+		if serverName == "" || newName == "" {
+			log.Print("Error: server name and --to are required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli rename <name> --to <new-name> --yes [--token <token>] [--json]")
+			os.Exit(1)
+		}
+		if !yes {
+			log.Print("Error: the registry cannot rename servers in place")
+			fmt.Fprintf(os.Stderr, "rename publishes the latest version of %s as a new server %s and leaves %s in the registry.\n", serverName, newName, serverName)
+			fmt.Fprintln(os.Stderr, "Pass --yes to continue.")
+			os.Exit(1)
+		}
+		if err := client.RenameServer(serverName, newName, token, renameOpts); err != nil {
+			fatal(renameOpts.JSON, "Rename server failed", err)
+		}
+	case "export":
+		var outputFile, outputDir string
+		var all, detailed bool
+		exportFlags := flag.NewFlagSet("export", flag.ExitOnError)
+		exportFlags.StringVar(&outputFile, "output", "", "File to write the manifest to (default: stdout)")
+		exportFlags.BoolVar(&all, "all", false, "Export every server in the registry (requires --output-dir)")
+		exportFlags.StringVar(&outputDir, "output-dir", "", "With --all, directory to write one <name>.json manifest per server to")
+		exportFlags.BoolVar(&detailed, "detailed", false, "With --all, fetch the full details of every server")
+		var force bool
+		exportFlags.BoolVar(&force, "force", false, "Overwrite existing manifest files")
+		handleHelp(exportFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if err := exportFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing export flags: %v", err)
+		}
+		if all {
+			if len(positional) > 0 || outputFile != "" {
+				log.Fatalf("Error: --all cannot be combined with a server name or --output")
+			}
+			if outputDir == "" {
+				log.Fatalf("Error: --all requires --output-dir")
+			}
+			if err := client.ExportAllServers(outputDir, detailed, force); err != nil {
+				log.Fatalf("Export failed: %v", err)
+			}
+			break
+		}
+		if outputDir != "" || detailed {
+			log.Fatalf("Error: --output-dir and --detailed require --all")
+		}
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli export <name> [--output <server.json>] [--force]")
+			fmt.Fprintln(os.Stderr, "   or: mcpx-cli export --all --output-dir <dir> [--detailed] [--force]")
+			os.Exit(1)
+		}
+		if err := client.ExportServer(positional[0], outputFile, force); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+	case "validate":
+		var validateOpts ValidateOptions
+		var noConsistencyChecks bool
+		validateFlags := flag.NewFlagSet("validate", flag.ExitOnError)
+		validateFlags.BoolVar(&noConsistencyChecks, "no-consistency-checks", false, "Do not check that an io.github.* name matches the repository")
+		validateFlags.BoolVar(&validateOpts.AllowNonSemver, "allow-nonsemver", false, "Accept versions that are not semantic versions")
+		validateFlags.BoolVar(&validateOpts.CheckURLs, "check-urls", false, "Send HEAD requests to package wheelUrl and binaryUrl to confirm they return 200")
+		validateFlags.BoolVar(&validateOpts.Strict, "strict", false, "Reject unknown keys, e.g. misspelled field names")
+		handleHelp(validateFlags, args[1:])
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			log.Print("Error: server file is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli validate <server.json> [--allow-nonsemver] [--check-urls] [--no-consistency-checks] [--strict]")
+			os.Exit(1)
+		}
+		if err := validateFlags.Parse(args[2:]); err != nil {
+			log.Fatalf("Error parsing validate flags: %v", err)
+		}
+		validateOpts.ConsistencyChecks = !noConsistencyChecks
+		if err := client.ValidateServerFile(args[1], validateOpts); err != nil {
+			log.Fatalf("Validation failed: %v", err)
+		}
+	case "roundtrip":
+		handleHelp(flag.NewFlagSet("roundtrip", flag.ExitOnError), args[1:])
+		if len(args) != 2 || strings.HasPrefix(args[1], "-") {
+			log.Print("Error: server file is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli roundtrip <server.json>")
+			os.Exit(1)
+		}
+		if err := client.RoundTripManifest(args[1]); err != nil {
+			log.Fatalf("Round trip failed: %v", err)
+		}
+	case "lint":
+		var failOn string
+		var checkURLs bool
+		lintFlags := flag.NewFlagSet("lint", flag.ExitOnError)
+		lintFlags.BoolVar(&checkURLs, "check-urls", false, "Report package wheelUrl and binaryUrl that do not return 200 (MCPX007)")
+		lintFlags.StringVar(&failOn, "fail-on", "", "Exit non-zero when a finding has at least this severity (info, warning, error)")
+		handleHelp(lintFlags, args[1:])
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			log.Print("Error: server file is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli lint <server.json> [--fail-on severity] [--check-urls]")
+			os.Exit(1)
+		}
+		if err := lintFlags.Parse(args[2:]); err != nil {
+			log.Fatalf("Error parsing lint flags: %v", err)
+		}
+		if _, ok := lintSeverityRank[failOn]; failOn != "" && !ok {
+			log.Fatalf("Error: --fail-on must be one of info, warning, error")
+		}
+		if err := client.LintServerFile(args[1], failOn, checkURLs); err != nil {
+			log.Fatalf("Lint failed: %v", err)
 		}
 	case "update":
 		var token string
@@ -1591,6 +8279,19 @@ func main() {
 		updateFlags := flag.NewFlagSet("update", flag.ExitOnError)
 		updateFlags.StringVar(&token, "token", "", "Authentication token (required for io.github.* servers)")
 		updateFlags.BoolVar(&jsonOutput, "json", false, "Output result in JSON format")
+		var updateOpts UpdateOptions
+		updateFlags.BoolVar(&updateOpts.Strict, "strict", false, "Reject unknown keys in the manifest, e.g. misspelled field names")
+		updateFlags.Func("set", "Set a server field by dotted path, as path=value, before sending (repeatable)", func(arg string) error {
+			field, value, err := parseKeyValue(arg)
+			if err != nil {
+				return err
+			}
+			updateOpts.Overrides = append(updateOpts.Overrides, fieldOverride{Field: field, Value: value})
+			return nil
+		})
+		updateFlags.BoolVar(&updateOpts.Patch, "patch", false, "Send only the --set fields as a JSON merge patch instead of a manifest")
+		updateFlags.StringVar(&updateOpts.Version, "version", "", "With --patch, the version to change (default: latest)")
+		handleHelp(updateFlags, args[1:])
 		var serverName string
 		var serverFile string
 		var flagArgs []string
@@ -1613,27 +8314,90 @@ func main() {
 		}
 
 		if serverName == "" {
-			fmt.Println("Error: server ID is required")
-			fmt.Println("Usage: mcpx-cli update <name> <server.json> [--token <token>] [--json]")
-			os.Exit(1)
-		}
-		if serverFile == "" {
-			fmt.Println("Error: server file is required")
-			fmt.Println("Usage: mcpx-cli update <name> <server.json> [--token <token>] [--json]")
+			log.Print("Error: server ID is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli update <name> <server.json> [--token <token>] [--json]")
 			os.Exit(1)
 		}
 		if err := updateFlags.Parse(flagArgs); err != nil {
 			log.Fatalf("Error parsing update flags: %v", err)
 		}
-		if err := client.UpdateServer(serverName, serverFile, token, jsonOutput); err != nil {
-			log.Fatalf("Update server failed: %v", err)
+		if updateOpts.Patch {
+			if serverFile != "" {
+				log.Fatalf("Error: --patch sends only the --set fields and cannot be combined with a server file")
+			}
+			if updateOpts.Strict {
+				log.Fatalf("Error: --strict checks a manifest and cannot be combined with --patch")
+			}
+		} else {
+			if updateOpts.Version != "" {
+				log.Fatalf("Error: --version requires --patch; the manifest names the version")
+			}
+			if serverFile == "" {
+				log.Print("Error: server file is required")
+				fmt.Fprintln(os.Stderr, "Usage: mcpx-cli update <name> <server.json> [--token <token>] [--json]")
+				fmt.Fprintln(os.Stderr, "   or: mcpx-cli update <name> --patch --set <path=value> [--version <version>]")
+				os.Exit(1)
+			}
+		}
+		updateOpts.JSON = jsonOutput
+		if err := client.UpdateServer(serverName, serverFile, token, updateOpts); err != nil {
+			fatal(jsonOutput, "Update server failed", err)
 		}
 	case "publish":
 		var token string
 		var interactive bool
+		var publishOpts PublishOptions
 		publishFlags := flag.NewFlagSet("publish", flag.ExitOnError)
 		publishFlags.StringVar(&token, "token", "", "Authentication token (optional)")
 		publishFlags.BoolVar(&interactive, "interactive", false, "Interactive mode to create server configuration")
+		publishFlags.BoolVar(&publishOpts.PreviewFull, "preview-full", false, "With --interactive, print the complete manifest before asking to publish")
+		publishFlags.BoolVar(&publishOpts.AcceptDefaults, "accept-defaults", false, "With --interactive, answer every prompt with its default and publish without reading stdin")
+		publishFlags.BoolVar(&publishOpts.AllowNonSemver, "allow-nonsemver", false, "Do not warn when the version is not a semantic version")
+		publishFlags.StringVar(&publishOpts.IdempotencyKey, "idempotency-key", "", "Idempotency-Key header value (default: a new UUID per publish)")
+		var dir string
+		var recursive bool
+		publishFlags.StringVar(&dir, "dir", "", "Publish every *.json manifest in this directory")
+		publishFlags.BoolVar(&recursive, "recursive", false, "With --dir, also publish manifests in subdirectories")
+		var summaryFormat string
+		publishFlags.StringVar(&summaryFormat, "summary", "", "With --dir, print the final summary as text (default) or json")
+		var bodyFile string
+		publishFlags.StringVar(&bodyFile, "body-file", "", "Pre-built request body to publish (requires --raw)")
+		publishFlags.BoolVar(&publishOpts.JSON, "json", false, "Output the publish result in JSON format")
+		publishFlags.BoolVar(&publishOpts.Raw, "raw", false, "Send the file verbatim, without parsing or re-encoding it")
+		publishFlags.BoolVar(&publishOpts.Strict, "strict", false, "Reject unknown keys in the manifest, e.g. misspelled field names")
+		publishFlags.BoolVar(&publishOpts.NoNormalize, "no-normalize", false, "Send the server object as written, without trimming strings, dropping empty lists or lower-casing types")
+		publishFlags.BoolVar(&publishOpts.NoConsistencyChecks, "no-consistency-checks", false, "Do not warn when an io.github.* name does not match the repository")
+		publishFlags.StringVar(&publishOpts.SchemaVersion, "schema-version", "", "Read the manifest as v1 (bare server manifest) or v2 (PublishRequest wrapper) instead of detecting it")
+		publishFlags.Func("set-version", "Publish with this version instead of the one in the file", func(value string) error {
+			publishOpts.Overrides = append(publishOpts.Overrides, fieldOverride{Field: "version", Value: value})
+			return nil
+		})
+		var setName bool
+		publishFlags.Func("set-name", "Publish under this server name instead of the one in the file", func(value string) error {
+			setName = true
+			publishOpts.Overrides = append(publishOpts.Overrides, fieldOverride{Field: "name", Value: value})
+			return nil
+		})
+		publishFlags.Func("set", "Set a server field by dotted path, as path=value, before publishing (repeatable)", func(arg string) error {
+			field, value, err := parseKeyValue(arg)
+			if err != nil {
+				return err
+			}
+			publishOpts.Overrides = append(publishOpts.Overrides, fieldOverride{Field: field, Value: value})
+			return nil
+		})
+		publishFlags.Func("publisher-meta", "Add a key=value entry to the x-publisher metadata (repeatable)", func(arg string) error {
+			key, value, err := parseKeyValue(arg)
+			if err != nil {
+				return err
+			}
+			if publishOpts.PublisherMeta == nil {
+				publishOpts.PublisherMeta = map[string]string{}
+			}
+			publishOpts.PublisherMeta[key] = value
+			return nil
+		})
+		handleHelp(publishFlags, args[1:])
 		flagArgs := args[1:]
 		var serverFile string
 		// If interactive flag is provided or no server file is given, use interactive mode
@@ -1641,35 +8405,156 @@ func main() {
 			if err := publishFlags.Parse(flagArgs); err != nil {
 				log.Fatalf("Error parsing publish flags: %v", err)
 			}
-			interactive = true
+			interactive = bodyFile == "" && dir == ""
 		} else {
 			serverFile = args[1]
 			if err := publishFlags.Parse(args[2:]); err != nil {
 				log.Fatalf("Error parsing publish flags: %v", err)
 			}
 		}
+		if publishOpts.Raw && len(publishOpts.PublisherMeta) > 0 {
+			log.Fatalf("Error: --publisher-meta cannot be combined with --raw")
+		}
+		if v := publishOpts.SchemaVersion; v != "" && v != SchemaVersionV1 && v != SchemaVersionV2 {
+			log.Fatalf("Error: --schema-version must be v1 or v2")
+		}
+		if publishOpts.Raw && publishOpts.SchemaVersion != "" {
+			log.Fatalf("Error: --schema-version cannot be combined with --raw")
+		}
+		if len(publishOpts.Overrides) > 0 && (publishOpts.Raw || (interactive && !publishOpts.AcceptDefaults)) {
+			log.Fatalf("Error: --set, --set-version and --set-name cannot be combined with --raw or --interactive (without --accept-defaults)")
+		}
+		if setName && dir != "" {
+			log.Fatalf("Error: --set-name cannot be combined with --dir")
+		}
+		if publishOpts.PreviewFull && !interactive {
+			log.Fatalf("Error: --preview-full requires --interactive")
+		}
+		if publishOpts.AcceptDefaults && !interactive {
+			log.Fatalf("Error: --accept-defaults requires --interactive")
+		}
+		if err := validateSummaryFormat(summaryFormat); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if summaryFormat != "" && dir == "" {
+			log.Fatalf("Error: --summary requires --dir")
+		}
+		if bodyFile != "" {
+			if !publishOpts.Raw {
+				log.Fatalf("Error: --body-file requires --raw")
+			}
+			if serverFile != "" || interactive {
+				log.Fatalf("Error: --body-file cannot be combined with a server file or --interactive")
+			}
+			serverFile = bodyFile
+		}
+		if dir != "" {
+			if serverFile != "" || interactive || bodyFile != "" {
+				log.Fatalf("Error: --dir cannot be combined with a server file, --body-file or --interactive")
+			}
+			if publishOpts.JSON {
+				log.Fatalf("Error: --json is not supported with --dir")
+			}
+			if err := client.PublishDirectory(dir, recursive, token, publishOpts, summaryFormat); err != nil {
+				log.Fatalf("Publish failed: %v", err)
+			}
+			break
+		}
 		if interactive {
-			if err := client.PublishServerInteractive(token); err != nil {
+			if err := client.PublishServerInteractive(token, publishOpts); err != nil {
 				log.Fatalf("Interactive publish failed: %v", err)
 			}
 		} else {
 			if serverFile == "" {
-				fmt.Println("Error: server file is required in non-interactive mode")
-				fmt.Println("Usage: mcpx-cli publish <server.json> [--token <token>]")
-				fmt.Println("   or: mcpx-cli publish --interactive [--token <token>]")
-				fmt.Println("Note: --token is required only for GitHub namespaced servers (io.github.*)")
+				log.Print("Error: server file is required in non-interactive mode")
+				fmt.Fprintln(os.Stderr, "Usage: mcpx-cli publish <server.json> [--token <token>]")
+				fmt.Fprintln(os.Stderr, "   or: mcpx-cli publish --interactive [--token <token>]")
+				fmt.Fprintln(os.Stderr, "Note: --token is required only for GitHub namespaced servers (io.github.*)")
 				os.Exit(1)
 			}
-			if err := client.PublishServer(serverFile, token); err != nil {
-				log.Fatalf("Publish server failed: %v", err)
+			if err := client.PublishServer(serverFile, token, publishOpts); err != nil {
+				fatal(publishOpts.JSON, "Publish server failed", err)
 			}
 		}
+	case "import":
+		var dir, token string
+		var bulk bulkPublishOptions
+		var publishOpts PublishOptions
+		importFlags := flag.NewFlagSet("import", flag.ExitOnError)
+		importFlags.StringVar(&dir, "dir", "", "Directory of manifests to publish, e.g. written by export --all (required)")
+		importFlags.StringVar(&token, "token", "", "Authentication token (required for io.github.* servers)")
+		importFlags.BoolVar(&bulk.ContinueOnError, "continue-on-error", false, "Keep publishing the remaining manifests after a failure")
+		importFlags.BoolVar(&bulk.IfNotExists, "if-not-exists", false, "Skip manifests whose name and version are already in the registry")
+		importFlags.BoolVar(&publishOpts.AllowNonSemver, "allow-nonsemver", false, "Do not warn when a version is not a semantic version")
+		importFlags.StringVar(&bulk.Summary, "summary", "", "Print the final summary as text (default) or json")
+		handleHelp(importFlags, args[1:])
+		if err := importFlags.Parse(args[1:]); err != nil {
+			log.Fatalf("Error parsing import flags: %v", err)
+		}
+		if err := validateSummaryFormat(bulk.Summary); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if dir == "" {
+			log.Print("Error: --dir is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli import --dir <dir> [--token <token>] [--continue-on-error] [--if-not-exists] [--summary text|json]")
+			os.Exit(1)
+		}
+		if err := client.ImportDirectory(dir, token, publishOpts, bulk); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+	case "deprecate":
+		var token, version, reason string
+		var jsonOutput bool
+		deprecateFlags := flag.NewFlagSet("deprecate", flag.ExitOnError)
+		deprecateFlags.StringVar(&version, "version", "", "Version to deprecate (default: latest)")
+		deprecateFlags.StringVar(&reason, "reason", "", "Why the server is deprecated (required)")
+		deprecateFlags.StringVar(&token, "token", "", "Authentication token (optional, will use stored token if not provided)")
+		deprecateFlags.BoolVar(&jsonOutput, "json", false, "Output result in JSON format")
+		handleHelp(deprecateFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli deprecate <name> [--version <version>] --reason <text> [--token <token>] [--json]")
+			os.Exit(1)
+		}
+		if err := deprecateFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing deprecate flags: %v", err)
+		}
+		if reason == "" {
+			log.Print("Error: --reason is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli deprecate <name> [--version <version>] --reason <text> [--token <token>] [--json]")
+			os.Exit(1)
+		}
+		if err := client.SetServerStatus(positional[0], version, ServerStatusDeprecated, reason, token, jsonOutput); err != nil {
+			fatal(jsonOutput, "Deprecate server failed", err)
+		}
+	case "restore":
+		var token, version string
+		var jsonOutput bool
+		restoreFlags := flag.NewFlagSet("restore", flag.ExitOnError)
+		restoreFlags.StringVar(&version, "version", "", "Version to restore (default: latest)")
+		restoreFlags.StringVar(&token, "token", "", "Authentication token (optional, will use stored token if not provided)")
+		restoreFlags.BoolVar(&jsonOutput, "json", false, "Output result in JSON format")
+		handleHelp(restoreFlags, args[1:])
+		positional, flagArgs := splitArgs(args[1:])
+		if len(positional) == 0 {
+			log.Print("Error: server name is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli restore <name> [--version <version>] [--token <token>] [--json]")
+			os.Exit(1)
+		}
+		if err := restoreFlags.Parse(flagArgs); err != nil {
+			log.Fatalf("Error parsing restore flags: %v", err)
+		}
+		if err := client.RestoreServer(positional[0], version, token, jsonOutput); err != nil {
+			fatal(jsonOutput, "Restore server failed", err)
+		}
 	case "delete":
 		var token string
 		var jsonOutput bool
 		deleteFlags := flag.NewFlagSet("delete", flag.ExitOnError)
 		deleteFlags.StringVar(&token, "token", "", "Authentication token (optional, will use stored token if not provided)")
 		deleteFlags.BoolVar(&jsonOutput, "json", false, "Output result in JSON format")
+		handleHelp(deleteFlags, args[1:])
 		var serverName string
 		var version string
 		var flagArgs []string
@@ -1689,37 +8574,43 @@ func main() {
 			}
 		}
 		if serverName == "" {
-			fmt.Println("Error: server ID is required")
-			fmt.Println("Usage: mcpx-cli delete <server-name> <version> [--token <token>] [--json]")
-			fmt.Println("Get server names and versions with: mcpx-cli servers")
+			log.Print("Error: server ID is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli delete <server-name> <version> [--token <token>] [--json]")
+			fmt.Fprintln(os.Stderr, "Get server names and versions with: mcpx-cli servers")
 			os.Exit(1)
 		}
 		if version == "" {
-			fmt.Println("Error: version is required")
-			fmt.Println("Usage: mcpx-cli delete <server-name> <version> [--token <token>] [--json]")
-			fmt.Println("Get server names and versions with: mcpx-cli servers")
+			log.Print("Error: version is required")
+			fmt.Fprintln(os.Stderr, "Usage: mcpx-cli delete <server-name> <version> [--token <token>] [--json]")
+			fmt.Fprintln(os.Stderr, "Get server names and versions with: mcpx-cli servers")
 			os.Exit(1)
 		}
 		if err := deleteFlags.Parse(flagArgs); err != nil {
 			log.Fatalf("Error parsing delete flags: %v", err)
 		}
-		if token == "" {
+		if token == "" && !client.noAuth {
 			// Try to load stored token
 			authConfig, err := client.loadAuthConfig()
 			if err != nil || authConfig.Token == "" {
-				fmt.Println("Error: authentication token is required for delete operations")
-				fmt.Println("Usage: mcpx-cli delete <server-name> <version> [--token <token>] [--json]")
-				fmt.Println("Get a token with: mcpx-cli login --method anonymous")
+				log.Print("Error: authentication token is required for delete operations")
+				fmt.Fprintln(os.Stderr, "Usage: mcpx-cli delete <server-name> <version> [--token <token>] [--json]")
+				fmt.Fprintln(os.Stderr, "Get a token with: mcpx-cli login --method anonymous")
 				os.Exit(1)
 			}
 			token = authConfig.Token
 		}
 		if err := client.DeleteServer(serverName, version, token, jsonOutput); err != nil {
-			log.Fatalf("Delete server failed: %v", err)
+			fatal(jsonOutput, "Delete server failed", err)
 		}
 	default:
-		fmt.Printf("Unknown command: %s\n\n", command)
+		log.Printf("Error: Unknown command: %s", command)
 		printUsage()
 		os.Exit(1)
 	}
+
+	if len(client.sinks) > 0 && !client.sinksWritten {
+		client.logger.Warn("nothing was written to --tee; this mode of the command has no JSON document")
+	}
+	// Failed commands exit before this point, so the notice only follows successful ones
+	notifier.finish(client.logger)
 }
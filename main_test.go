@@ -1,14 +1,37 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"log"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -639,7 +662,7 @@ func TestListServers(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
-			err := client.ListServers(tt.cursor, tt.limit, tt.json, tt.detailed)
+			err := client.ListServers(ListServersOptions{Cursor: tt.cursor, Limit: tt.limit, JSON: tt.json, Detailed: tt.detailed})
 
 			_ = w.Close()
 			os.Stdout = oldStdout
@@ -700,7 +723,7 @@ func TestGetServer(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
-			err := client.GetServer(tt.serverName, tt.json)
+			err := client.GetServer(tt.serverName, GetServerOptions{JSON: tt.json})
 
 			_ = w.Close()
 			os.Stdout = oldStdout
@@ -783,7 +806,7 @@ func TestPublishServer(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
-			err := client.PublishServer(tt.serverFile, tt.token)
+			err := client.PublishServer(tt.serverFile, tt.token, PublishOptions{})
 
 			_ = w.Close()
 			os.Stdout = oldStdout
@@ -856,7 +879,7 @@ func TestUpdateServer(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
-			err := client.UpdateServer(tt.serverName, tt.serverFile, tt.token, tt.json)
+			err := client.UpdateServer(tt.serverName, tt.serverFile, tt.token, UpdateOptions{JSON: tt.json})
 
 			_ = w.Close()
 			os.Stdout = oldStdout
@@ -1230,21 +1253,22 @@ func TestMetaIDExtraction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Create a server wrapper with the test registry meta
-			wrapper := ServerWrapper{
-				Server: Server{
-					ID:   "original-id",
-					Name: "test-server",
-				},
-				RegistryMeta: tt.registryMeta,
+			// Decode a server wrapper with the test registry meta
+			body, err := json.Marshal(map[string]interface{}{
+				"server": map[string]interface{}{"id": "original-id", "name": "test-server"},
+				"_meta":  tt.registryMeta,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wrapper ServerWrapper
+			if err := json.Unmarshal(body, &wrapper); err != nil {
+				t.Fatalf("Failed to decode wrapper: %v", err)
 			}
 
-			// Extract ID from RegistryMeta structure (this simulates the logic in main.go)
 			extractedID := ""
-			if wrapper.RegistryMeta != nil {
-				if id, ok := wrapper.RegistryMeta["id"].(string); ok {
-					extractedID = id
-				}
+			if official := wrapper.RegistryMeta.official(); official != nil {
+				extractedID = official.ServerID
 			}
 
 			if tt.shouldExtract {
@@ -1260,6 +1284,45 @@ func TestMetaIDExtraction(t *testing.T) {
 	}
 }
 
+func TestRegistryMeta(t *testing.T) {
+	body := `{"server":{"name":"io.test/server","version":"1.0.0"},"_meta":{` +
+		`"io.modelcontextprotocol.registry/official":{"serverId":"sid","versionId":"vid","publishedAt":"2025-01-02T03:04:05Z","updatedAt":"2025-02-03T04:05:06Z","isLatest":true},` +
+		`"com.example/custom":{"tier":"gold"}}}`
+	detail, err := parseServerDetail([]byte(body))
+	if err != nil {
+		t.Fatalf("parseServerDetail failed: %v", err)
+	}
+	output := captureStdout(t, func() { printServerDetail(detail) })
+	for _, want := range []string{"Server ID: sid\n", "Published: 2025-01-02T03:04:05Z\n", "Last Updated: 2025-02-03T04:05:06Z\n", "Latest: yes\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the detail output, got:\n%s", want, output)
+		}
+	}
+
+	var wrapper ServerDetailWrapper
+	if err := json.Unmarshal([]byte(body), &wrapper); err != nil {
+		t.Fatalf("Failed to decode wrapper: %v", err)
+	}
+	if wrapper.GetVersionID() != "vid" || string(wrapper.RegistryMeta.Extra["com.example/custom"]) != `{"tier":"gold"}` {
+		t.Errorf("Expected typed fields and unknown keys to be kept, got %+v", wrapper.RegistryMeta)
+	}
+	encoded, err := json.Marshal(wrapper.RegistryMeta)
+	if err != nil {
+		t.Fatalf("Failed to encode metadata: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"com.example/custom":{"tier":"gold"}`) || !strings.Contains(string(encoded), `"serverId":"sid"`) {
+		t.Errorf("Expected the metadata to round-trip, got %s", encoded)
+	}
+
+	var legacy ServerWrapper
+	if err := json.Unmarshal([]byte(`{"server":{"name":"io.test/old"},"_meta":{"id":"old-id","published_at":"2023-01-01T00:00:00Z","is_latest":true}}`), &legacy); err != nil {
+		t.Fatalf("Failed to decode legacy wrapper: %v", err)
+	}
+	if official := legacy.RegistryMeta.official(); official == nil || official.ServerID != "old-id" || official.PublishedAt != "2023-01-01T00:00:00Z" || !official.IsLatest {
+		t.Errorf("Expected the flat legacy keys to be typed, got %+v", official)
+	}
+}
+
 func TestListServersWithMetaIDs(t *testing.T) {
 	mockServer := createMockServer()
 	defer mockServer.Close()
@@ -1271,7 +1334,7 @@ func TestListServersWithMetaIDs(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := client.ListServers("", 10, false, false)
+	err := client.ListServers(ListServersOptions{Limit: 10})
 	if err != nil {
 		t.Fatalf("ListServers() error = %v", err)
 	}
@@ -1501,7 +1564,7 @@ func TestWindowsPathHandling(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		err = client.PublishServer(serverFile, "test-token")
+		err = client.PublishServer(serverFile, "test-token", PublishOptions{})
 
 		_ = w.Close()
 		os.Stdout = oldStdout
@@ -1581,7 +1644,7 @@ func TestPublishServerWithAutoRetry(t *testing.T) {
 
 	// Test publish without token - should trigger auto-auth initially,
 	// fail on first publish, then retry successfully
-	err := client.PublishServer(serverFile, "")
+	err := client.PublishServer(serverFile, "", PublishOptions{})
 
 	_ = w.Close()
 	os.Stdout = oldStdout
@@ -1607,6 +1670,27 @@ func TestPublishServerWithAutoRetry(t *testing.T) {
 	t.Logf("✓ Auto-authentication and retry logic worked correctly with %d attempts", retryCount)
 }
 
+func TestPublishServerRejected(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = fmt.Fprint(w, `{"title":"Unprocessable Entity","status":422,"detail":"validation failed"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	serverFile := createTempServerFile(t, exampleServerNPMJSON)
+	defer func() { _ = os.Remove(serverFile) }()
+
+	for name, opts := range map[string]PublishOptions{"text": {}, "quiet": {Quiet: true}} {
+		t.Run(name, func(t *testing.T) {
+			_, err := captureStdoutErr(t, func() error { return client.PublishServer(serverFile, "test-token", opts) })
+			if err == nil || !strings.Contains(err.Error(), "422") {
+				t.Errorf("Expected a rejected publish to fail with status 422, got %v", err)
+			}
+		})
+	}
+}
+
 func TestPublishServerPackageTypes(t *testing.T) {
 	mockServer := createMockServer()
 	defer mockServer.Close()
@@ -1653,7 +1737,7 @@ func TestPublishServerPackageTypes(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
-			err := client.PublishServer(serverFile, "")
+			err := client.PublishServer(serverFile, "", PublishOptions{})
 
 			_ = w.Close()
 			os.Stdout = oldStdout
@@ -1673,3 +1757,4936 @@ func TestPublishServerPackageTypes(t *testing.T) {
 		})
 	}
 }
+
+// Test helper to capture everything written to stdout while fn runs
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+
+	fn()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	return string(<-done)
+}
+
+// captureStdoutErr is captureStdout for a function that can fail. The error is returned rather than reported
+// inside fn, so a t.Fatalf never runs while os.Stdout is still redirected.
+func captureStdoutErr(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	var err error
+	output := captureStdout(t, func() { err = fn() })
+	return output, err
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "1048576", want: 1048576},
+		{input: "512KB", want: 512 * 1000},
+		{input: "64MiB", want: 64 << 20},
+		{input: "1gib", want: 1 << 30},
+		{input: "10 B", want: 10},
+		{input: "", wantErr: true},
+		{input: "-5", wantErr: true},
+		{input: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"status":"ok","github_client_id":"%s"}`, strings.Repeat("x", 2048))
+	}))
+	defer mockServer.Close()
+
+	t.Run("response within limit", func(t *testing.T) {
+		client := NewMCPXClient(mockServer.URL)
+		var err error
+		output := captureStdout(t, func() {
+			err = client.Health()
+		})
+		if err != nil {
+			t.Fatalf("Health() error = %v", err)
+		}
+		if !strings.Contains(output, "Status: ok") {
+			t.Errorf("Expected health status in output, got %v", output)
+		}
+	})
+
+	t.Run("response exceeds limit", func(t *testing.T) {
+		client := NewMCPXClient(mockServer.URL)
+		client.maxResponseSize = 1024
+		var err error
+		captureStdout(t, func() {
+			err = client.Health()
+		})
+		if err == nil || !strings.Contains(err.Error(), "response too large") {
+			t.Errorf("Expected 'response too large' error, got %v", err)
+		}
+	})
+}
+
+func TestInstallCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  Package
+		want string
+	}{
+		{
+			name: "npm package",
+			pkg:  Package{RegistryType: RegistryTypeNPM, Identifier: "@example/server", Version: "1.0.0", RuntimeHint: RuntimeHintNPX},
+			want: "npx @example/server@1.0.0",
+		},
+		{
+			name: "pypi package defaults to uvx",
+			pkg:  Package{RegistryType: RegistryTypePyPI, Identifier: "example-server", Version: "1.0.0"},
+			want: "uvx example-server@1.0.0",
+		},
+		{
+			name: "pypi package with python runtime hint",
+			pkg:  Package{RegistryType: RegistryTypePyPI, Identifier: "example-server", Version: "1.0.0", RuntimeHint: "python"},
+			want: "pip install example-server==1.0.0",
+		},
+		{
+			name: "wheel package",
+			pkg:  Package{RegistryType: RegistryTypeWheel, Identifier: "example", WheelURL: "https://example.com/example-1.0.0-py3-none-any.whl"},
+			want: "pip install https://example.com/example-1.0.0-py3-none-any.whl",
+		},
+		{
+			name: "docker package",
+			pkg:  Package{RegistryType: RegistryTypeDocker, Identifier: "example/server", Version: "1.0.0"},
+			want: "docker run -i --rm example/server:1.0.0",
+		},
+		{
+			name: "binary package",
+			pkg:  Package{RegistryType: RegistryTypeBinary, Identifier: "example/server", BinaryURL: "https://example.com/server"},
+			want: "curl -fLO https://example.com/server",
+		},
+		{
+			name: "unknown registry with runtime hint",
+			pkg:  Package{RegistryType: "custom", Identifier: "example", RuntimeHint: "run"},
+			want: "run example",
+		},
+		{
+			name: "unknown registry without runtime hint",
+			pkg:  Package{RegistryType: RegistryTypeMCPB, Identifier: "example"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := installCommand(tt.pkg); got != tt.want {
+				t.Errorf("installCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetServerInstallCommand(t *testing.T) {
+	mockServer := createMockServer()
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = client.GetServerInstallCommand("io.test/server1", "")
+	})
+	if err != nil {
+		t.Fatalf("GetServerInstallCommand() error = %v", err)
+	}
+
+	if strings.TrimSpace(output) != "npx @test/server@latest" {
+		t.Errorf("Expected only the install command, got %q", output)
+	}
+}
+
+func TestFormatServerShort(t *testing.T) {
+	full := ServerDetail{Server: Server{
+		Name:        "io.test/server",
+		Version:     "1.2.0",
+		Description: "Test server",
+		Status:      "deprecated",
+		Repository:  Repository{URL: "https://github.com/test/server"},
+	}}
+	want := "io.test/server 1.2.0 [deprecated]\nTest server\nhttps://github.com/test/server"
+	if got := formatServerShort(full); got != want {
+		t.Errorf("formatServerShort() = %q, want %q", got, want)
+	}
+
+	minimal := ServerDetail{Server: Server{Name: "io.test/server", Version: "1.0.0", Status: "active"}}
+	if got := formatServerShort(minimal); got != "io.test/server 1.0.0" {
+		t.Errorf("formatServerShort() = %q, want a single line", got)
+	}
+}
+
+func TestParseServerDetail(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantName     string
+		wantID       string
+		wantPackages int
+	}{
+		{
+			name:         "wrapper format",
+			body:         `{"server":{"name":"io.test/wrapped","version":"1.0.0","packages":[{"registryType":"npm","identifier":"x","version":"1.0.0"}]},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"wrapped-id"}}}`,
+			wantName:     "io.test/wrapped",
+			wantID:       "wrapped-id",
+			wantPackages: 1,
+		},
+		{
+			name:         "legacy format with _meta",
+			body:         `{"name":"io.test/legacy","version":"1.0.0","packages":[{"registryType":"npm","identifier":"x","version":"1.0.0"}],"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"legacy-id"}}}`,
+			wantName:     "io.test/legacy",
+			wantID:       "legacy-id",
+			wantPackages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, err := parseServerDetail([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("parseServerDetail() error = %v", err)
+			}
+			if detail.Name != tt.wantName {
+				t.Errorf("Expected name %s, got %s", tt.wantName, detail.Name)
+			}
+			if detail.GetServerID() != tt.wantID {
+				t.Errorf("Expected server ID %s, got %s", tt.wantID, detail.GetServerID())
+			}
+			if len(detail.Packages) != tt.wantPackages {
+				t.Errorf("Expected %d packages, got %d", tt.wantPackages, len(detail.Packages))
+			}
+		})
+	}
+}
+
+func TestCopyServer(t *testing.T) {
+	mockServer := createMockServer()
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	outputFile := filepath.Join(t.TempDir(), "server.json")
+
+	var err error
+	output := captureStdout(t, func() {
+		err = client.CopyServer("io.test/server1", "2.0.0", outputFile, false)
+	})
+	if err != nil {
+		t.Fatalf("CopyServer() error = %v", err)
+	}
+	if !strings.Contains(output, "2.0.0") {
+		t.Errorf("Expected output to mention the new version, got %v", output)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read copied manifest: %v", err)
+	}
+
+	var copied ServerDetail
+	if err := json.Unmarshal(data, &copied); err != nil {
+		t.Fatalf("Copied manifest is not valid JSON: %v", err)
+	}
+	if copied.Version != "2.0.0" {
+		t.Errorf("Expected version 2.0.0, got %s", copied.Version)
+	}
+	if copied.ID != "" || copied.Meta != nil || copied.Status != "" {
+		t.Errorf("Expected registry-managed fields to be cleared, got id=%q status=%q meta=%v", copied.ID, copied.Status, copied.Meta)
+	}
+	if len(copied.Packages) == 0 {
+		t.Errorf("Expected packages to be preserved in the copy")
+	}
+}
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: "1.0.0"},
+		{version: "0.10.2"},
+		{version: "2.0.0-beta.1"},
+		{version: "1.0.0-rc.1+build.5"},
+		{version: "1.0.0+20251016"},
+		{version: "1.0", wantErr: true},
+		{version: "v1.0.0", wantErr: true},
+		{version: "01.0.0", wantErr: true},
+		{version: "1.0.0-", wantErr: true},
+		{version: "1.0.0-alpha..1", wantErr: true},
+		{version: "latest", wantErr: true},
+		{version: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			_, err := parseSemver(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSemver(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServerDetail(t *testing.T) {
+	valid := ServerDetail{
+		Server: Server{Name: "io.test/server", Description: "Test server", Version: "1.0.0"},
+		Packages: []Package{
+			{RegistryType: RegistryTypeNPM, Identifier: "@test/server", Version: "1.0.0"},
+		},
+	}
+	if problems := validateServerDetail(valid, false); len(problems) != 0 {
+		t.Errorf("Expected no problems, got %v", problems)
+	}
+
+	nonSemver := valid
+	nonSemver.Version = "2024.1"
+	if problems := validateServerDetail(nonSemver, false); len(problems) != 1 {
+		t.Errorf("Expected one semver problem, got %v", problems)
+	}
+	if problems := validateServerDetail(nonSemver, true); len(problems) != 0 {
+		t.Errorf("Expected --allow-nonsemver to accept the version, got %v", problems)
+	}
+
+	var empty ServerDetail
+	empty.Packages = []Package{{}}
+	if problems := validateServerDetail(empty, false); len(problems) != 5 {
+		t.Errorf("Expected 5 problems for an empty manifest, got %v", problems)
+	}
+}
+
+func TestPublishServerNonSemverWarning(t *testing.T) {
+	mockServer := createMockServer()
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	serverFile := createTempServerFile(t, []byte(`{"name":"io.test/server","description":"Test","version":"latest"}`))
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(serverFile)
+
+	captureStdout(t, func() {
+		_ = client.PublishServer(serverFile, "test-token", PublishOptions{})
+	})
+	if !strings.Contains(logs.String(), "Warning: invalid semantic version") {
+		t.Errorf("Expected a semver warning, got %v", logs.String())
+	}
+
+	logs.Reset()
+	captureStdout(t, func() {
+		_ = client.PublishServer(serverFile, "test-token", PublishOptions{AllowNonSemver: true})
+	})
+	if strings.Contains(logs.String(), "invalid semantic version") {
+		t.Errorf("Expected no semver warning with AllowNonSemver, got %v", logs.String())
+	}
+}
+
+func TestWatchLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	runs := 0
+	output := captureStdout(t, func() {
+		watchLoop(ctx, 10*time.Millisecond, watchTimestamp, func() error {
+			runs++
+			if runs == 2 {
+				return fmt.Errorf("registry unavailable")
+			}
+			if runs == 3 {
+				cancel()
+			}
+			return nil
+		})
+	})
+
+	if runs != 3 {
+		t.Errorf("Expected 3 runs before cancellation, got %d", runs)
+	}
+	if strings.Count(output, "###") != 6 {
+		t.Errorf("Expected a timestamp header per run, got %v", output)
+	}
+	if !strings.Contains(logs.String(), "Error: registry unavailable") || strings.Contains(output, "registry unavailable") {
+		t.Errorf("Expected errors to be reported on stderr without stopping the loop, got logs %q and output %q", logs.String(), output)
+	}
+
+	// --json: nothing but the documents fn prints, so the output stays a JSON stream
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	runs = 0
+	output = captureStdout(t, func() {
+		watchLoop(ctx, 10*time.Millisecond, watchPlain, func() error {
+			runs++
+			if runs == 2 {
+				cancel()
+			}
+			fmt.Println(`{"servers":[]}`)
+			return nil
+		})
+	})
+	if output != "{\"servers\":[]}\n{\"servers\":[]}\n" {
+		t.Errorf("Expected only the JSON documents in plain mode, got %q", output)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("text format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewLogger(&buf, LogFormatText, false)
+		logger.Info("publishing", "file", "server.json")
+		logger.Warn("version is not semver")
+		logger.Debug("hidden unless verbose")
+
+		want := "publishing file=server.json\nWarning: version is not semver\n"
+		if buf.String() != want {
+			t.Errorf("Expected %q, got %q", want, buf.String())
+		}
+	})
+
+	t.Run("verbose text format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewLogger(&buf, LogFormatText, true)
+		logger.Debug("sending request", "method", "GET")
+
+		if buf.String() != "[verbose] sending request method=GET\n" {
+			t.Errorf("Unexpected verbose output %q", buf.String())
+		}
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewLogger(&buf, LogFormatJSON, true)
+		logger.Error("request failed", "status", 500, "err", fmt.Errorf("boom"))
+		logger.Debug("sending request")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected one JSON record per message, got %q", buf.String())
+		}
+
+		var record logRecord
+		if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+			t.Fatalf("Log line is not valid JSON: %v", err)
+		}
+		if record.Level != "error" || record.Msg != "request failed" || record.Timestamp == "" {
+			t.Errorf("Unexpected record %+v", record)
+		}
+		if record.Fields["status"] != float64(500) || record.Fields["err"] != "boom" {
+			t.Errorf("Unexpected fields %+v", record.Fields)
+		}
+	})
+
+	t.Run("standard logger adapter", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := logWriter{logger: NewLogger(&buf, LogFormatJSON, false)}
+		_, _ = writer.Write([]byte("Login failed: boom\n"))
+
+		var record logRecord
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("Log line is not valid JSON: %v", err)
+		}
+		if record.Level != "error" || record.Msg != "Login failed: boom" {
+			t.Errorf("Unexpected record %+v", record)
+		}
+	})
+}
+
+// Test helper to create a mock registry that serves the given pages of servers, chained by cursor.
+// Page i is served for cursor "page-i" (the first page for no cursor) and links to page i+1.
+func createPaginatedMockServer(t *testing.T, pages [][]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 0
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			if _, err := fmt.Sscanf(cursor, "page-%d", &page); err != nil || page >= len(pages) {
+				http.Error(w, `{"detail":"invalid cursor"}`, http.StatusBadRequest)
+				return
+			}
+		}
+
+		var wrappers []map[string]interface{}
+		for _, entry := range pages[page] {
+			// An entry is a server name, or name@version for another version of the same server
+			name, version, found := strings.Cut(entry, "@")
+			if !found {
+				version = "1.0.0"
+			}
+			wrappers = append(wrappers, map[string]interface{}{
+				"server": map[string]interface{}{
+					"name":        name,
+					"description": "Server " + name,
+					"version":     version,
+					"repository":  map[string]interface{}{"url": "https://github.com/test/" + name, "source": "github", "id": "test/" + name},
+				},
+				"_meta": map[string]interface{}{
+					"io.modelcontextprotocol.registry/official": map[string]interface{}{
+						"serverId": "id-" + name,
+					},
+				},
+			})
+		}
+
+		response := map[string]interface{}{
+			"servers":  wrappers,
+			"metadata": map[string]interface{}{"count": len(wrappers)},
+		}
+		if page+1 < len(pages) {
+			response["metadata"].(map[string]interface{})["nextCursor"] = fmt.Sprintf("page-%d", page+1)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestWithPageParams(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		cursor   string
+		limit    int
+		want     string
+	}{
+		{endpoint: "/v0/servers", want: "/v0/servers"},
+		{endpoint: "/v0/servers", cursor: "abc", limit: 5, want: "/v0/servers?cursor=abc&limit=5"},
+		{endpoint: "/v0/servers?search=file", limit: 10, want: "/v0/servers?limit=10&search=file"},
+		{endpoint: "/v0/servers", cursor: "a b&c", want: "/v0/servers?cursor=a+b%26c"},
+	}
+
+	for _, tt := range tests {
+		if got := withPageParams(tt.endpoint, tt.cursor, tt.limit); got != tt.want {
+			t.Errorf("withPageParams(%q, %q, %d) = %q, want %q", tt.endpoint, tt.cursor, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestFetchAllPages(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{
+		{"io.test/a", "io.test/b"},
+		{"io.test/c", "io.test/d"},
+		{"io.test/e"},
+	})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+
+	servers, metadata, err := client.fetchAllPages("/v0/servers", "", 2)
+	if err != nil {
+		t.Fatalf("fetchAllPages() error = %v", err)
+	}
+	if len(servers) != 5 || metadata.Count != 5 {
+		t.Errorf("Expected 5 servers across all pages, got %d (count %d)", len(servers), metadata.Count)
+	}
+	if metadata.NextCursor != "" {
+		t.Errorf("Expected no next cursor after fetching all pages, got %q", metadata.NextCursor)
+	}
+	if servers[4].ID != "id-io.test/e" {
+		t.Errorf("Expected server IDs to be extracted from wrapper metadata, got %q", servers[4].ID)
+	}
+
+	servers, _, err = client.fetchAllPages("/v0/servers", "page-1", 2)
+	if err != nil {
+		t.Fatalf("fetchAllPages() from cursor error = %v", err)
+	}
+	if len(servers) != 3 {
+		t.Errorf("Expected 3 servers when starting from the second page, got %d", len(servers))
+	}
+}
+
+func TestFieldSelectors(t *testing.T) {
+	if _, err := parseFieldSelectors("owner=me"); err == nil || !strings.Contains(err.Error(), "source, status") {
+		t.Errorf("Expected an unknown selector to be rejected with the known keys, got %v", err)
+	}
+	selectors, err := parseFieldSelectors("Status=active,source=github")
+	if err != nil || len(selectors) != 2 || selectors[0] != (fieldSelector{Key: "status", Value: "active"}) {
+		t.Fatalf("Unexpected selectors %v (%v)", selectors, err)
+	}
+
+	listing := `{"servers":[
+		{"name":"io.test/active","version":"1.0.0","repository":{"source":"github"}},
+		{"name":"io.test/old","version":"1.0.0","status":"deprecated","repository":{"source":"github"}},
+		{"name":"io.test/gitlab","version":"1.0.0","repository":{"source":"gitlab"}}
+	],"metadata":{"count":3}}`
+	for _, tt := range []struct {
+		name     string
+		rejected bool
+	}{{"server-side", false}, {"fallback on 400", true}} {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []url.Values
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queries = append(queries, r.URL.Query())
+				if tt.rejected && r.URL.Query().Get("status") != "" {
+					http.Error(w, `{"detail":"unknown parameter status"}`, http.StatusBadRequest)
+					return
+				}
+				// The mock ignores the selectors, so the client-side check must still apply them
+				_, _ = fmt.Fprint(w, listing)
+			}))
+			defer mockServer.Close()
+
+			var logs bytes.Buffer
+			client := NewMCPXClient(mockServer.URL)
+			client.cacheDir = ""
+			client.logger = NewLogger(&logs, LogFormatText, false)
+			output, err := captureStdoutErr(t, func() error {
+				return client.ListServers(ListServersOptions{IDOnly: true, All: true, FieldSelectors: selectors})
+			})
+			if err != nil {
+				t.Fatalf("ListServers failed: %v", err)
+			}
+			if strings.TrimSpace(output) != generateServerID("io.test/active") {
+				t.Errorf("Expected only the active GitHub server, got %q", output)
+			}
+			if queries[0].Get("status") != "active" || queries[0].Get("source") != "github" {
+				t.Errorf("Expected the selectors as query parameters, got %v", queries[0])
+			}
+			warned := strings.Contains(logs.String(), "filtering client-side instead")
+			if tt.rejected && (len(queries) != 2 || queries[1].Get("status") != "" || !warned) {
+				t.Errorf("Expected a warning and a refetch without selectors, got queries %v and logs %q", queries, logs.String())
+			}
+			if !tt.rejected && (len(queries) != 1 || warned) {
+				t.Errorf("Expected a single server-side request, got queries %v and logs %q", queries, logs.String())
+			}
+		})
+	}
+}
+
+func TestFetchAllPagesDeduplicates(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{
+		{"io.test/a", "io.test/b"},
+		{"io.test/b", "io.test/c"},
+		{"io.test/c", "io.test/c"},
+	})
+	defer mockServer.Close()
+
+	var logs bytes.Buffer
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.logger = NewLogger(&logs, LogFormatText, true)
+
+	servers, metadata, err := client.fetchAllPages("/v0/servers", "", 2)
+	if err != nil {
+		t.Fatalf("fetchAllPages() error = %v", err)
+	}
+	var names []string
+	for _, server := range servers {
+		names = append(names, server.Name)
+	}
+	if strings.Join(names, ",") != "io.test/a,io.test/b,io.test/c" || metadata.Count != 3 {
+		t.Errorf("Expected overlapping pages to be collapsed, got %v (count %d)", names, metadata.Count)
+	}
+	if !strings.Contains(logs.String(), "collapsed duplicate servers returned by the registry") || !strings.Contains(logs.String(), "duplicates=3") {
+		t.Errorf("Expected the duplicates to be reported in verbose mode, got:\n%s", logs.String())
+	}
+
+	// Versions of one server share the server ID but are distinct entries
+	v1 := Server{Name: "io.test/a", Version: "1.0.0", Meta: &ServerMeta{Official: &RegistryExtensions{ServerID: "sid"}}}
+	v2 := Server{Name: "io.test/a", Version: "2.0.0", Meta: &ServerMeta{Official: &RegistryExtensions{ServerID: "sid"}}}
+	if serverListKey(v1) == serverListKey(v2) {
+		t.Errorf("Expected different versions to have different keys, got %q", serverListKey(v1))
+	}
+}
+
+func TestSearchAndVersionsPagination(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{
+		{"io.test/a"},
+		{"io.test/b"},
+	})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = client.SearchServers("test", ListServersOptions{Limit: 1, All: true})
+	})
+	if err != nil {
+		t.Fatalf("SearchServers() error = %v", err)
+	}
+	if !strings.Contains(output, "Total Servers: 2") {
+		t.Errorf("Expected search --all to follow cursors, got %v", output)
+	}
+
+	output = captureStdout(t, func() {
+		err = client.ListServerVersions("io.test/a", ListServersOptions{Limit: 1, JSON: true})
+	})
+	if err != nil {
+		t.Fatalf("ListServerVersions() error = %v", err)
+	}
+	var response LegacyServersResponse
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		t.Fatalf("Invalid JSON output: %v\nOutput: %s", err, output)
+	}
+	if len(response.Servers) != 1 || response.Metadata.NextCursor != "page-1" {
+		t.Errorf("Expected a single page with a next cursor, got %+v", response)
+	}
+}
+
+func TestIterateServers(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{
+		{"io.test/a", "io.test/b"},
+		{"io.test/c"},
+	})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+
+	t.Run("visits every server across pages", func(t *testing.T) {
+		var names []string
+		err := client.iterateServers("", 2, func(server Server) error {
+			names = append(names, server.Name)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("iterateServers() error = %v", err)
+		}
+		if strings.Join(names, ",") != "io.test/a,io.test/b,io.test/c" {
+			t.Errorf("Unexpected iteration order %v", names)
+		}
+	})
+
+	t.Run("stops early without error", func(t *testing.T) {
+		visited := 0
+		err := client.iterateServers("", 2, func(server Server) error {
+			visited++
+			return errStopIteration
+		})
+		if err != nil {
+			t.Fatalf("iterateServers() error = %v", err)
+		}
+		if visited != 1 {
+			t.Errorf("Expected iteration to stop after the first server, visited %d", visited)
+		}
+	})
+
+	t.Run("propagates yield errors", func(t *testing.T) {
+		wantErr := fmt.Errorf("consumer failed")
+		err := client.iterateServers("", 2, func(server Server) error {
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("Expected yield error to be returned, got %v", err)
+		}
+	})
+
+	t.Run("reports registry errors", func(t *testing.T) {
+		err := client.iterateServers("bogus", 2, func(server Server) error {
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "status 400") {
+			t.Errorf("Expected a status 400 error, got %v", err)
+		}
+	})
+}
+
+func TestParseServersResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantNames []string
+		wantIDs   []string
+		wantMeta  Metadata
+		wantErr   bool
+	}{
+		{
+			name:      "wrapper format",
+			body:      `{"servers":[{"server":{"name":"io.test/a","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"id-a"}}}],"metadata":{"nextCursor":"next","count":1}}`,
+			wantNames: []string{"io.test/a"},
+			wantIDs:   []string{"id-a"},
+			wantMeta:  Metadata{NextCursor: "next", Count: 1},
+		},
+		{
+			name:      "legacy format with _meta",
+			body:      `{"servers":[{"name":"io.test/b","version":"1.0.0","_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"id-b"}}}]}`,
+			wantNames: []string{"io.test/b"},
+			wantIDs:   []string{"id-b"},
+		},
+		{
+			name:      "legacy format with top-level id",
+			body:      `{"servers":[{"id":"id-c","name":"io.test/c","version":"1.0.0"}]}`,
+			wantNames: []string{"io.test/c"},
+			wantIDs:   []string{"id-c"},
+		},
+		{
+			name:      "mixed formats",
+			body:      `{"servers":[{"name":"io.test/legacy","id":"id-l"},{"server":{"name":"io.test/wrapped"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"id-w"}}}]}`,
+			wantNames: []string{"io.test/legacy", "io.test/wrapped"},
+			wantIDs:   []string{"id-l", "id-w"},
+		},
+		{
+			name:     "empty list",
+			body:     `{"servers":[],"metadata":{"total":0}}`,
+			wantMeta: Metadata{},
+		},
+		{
+			name: "missing servers field",
+			body: `{}`,
+		},
+		{
+			name:    "invalid JSON",
+			body:    `{"servers":`,
+			wantErr: true,
+		},
+		{
+			name:    "servers is not an array",
+			body:    `{"servers":"nope"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, metadata, err := parseServersResponse([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseServersResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(servers) != len(tt.wantNames) {
+				t.Fatalf("Expected %d servers, got %d", len(tt.wantNames), len(servers))
+			}
+			for i, server := range servers {
+				if server.Name != tt.wantNames[i] {
+					t.Errorf("servers[%d].Name = %s, want %s", i, server.Name, tt.wantNames[i])
+				}
+				if id := server.GetServerID(); id != tt.wantIDs[i] {
+					t.Errorf("servers[%d] ID = %s, want %s", i, id, tt.wantIDs[i])
+				}
+			}
+			if metadata != tt.wantMeta {
+				t.Errorf("Expected metadata %+v, got %+v", tt.wantMeta, metadata)
+			}
+		})
+	}
+}
+
+func TestListServersEmpty(t *testing.T) {
+	bodies := map[string]string{
+		"wrapper shape": `{"servers":[],"metadata":{"count":0}}`,
+		"legacy shape":  `{"servers":null}`,
+		"no servers":    `{}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, body)
+			}))
+			defer mockServer.Close()
+
+			client := NewMCPXClient(mockServer.URL)
+
+			for _, all := range []bool{false, true} {
+				output := captureStdout(t, func() {
+					if err := client.ListServers(ListServersOptions{All: all}); err != nil {
+						t.Errorf("ListServers() error = %v", err)
+					}
+				})
+				if !strings.Contains(output, "No servers found.") {
+					t.Errorf("Expected 'No servers found.' (all=%v), got %v", all, output)
+				}
+
+				for _, detailed := range []bool{false, true} {
+					output = captureStdout(t, func() {
+						if err := client.ListServers(ListServersOptions{All: all, JSON: true, Detailed: detailed}); err != nil {
+							t.Errorf("ListServers() error = %v", err)
+						}
+					})
+					var response map[string]json.RawMessage
+					if err := json.Unmarshal([]byte(output), &response); err != nil {
+						t.Fatalf("Invalid JSON output: %v\nOutput: %s", err, output)
+					}
+					if string(response["servers"]) != "[]" {
+						t.Errorf("Expected servers to be [] (all=%v, detailed=%v), got %s", all, detailed, response["servers"])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestListServersIDOnly(t *testing.T) {
+	mockServer := createMockServer()
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+
+	output := captureStdout(t, func() {
+		if err := client.ListServers(ListServersOptions{IDOnly: true}); err != nil {
+			t.Errorf("ListServers() error = %v", err)
+		}
+	})
+	want := "58031f85-792f-4c22-9d76-b4dd01e287aa\n69142f85-792f-4c22-9d76-b4dd01e287bb\n"
+	if output != want {
+		t.Errorf("Expected only server IDs %q, got %q", want, output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := client.ListServers(ListServersOptions{IDOnly: true, Filter: "SERVER2"}); err != nil {
+			t.Errorf("ListServers() error = %v", err)
+		}
+	})
+	if output != "69142f85-792f-4c22-9d76-b4dd01e287bb\n" {
+		t.Errorf("Expected the filter to apply to --id-only output, got %q", output)
+	}
+}
+
+func TestETagCaching(t *testing.T) {
+	requests := 0
+	notModified := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"servers":[{"name":"io.test/cached","version":"1.0.0","id":"cached-id"}]}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		output := captureStdout(t, func() {
+			if err := client.ListServers(ListServersOptions{Limit: 5}); err != nil {
+				t.Errorf("ListServers() error = %v", err)
+			}
+		})
+		if !strings.Contains(output, "io.test/cached") {
+			t.Errorf("Request %d: expected the server in the output, got %v", i+1, output)
+		}
+		if !strings.Contains(output, "Status Code: 200") {
+			t.Errorf("Request %d: expected a 304 to be served as 200 from cache, got %v", i+1, output)
+		}
+	}
+
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected the second request to be conditional and answered with 304, got %d requests, %d not modified", requests, notModified)
+	}
+
+	t.Run("disabled cache sends no conditional request", func(t *testing.T) {
+		client.cacheDir = ""
+		captureStdout(t, func() {
+			_ = client.ListServers(ListServersOptions{Limit: 5})
+		})
+		if notModified != 1 {
+			t.Errorf("Expected no If-None-Match header without a cache directory")
+		}
+	})
+}
+
+func TestListServersRepositoryFilterAndGrouping(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"servers":[
+			{"name":"io.test/mono-a","version":"1.0.0","repository":{"url":"https://github.com/test/monorepo","source":"github"}},
+			{"name":"io.test/mono-b","version":"1.0.0","repository":{"url":"https://github.com/test/monorepo","source":"github"}},
+			{"name":"io.test/single","version":"2.0.0","repository":{"url":"https://gitlab.com/test/single","source":"gitlab"}}
+		]}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+
+	t.Run("repository url filter", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			_ = client.ListServers(ListServersOptions{RepositoryURL: "GITLAB.com"})
+		})
+		if !strings.Contains(output, "io.test/single") || strings.Contains(output, "io.test/mono-a") {
+			t.Errorf("Expected only the gitlab server, got %v", output)
+		}
+	})
+
+	t.Run("group by repository in JSON", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			_ = client.ListServers(ListServersOptions{GroupBy: "repository", JSON: true})
+		})
+		var groups map[string][]Server
+		if err := json.Unmarshal([]byte(output), &groups); err != nil {
+			t.Fatalf("Invalid JSON output: %v\nOutput: %s", err, output)
+		}
+		if len(groups["https://github.com/test/monorepo"]) != 2 || len(groups["https://gitlab.com/test/single"]) != 1 {
+			t.Errorf("Unexpected grouping %+v", groups)
+		}
+	})
+
+	t.Run("group by repository in text", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			_ = client.ListServers(ListServersOptions{GroupBy: "repository"})
+		})
+		if !strings.Contains(output, "=== https://github.com/test/monorepo (2) ===") {
+			t.Errorf("Expected a monorepo group header, got %v", output)
+		}
+		if !strings.Contains(output, "in 2 repositories") {
+			t.Errorf("Expected a repository count, got %v", output)
+		}
+	})
+}
+
+func TestPublishServerIdempotencyKey(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	recorded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+	reset := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = nil
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/auth/none" {
+			_ = json.NewEncoder(w).Encode(TokenResponse{RegistryToken: "idem-token", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+			return
+		}
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		first := len(keys) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = fmt.Fprint(w, `{"detail":"validation failed"}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"message":"Server published successfully"}`)
+	}))
+	defer mockServer.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	serverFile := createTempServerFile(t, exampleServerNPMJSON)
+	defer func() { _ = os.Remove(serverFile) }()
+
+	t.Run("generated key reused on retry", func(t *testing.T) {
+		reset()
+		client := NewMCPXClient(mockServer.URL)
+		if _, err := captureStdoutErr(t, func() error { return client.PublishServer(serverFile, "", PublishOptions{}) }); err != nil {
+			t.Fatalf("PublishServer failed: %v", err)
+		}
+		got := recorded()
+		if len(got) != 2 {
+			t.Fatalf("Expected 2 publish attempts, got %d", len(got))
+		}
+		if got[0] == "" || got[0] != got[1] {
+			t.Errorf("Expected the same non-empty key on both attempts, got %q and %q", got[0], got[1])
+		}
+	})
+
+	t.Run("caller supplied key", func(t *testing.T) {
+		reset()
+		client := NewMCPXClient(mockServer.URL)
+		if _, err := captureStdoutErr(t, func() error { return client.PublishServer(serverFile, "", PublishOptions{IdempotencyKey: "my-key"}) }); err != nil {
+			t.Fatalf("PublishServer failed: %v", err)
+		}
+		for i, key := range recorded() {
+			if key != "my-key" {
+				t.Errorf("Attempt %d: expected key %q, got %q", i+1, "my-key", key)
+			}
+		}
+	})
+}
+
+func TestLintServerDetail(t *testing.T) {
+	codes := func(findings []lintFinding) []string {
+		var result []string
+		for _, f := range findings {
+			result = append(result, f.Code)
+		}
+		return result
+	}
+
+	var clean ServerDetail
+	if err := json.Unmarshal(exampleServerNPMJSON, &clean); err != nil {
+		t.Fatalf("Failed to parse example server: %v", err)
+	}
+	if findings := lintServerDetail(clean); len(findings) != 0 {
+		t.Errorf("Expected no findings for the example server, got %v", findings)
+	}
+
+	messy := ServerDetail{
+		Server: Server{Name: "io.test/server", Version: "1.0.0"},
+		Packages: []Package{{
+			RegistryType: RegistryTypeNPM,
+			Identifier:   "@test/server",
+			Version:      "^1.0.0",
+			EnvironmentVariables: []KeyValueInput{
+				{Name: "API_TOKEN"},
+				{Name: "DB_PASSWORD", IsSecret: true},
+			},
+		}},
+	}
+	got := strings.Join(codes(lintServerDetail(messy)), ",")
+	if got != "MCPX001,MCPX003,MCPX004,MCPX005" {
+		t.Errorf("Unexpected findings: %s", got)
+	}
+
+	empty := ServerDetail{Server: Server{Description: "x", Repository: Repository{URL: "https://example.com"}}}
+	if got := codes(lintServerDetail(empty)); len(got) != 1 || got[0] != "MCPX002" {
+		t.Errorf("Expected only MCPX002, got %v", got)
+	}
+}
+
+func TestLintServerFileFailOn(t *testing.T) {
+	client := NewMCPXClient("http://localhost")
+	serverFile := createTempServerFile(t, []byte(`{"name":"io.test/server","version":"1.0.0","remotes":[{"type":"sse","url":"https://example.com"}],"repository":{"url":"https://example.com"}}`))
+	defer func() { _ = os.Remove(serverFile) }()
+
+	output := captureStdout(t, func() {
+		if err := client.LintServerFile(serverFile, "", false); err != nil {
+			t.Errorf("Expected no error without --fail-on, got %v", err)
+		}
+		if err := client.LintServerFile(serverFile, LintSeverityError, false); err != nil {
+			t.Errorf("Expected no error for --fail-on error, got %v", err)
+		}
+		if err := client.LintServerFile(serverFile, LintSeverityWarning, false); err == nil {
+			t.Error("Expected an error for --fail-on warning")
+		}
+	})
+	if !strings.Contains(output, "MCPX001 [warning]") {
+		t.Errorf("Expected MCPX001 in output, got %s", output)
+	}
+}
+
+func TestFindServersByRepository(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{{"alpha", "beta"}, {"gamma"}})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output := captureStdout(t, func() {
+		if err := client.FindServersByRepository("TEST/gamma", false); err != nil {
+			t.Errorf("FindServersByRepository failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "gamma 1.0.0 (ID: id-gamma)") || strings.Contains(output, "alpha") {
+		t.Errorf("Expected only gamma across pages, got %s", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := client.FindServersByRepository("test/missing", true); err != nil {
+			t.Errorf("FindServersByRepository failed: %v", err)
+		}
+	})
+	var resp LegacyServersResponse
+	if err := json.Unmarshal([]byte(output), &resp); err != nil {
+		t.Fatalf("Expected JSON output, got %s", output)
+	}
+	if resp.Servers == nil || len(resp.Servers) != 0 {
+		t.Errorf("Expected an empty server array, got %v", resp.Servers)
+	}
+}
+
+func TestClassifyNetworkError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		kind      int
+		retryable bool
+		message   string
+	}{
+		{"dns", &url.Error{Op: "Get", URL: "http://nope.invalid", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Name: "nope.invalid", IsNotFound: true}}}, NetworkErrorDNS, false, "could not resolve host"},
+		{"dns timeout", &net.DNSError{Name: "slow.example", IsTimeout: true}, NetworkErrorTimeout, true, "timed out"},
+		{"refused", &url.Error{Op: "Get", URL: "http://localhost:1", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, NetworkErrorRefused, true, "refused"},
+		{"deadline", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), NetworkErrorTimeout, true, "timed out"},
+		{"other", errors.New("boom"), NetworkErrorUnknown, false, "failed: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqErr := newRequestError("registry.example:8080", tt.err)
+			if reqErr.Kind != tt.kind {
+				t.Errorf("Kind = %d, want %d", reqErr.Kind, tt.kind)
+			}
+			if reqErr.Retryable() != tt.retryable {
+				t.Errorf("Retryable() = %v, want %v", reqErr.Retryable(), tt.retryable)
+			}
+			if !strings.Contains(reqErr.Error(), tt.message) {
+				t.Errorf("Error() = %q, want it to contain %q", reqErr.Error(), tt.message)
+			}
+			if !errors.Is(reqErr, tt.err) {
+				t.Error("Expected the original error to be unwrappable")
+			}
+		})
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	mockServer.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	mockServer.Config.ErrorLog = log.New(io.Discard, "", 0)
+	mockServer.StartTLS()
+	defer mockServer.Close()
+
+	newClient := func() *MCPXClient {
+		client := NewMCPXClient(mockServer.URL)
+		client.cacheDir = ""
+		client.logger = NewLogger(io.Discard, LogFormatText, false)
+		roots := x509.NewCertPool()
+		roots.AddCert(mockServer.Certificate())
+		client.httpClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: roots}
+		return client
+	}
+
+	client := newClient()
+	if err := client.setTLSMinVersion("1.2"); err != nil {
+		t.Fatalf("setTLSMinVersion(1.2) failed: %v", err)
+	}
+	if _, err := client.makeRequest("GET", "/v0/health", nil, "none"); err != nil {
+		t.Errorf("Expected a TLS 1.2 registry to satisfy --tls-min-version 1.2, got %v", err)
+	}
+
+	client = newClient()
+	if err := client.setTLSMinVersion("1.3"); err != nil {
+		t.Fatalf("setTLSMinVersion(1.3) failed: %v", err)
+	}
+	_, err := client.makeRequest("GET", "/v0/health", nil, "none")
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.Kind != NetworkErrorTLSVersion || reqErr.Retryable() {
+		t.Fatalf("Expected a non-retryable TLS version RequestError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "TLS handshake with") || !strings.Contains(err.Error(), "--tls-min-version") {
+		t.Errorf("Expected a clear handshake error, got %v", err)
+	}
+
+	if err := newClient().setTLSMinVersion("1.1"); err == nil {
+		t.Error("Expected TLS 1.1 to be rejected as a minimum version")
+	}
+}
+
+// writeTestKeyPair writes a self-signed PEM certificate and its private key to dir
+func writeTestKeyPair(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	certFile, keyFile = filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestClientCertificate(t *testing.T) {
+	var presented string
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented = r.TLS.PeerCertificates[0].Subject.CommonName
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	mockServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	mockServer.Config.ErrorLog = log.New(io.Discard, "", 0)
+	mockServer.StartTLS()
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTestKeyPair(t, dir, "cli")
+	_, otherKeyFile := writeTestKeyPair(t, dir, "other")
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.logger = NewLogger(io.Discard, LogFormatText, false)
+	roots := x509.NewCertPool()
+	roots.AddCert(mockServer.Certificate())
+	client.httpClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: roots}
+	if err := client.setClientCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("setClientCertificate failed: %v", err)
+	}
+	if _, err := client.makeRequest("GET", "/v0/health", nil, "none"); err != nil {
+		t.Fatalf("Expected the mTLS request to succeed, got %v", err)
+	}
+	if presented != "cli" {
+		t.Errorf("Expected the client certificate to be presented, got %q", presented)
+	}
+
+	if err := client.setClientCertificate(certFile, ""); err == nil || !strings.Contains(err.Error(), "together") {
+		t.Errorf("Expected an error for a certificate without a key, got %v", err)
+	}
+	if err := client.setClientCertificate(certFile, otherKeyFile); err == nil || !strings.Contains(err.Error(), "does not belong") {
+		t.Errorf("Expected an error for a mismatched key, got %v", err)
+	}
+}
+
+func TestRequestRetries(t *testing.T) {
+	t.Run("connection refused", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to reserve a port: %v", err)
+		}
+		addr := listener.Addr().String()
+		_ = listener.Close()
+
+		client := NewMCPXClient("http://" + addr)
+		client.retries = 2
+		client.retryBackoff = time.Millisecond
+		client.logger = NewLogger(io.Discard, LogFormatText, false)
+		_, err = client.makeRequest("GET", "/v0/health", nil, "none")
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) || reqErr.Kind != NetworkErrorRefused {
+			t.Fatalf("Expected a connection refused RequestError, got %v", err)
+		}
+	})
+
+	t.Run("timeout then success", func(t *testing.T) {
+		var calls int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+		}))
+		defer mockServer.Close()
+
+		client := NewMCPXClient(mockServer.URL)
+		client.httpClient.Timeout = 50 * time.Millisecond
+		client.retryBackoff = time.Millisecond
+		client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+		if _, err := client.makeRequest("GET", "/v0/health", nil, "none"); classifyNetworkError(err) != NetworkErrorTimeout {
+			t.Fatalf("Expected a timeout without retries, got %v", err)
+		}
+
+		atomic.StoreInt32(&calls, 0)
+		client.retries = 1
+		resp, err := client.makeRequest("GET", "/v0/health", nil, "none")
+		if err != nil {
+			t.Fatalf("Expected the retry to succeed, got %v", err)
+		}
+		_ = resp.Body.Close()
+		if atomic.LoadInt32(&calls) != 2 {
+			t.Errorf("Expected 2 attempts, got %d", calls)
+		}
+	})
+}
+
+func TestTruncatedBodyRetry(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Promise more bytes than are sent, then drop the connection mid-body
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Hijack failed: %v", err)
+				return
+			}
+			_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n{\"status\":")
+			_ = buf.Flush()
+			_ = conn.Close()
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.retryBackoff = time.Millisecond
+	client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+	// Without retries the short read is reported as a network error, not as invalid JSON
+	resp, err := client.makeRequest("GET", "/v0/health", nil, "none")
+	if err != nil {
+		t.Fatalf("makeRequest failed: %v", err)
+	}
+	_, err = client.readResponseBody(resp)
+	_ = resp.Body.Close()
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.Kind != NetworkErrorTruncated || !strings.Contains(err.Error(), "closed before the whole response arrived") {
+		t.Fatalf("Expected a truncated-body RequestError, got %v", err)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	client.retries = 1
+	resp, err = client.makeRequest("GET", "/v0/health", nil, "none")
+	if err != nil {
+		t.Fatalf("Expected the truncated body to be retried, got %v", err)
+	}
+	body, err := client.readResponseBody(resp)
+	_ = resp.Body.Close()
+	if err != nil || string(body) != `{"status":"ok"}` {
+		t.Errorf("Expected the full body of the second attempt, got %q, %v", body, err)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		method     string
+		idempotent bool
+	}{
+		{"GET", true}, {"HEAD", true}, {"PUT", true}, {"DELETE", true}, {"POST", false}, {"PATCH", false},
+	} {
+		if isIdempotentMethod(tt.method) != tt.idempotent || retryableStatus(tt.method, http.StatusServiceUnavailable) != tt.idempotent {
+			t.Errorf("%s: expected idempotent=%v to decide status retries", tt.method, tt.idempotent)
+		}
+	}
+	if retryableStatus("GET", http.StatusInternalServerError) || retryableStatus("GET", http.StatusNotFound) {
+		t.Error("Expected only transient statuses to be retried")
+	}
+
+	newClient := func(baseURL string) *MCPXClient {
+		client := NewMCPXClient(baseURL)
+		client.cacheDir = ""
+		client.retries = 2
+		client.retryBackoff = time.Millisecond
+		client.sleep = func(time.Duration) {}
+		client.logger = NewLogger(io.Discard, LogFormatText, false)
+		return client
+	}
+
+	t.Run("transient status", func(t *testing.T) {
+		var calls int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+		}))
+		defer mockServer.Close()
+		client := newClient(mockServer.URL)
+
+		for method, wantStatus := range map[string]int{"PUT": http.StatusOK, "POST": http.StatusServiceUnavailable} {
+			atomic.StoreInt32(&calls, 0)
+			resp, err := client.makeRequest(method, "/v0/servers/x", []byte(`{}`), "none")
+			if err != nil {
+				t.Fatalf("%s failed: %v", method, err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != wantStatus {
+				t.Errorf("%s: expected status %d after the retry policy, got %d (%d calls)", method, wantStatus, resp.StatusCode, calls)
+			}
+		}
+	})
+
+	t.Run("post is not retried once sent", func(t *testing.T) {
+		var calls int32
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+		}))
+		defer mockServer.Close()
+		client := newClient(mockServer.URL)
+		client.httpClient.Timeout = 50 * time.Millisecond
+
+		if _, err := client.makeRequest("POST", "/v0/publish", []byte(`{}`), "none"); classifyNetworkError(err) != NetworkErrorTimeout {
+			t.Fatalf("Expected the POST timeout to be returned, got %v", err)
+		}
+		if calls := atomic.LoadInt32(&calls); calls != 1 {
+			t.Errorf("Expected a single POST attempt, got %d", calls)
+		}
+	})
+
+	t.Run("post is retried before it is sent", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to reserve a port: %v", err)
+		}
+		addr := listener.Addr().String()
+		_ = listener.Close()
+
+		client := newClient("http://" + addr)
+		var logs bytes.Buffer
+		client.logger = NewLogger(&logs, LogFormatText, true)
+		if _, err := client.makeRequest("POST", "/v0/publish", []byte(`{}`), "none"); err == nil {
+			t.Fatal("Expected the refused connection to fail")
+		}
+		if !strings.Contains(logs.String(), "request gave up after 3 attempts") {
+			t.Errorf("Expected a refused POST to be retried, got:\n%s", logs.String())
+		}
+	})
+}
+
+func TestRetryMetrics(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	client := NewMCPXClient("http://" + addr)
+	client.retries = 2
+	client.retryBackoff = time.Millisecond
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, true)
+	if _, err := client.makeRequest("GET", "/v0/health", nil, "none"); err == nil {
+		t.Fatal("Expected the refused connection to fail")
+	}
+	if !strings.Contains(logs.String(), "request gave up after 3 attempts") {
+		t.Errorf("Expected a gave-up summary, got:\n%s", logs.String())
+	}
+
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer mockServer.Close()
+	client.baseURL = mockServer.URL
+	client.sleep = func(time.Duration) {}
+	logs.Reset()
+	resp, err := client.makeRequest("GET", "/v0/health", nil, "none")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	for _, want := range []string{"retrying request", "reason=429 Too Many Requests", "delay=1ms", "request succeeded after 2 attempts"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("Expected %q in the verbose log, got:\n%s", want, logs.String())
+		}
+	}
+
+	// Without --verbose only the existing warnings are shown
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	requests = 0
+	logs.Reset()
+	resp, err = client.makeRequest("GET", "/v0/health", nil, "none")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if strings.Contains(logs.String(), "succeeded after") {
+		t.Errorf("Expected no retry summary without --verbose, got:\n%s", logs.String())
+	}
+}
+
+func TestPublishServerRaw(t *testing.T) {
+	var received []byte
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"message":"Server published successfully","id":"raw-id"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	raw := []byte(`{"x-publisher":{"tool":"ci","build":42},"server":{"version":"nightly","name":"io.test/raw"}}`)
+	bodyFile := createTempServerFile(t, raw)
+	defer func() { _ = os.Remove(bodyFile) }()
+
+	if _, err := captureStdoutErr(t, func() error { return client.PublishServer(bodyFile, "test-token", PublishOptions{Raw: true}) }); err != nil {
+		t.Fatalf("PublishServer failed: %v", err)
+	}
+	if !bytes.Equal(received, raw) {
+		t.Errorf("Expected the body to be sent verbatim\ngot:  %s\nwant: %s", received, raw)
+	}
+
+	githubFile := createTempServerFile(t, []byte(`{"server":{"name":"io.github.test/raw","version":"1.0.0"}}`))
+	defer func() { _ = os.Remove(githubFile) }()
+	_ = captureStdout(t, func() {
+		err := client.PublishServer(githubFile, "", PublishOptions{Raw: true})
+		if err == nil || !strings.Contains(err.Error(), "authentication token is required") {
+			t.Errorf("Expected the io.github.* token check to apply to raw bodies, got %v", err)
+		}
+	})
+}
+
+func TestBuildPublishBody(t *testing.T) {
+	decode := func(t *testing.T, body []byte) map[string]map[string]interface{} {
+		t.Helper()
+		var request map[string]map[string]interface{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Fatalf("Invalid publish body: %v", err)
+		}
+		return request
+	}
+
+	t.Run("bare manifest gets CLI metadata", func(t *testing.T) {
+		body, detail, err := buildPublishBody(exampleServerNPMJSON, map[string]string{"pipeline": "release"}, "", nil, false)
+		if err != nil {
+			t.Fatalf("buildPublishBody failed: %v", err)
+		}
+		if detail.Name != "io.modelcontextprotocol.anonymous/test-server-node" {
+			t.Errorf("Unexpected server name %q", detail.Name)
+		}
+		request := decode(t, body)
+		if request["x-publisher"]["tool"] != "mcpx-cli" || request["x-publisher"]["pipeline"] != "release" {
+			t.Errorf("Expected CLI and flag metadata, got %v", request["x-publisher"])
+		}
+		// Fields the CLI does not model must survive
+		if request["server"]["websiteUrl"] == nil || request["server"]["$schema"] == nil {
+			t.Errorf("Expected unmodelled server fields to be preserved, got %v", request["server"])
+		}
+	})
+
+	t.Run("file metadata is not clobbered", func(t *testing.T) {
+		file := []byte(`{"server":{"name":"io.test/server","version":"1.0.0"},"x-publisher":{"tool":"my-tool"}}`)
+		body, _, err := buildPublishBody(file, map[string]string{"extra": "1"}, "", nil, false)
+		if err != nil {
+			t.Fatalf("buildPublishBody failed: %v", err)
+		}
+		publisher := decode(t, body)["x-publisher"]
+		if publisher["tool"] != "my-tool" || publisher["version"] != nil || publisher["extra"] != "1" {
+			t.Errorf("Expected file metadata plus flag entries only, got %v", publisher)
+		}
+	})
+
+	t.Run("forced schema version", func(t *testing.T) {
+		// A bare manifest that happens to have a "server" object is ambiguous; v1 treats it as the server
+		file := []byte(`{"name":"io.test/server","version":"1.0.0","server":{"name":"nested"}}`)
+		if v, _ := detectSchemaVersion(file); v != SchemaVersionV2 {
+			t.Errorf("Expected the wrapper to be detected, got %s", v)
+		}
+		_, detail, err := buildPublishBody(file, nil, SchemaVersionV1, nil, false)
+		if err != nil || detail.Name != "io.test/server" {
+			t.Errorf("Expected v1 to read the top-level manifest, got %q, %v", detail.Name, err)
+		}
+
+		if _, _, err := buildPublishBody(exampleServerNPMJSON, nil, SchemaVersionV2, nil, false); err == nil {
+			t.Error("Expected v2 to reject a manifest without a server object")
+		}
+		if _, _, err := buildPublishBody(exampleServerNPMJSON, nil, "v3", nil, false); err == nil {
+			t.Error("Expected an unknown schema version to be rejected")
+		}
+	})
+
+	t.Run("normalization", func(t *testing.T) {
+		file := []byte(`{"name":" io.test/server ","version":"1.0.0\n","description":"d","remotes":[],` +
+			`"repository":{"url":"https://github.com/o/r","source":"GitHub"},` +
+			`"packages":[{"registryType":"NPM","identifier":"pkg","version":"1.0.0","transport":{"type":"Stdio"},"environmentVariables":[]}],` +
+			`"_meta":{"io.example/custom":{"note":" kept ","tags":[]}}}`)
+		body, detail, err := buildPublishBody(file, nil, "", nil, true)
+		if err != nil {
+			t.Fatalf("buildPublishBody failed: %v", err)
+		}
+		if detail.Name != "io.test/server" || detail.Version != "1.0.0" {
+			t.Errorf("Expected trimmed name and version, got %q %q", detail.Name, detail.Version)
+		}
+		server := decode(t, body)["server"]
+		if _, ok := server["remotes"]; ok {
+			t.Errorf("Expected the empty remotes list to be dropped, got %v", server)
+		}
+		pkg := server["packages"].([]interface{})[0].(map[string]interface{})
+		if pkg["registryType"] != "npm" || pkg["transport"].(map[string]interface{})["type"] != "stdio" || pkg["environmentVariables"] != nil {
+			t.Errorf("Expected a lower-cased, tidied package, got %v", pkg)
+		}
+		if server["repository"].(map[string]interface{})["source"] != "github" {
+			t.Errorf("Expected a lower-cased repository source, got %v", server["repository"])
+		}
+		custom := server["_meta"].(map[string]interface{})["io.example/custom"].(map[string]interface{})
+		if custom["note"] != " kept " || custom["tags"] == nil {
+			t.Errorf("Expected _meta to be left alone, got %v", custom)
+		}
+
+		_, detail, err = buildPublishBody(file, nil, "", nil, false)
+		if err != nil || detail.Name != " io.test/server " {
+			t.Errorf("Expected --no-normalize to keep the manifest as written, got %q, %v", detail.Name, err)
+		}
+	})
+
+	t.Run("field overrides", func(t *testing.T) {
+		file := []byte(`{"server":{"name":"io.test/server","version":"0.0.0","websiteUrl":"https://example.com"}}`)
+		overrides := []fieldOverride{{Field: "version", Value: "1.2.3"}, {Field: "name", Value: "io.github.owner/server"}, {Field: "title", Value: "Server"}}
+		body, detail, err := buildPublishBody(file, nil, "", overrides, false)
+		if err != nil {
+			t.Fatalf("buildPublishBody failed: %v", err)
+		}
+		if detail.Version != "1.2.3" || detail.Name != "io.github.owner/server" {
+			t.Errorf("Expected the overrides in the parsed server, got %s %s", detail.Name, detail.Version)
+		}
+		server := decode(t, body)["server"]
+		if server["version"] != "1.2.3" || server["title"] != "Server" || server["websiteUrl"] != "https://example.com" {
+			t.Errorf("Expected the overrides in the request with other fields kept, got %v", server)
+		}
+
+		if _, _, err := buildPublishBody(exampleServerNPMJSON, nil, "", []fieldOverride{{Field: "packages", Value: "x"}}, false); err == nil || !strings.Contains(err.Error(), "object or list") {
+			t.Errorf("Expected overriding a list to fail, got %v", err)
+		}
+	})
+}
+
+func TestRoundTripManifest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	client := NewMCPXClient("http://localhost")
+	client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+	lossless := write("lossless.json", `{"server":{"name":"io.test/server","description":"d","version":"1.0.0",`+
+		`"repository":{"url":"https://github.com/o/r","source":"github","id":"o/r"},`+
+		`"packages":[{"registryType":"npm","identifier":"pkg","version":"1.0.0","environmentVariables":[{"name":"A","isRequired":false}]}]}}`)
+	output := captureStdout(t, func() {
+		if err := client.RoundTripManifest(lossless); err != nil {
+			t.Errorf("Expected a lossless round trip, got %v", err)
+		}
+	})
+	if !strings.Contains(output, "- packages[0].environmentVariables[0].isRequired: false (empty or default, no data lost)") {
+		t.Errorf("Expected the omitted default to be listed as harmless, got:\n%s", output)
+	}
+
+	lossy := write("lossy.json", `{"name":"io.test/server","description":"d","version":"1.0.0","websiteUrl":"https://example.com",`+
+		`"repository":{"url":"https://github.com/o/r","source":"github","id":"o/r"}}`)
+	output = captureStdout(t, func() {
+		if err := client.RoundTripManifest(lossy); err == nil || !strings.Contains(err.Error(), "1 field(s)") {
+			t.Errorf("Expected a lossy round trip, got %v", err)
+		}
+	})
+	if !strings.Contains(output, `- websiteUrl: "https://example.com"`+"\n") {
+		t.Errorf("Expected the dropped field in the diff, got:\n%s", output)
+	}
+
+	diffs := diffJSON("", map[string]interface{}{"a": []interface{}{json.Number("1")}}, map[string]interface{}{"a": []interface{}{json.Number("2"), "x"}, "b": ""})
+	var lines []string
+	for _, d := range diffs {
+		lines = append(lines, d.String())
+	}
+	if got := strings.Join(lines, "\n"); got != "~ a[0]: 1 → 2\n+ a[1]: \"x\"\n+ b: \"\"" {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+}
+
+func TestApplyFieldOverrides(t *testing.T) {
+	server := json.RawMessage(`{"name":"io.test/server","version":"1.0.0","x-custom":{"count":1,"enabled":false},
+		"packages":[{"registryType":"npm","identifier":"x","version":"1.0.0","runtimeArguments":[{"type":"positional","isRequired":false}]}]}`)
+
+	updated, err := applyFieldOverrides(server, []fieldOverride{
+		{Field: "repository.url", Value: "https://github.com/owner/server"},
+		{Field: "packages.0.version", Value: "2.0.0"},
+		{Field: "packages.0.runtimeArguments.0.isRequired", Value: "true"},
+		{Field: "x-custom.count", Value: "3"},
+		{Field: "x-custom.enabled", Value: "true"},
+	})
+	if err != nil {
+		t.Fatalf("applyFieldOverrides failed: %v", err)
+	}
+	var detail ServerDetail
+	if err := json.Unmarshal(updated, &detail); err != nil {
+		t.Fatalf("Invalid result %s: %v", updated, err)
+	}
+	if detail.Repository.URL != "https://github.com/owner/server" || detail.Packages[0].Version != "2.0.0" || !detail.Packages[0].RuntimeArguments[0].IsRequired {
+		t.Errorf("Expected the overrides to be applied, got %s", updated)
+	}
+	if !strings.Contains(string(updated), `"x-custom":{"count":3,"enabled":true}`) {
+		t.Errorf("Expected unmodelled fields to keep their JSON types, got %s", updated)
+	}
+
+	for _, tt := range []struct {
+		override fieldOverride
+		want     string
+	}{
+		{fieldOverride{Field: "packages.0.runtimeArguments.0.isRequired", Value: "yes"}, "expected true or false"},
+		{fieldOverride{Field: "packages.3.version", Value: "1"}, "path packages.3 does not exist"},
+		{fieldOverride{Field: "x-missing.field", Value: "1"}, "path x-missing does not exist"},
+		{fieldOverride{Field: "x-custom.other", Value: "1"}, "path does not exist"},
+		{fieldOverride{Field: "repository", Value: "x"}, "object or list"},
+		{fieldOverride{Field: "name.first", Value: "x"}, "name is not an object or list"},
+	} {
+		if _, err := applyFieldOverrides(server, []fieldOverride{tt.override}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Override %s=%s: expected an error containing %q, got %v", tt.override.Field, tt.override.Value, tt.want, err)
+		}
+	}
+}
+
+func TestParsePublishResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       PublishResult
+	}{
+		{"publish response", 201, `{"message":"Server published successfully","id":"pub-id"}`,
+			PublishResult{Success: true, StatusCode: 201, ServerID: "pub-id", Message: "Server published successfully"}},
+		{"wrapper", 200, `{"server":{"name":"io.test/server","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"wrap-id","versionId":"wrap-version"}}}`,
+			PublishResult{Success: true, StatusCode: 200, ServerID: "wrap-id", VersionID: "wrap-version"}},
+		{"legacy server", 200, `{"id":"legacy-id","name":"io.test/server","version":"1.0.0"}`,
+			PublishResult{Success: true, StatusCode: 200, ServerID: "legacy-id"}},
+		{"unknown shape", 201, `{"ok":true}`,
+			PublishResult{Success: true, StatusCode: 201, Response: `{"ok":true}`}},
+		{"failure", 400, `{"detail":"bad request"}`,
+			PublishResult{StatusCode: 400, Error: `{"detail":"bad request"}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePublishResponse(tt.statusCode, []byte(tt.body)); got != tt.want {
+				t.Errorf("parsePublishResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishServerJSON(t *testing.T) {
+	mockServer := createMockServer()
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	serverFile := createTempServerFile(t, exampleServerNPMJSON)
+	defer func() { _ = os.Remove(serverFile) }()
+
+	output, err := captureStdoutErr(t, func() error { return client.PublishServer(serverFile, "test-token", PublishOptions{JSON: true}) })
+	if err != nil {
+		t.Fatalf("PublishServer failed: %v", err)
+	}
+
+	var result PublishResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("Expected only JSON on stdout, got %q", output)
+	}
+	if !result.Success || result.ServerID != "new-server-id" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	t.Run("anonymous login keeps stdout JSON", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		client := NewMCPXClient(mockServer.URL)
+		var logs bytes.Buffer
+		client.logger = NewLogger(&logs, LogFormatText, false)
+
+		output, err := captureStdoutErr(t, func() error { return client.PublishServer(serverFile, "", PublishOptions{JSON: true}) })
+		if err != nil {
+			t.Fatalf("PublishServer failed: %v", err)
+		}
+		var result PublishResult
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("Expected only JSON on stdout, got %q", output)
+		}
+		if !strings.Contains(logs.String(), "Successfully authenticated as anonymous user") {
+			t.Errorf("Expected the login notice on the logger, got %q", logs.String())
+		}
+	})
+}
+
+func TestListServersDetailedSummary(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/servers":
+			_, _ = fmt.Fprint(w, `{"servers":[{"id":"good","name":"io.test/good","version":"1.0.0"},{"id":"bad","name":"io.test/bad","version":"1.0.0"}]}`)
+		case "/v0/servers/good":
+			_, _ = fmt.Fprint(w, `{"id":"good","name":"io.test/good","version":"1.0.0","packages":[{"registryType":"npm","identifier":"good","version":"1.0.0"}]}`)
+		default:
+			http.Error(w, `{"detail":"boom"}`, http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+
+	output, err := captureStdoutErr(t, func() error { return client.ListServers(ListServersOptions{JSON: true, Detailed: true}) })
+	if err != nil {
+		t.Fatalf("ListServers failed: %v", err)
+	}
+
+	var resp LegacyDetailedServersResponse
+	if err := json.Unmarshal([]byte(output), &resp); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	if resp.Details == nil || resp.Details.DetailsFetched != 1 || resp.Details.DetailsFailed != 1 {
+		t.Fatalf("Unexpected details summary %+v", resp.Details)
+	}
+	if len(resp.Details.FailedIDs) != 1 || resp.Details.FailedIDs[0] != "bad" {
+		t.Errorf("Expected failed ID bad, got %v", resp.Details.FailedIDs)
+	}
+	if len(resp.Servers) != 2 || len(resp.Servers[0].Packages) != 1 {
+		t.Errorf("Expected both servers with details for the good one, got %+v", resp.Servers)
+	}
+	if !strings.Contains(logs.String(), "details fetched for 1 of 2 servers") {
+		t.Errorf("Expected a warning on stderr, got %q", logs.String())
+	}
+}
+
+func TestFetchServerDetailsPreservesListID(t *testing.T) {
+	var requested []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		// A wrapper whose server has no ID and no _meta
+		_, _ = fmt.Fprint(w, `{"server":{"name":"io.test/server","version":"1.0.0"}}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	servers := []Server{{
+		Name:    "io.test/server",
+		Version: "1.0.0",
+		Meta:    &ServerMeta{Official: &RegistryExtensions{ServerID: "meta-id"}},
+	}}
+
+	details, summary, err := client.fetchServerDetails(servers)
+	if err != nil {
+		t.Fatalf("fetchServerDetails failed: %v", err)
+	}
+	if summary.DetailsFetched != 1 {
+		t.Errorf("Expected one fetched detail, got %+v", summary)
+	}
+	if len(requested) != 1 || requested[0] != "/v0/servers/meta-id" {
+		t.Errorf("Expected the detail to be requested by the _meta ID, got %v", requested)
+	}
+	if details[0].ID != "meta-id" {
+		t.Errorf("Expected the listing's ID to be kept, got %q", details[0].ID)
+	}
+}
+
+func TestSettingsRegistryAliases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var settings Settings
+	if err := settings.Set("registry.prod", "https://registry.example.com"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := settings.Set("registry.bad", "not a url"); err == nil {
+		t.Error("Expected an error for a non-URL registry")
+	}
+	if err := settings.Set("unknown.key", "x"); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+	if err := saveSettings(settings); err != nil {
+		t.Fatalf("saveSettings failed: %v", err)
+	}
+
+	loaded, err := loadSettings()
+	if err != nil {
+		t.Fatalf("loadSettings failed: %v", err)
+	}
+	if value, ok := loaded.Get("registry.prod"); !ok || value != "https://registry.example.com" {
+		t.Errorf("Get(registry.prod) = %q, %v", value, ok)
+	}
+	if got := resolveRegistry(loaded, "prod"); got != "https://registry.example.com" {
+		t.Errorf("Expected the alias to resolve, got %q", got)
+	}
+	if got := resolveRegistry(loaded, "http://localhost:9090"); got != "http://localhost:9090" {
+		t.Errorf("Expected an unknown alias to be used as a URL, got %q", got)
+	}
+}
+
+func TestServerExists(t *testing.T) {
+	var methods []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.URL.Path {
+		case "/v0/servers/known-id":
+			w.WriteHeader(http.StatusOK)
+		case "/v0/servers/broken-id":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+
+	if exists, err := client.ServerExists("known-id"); err != nil || !exists {
+		t.Errorf("ServerExists(known-id) = %v, %v; want true, nil", exists, err)
+	}
+	if exists, err := client.ServerExists("missing-id"); err != nil || exists {
+		t.Errorf("ServerExists(missing-id) = %v, %v; want false, nil", exists, err)
+	}
+	if _, err := client.ServerExists("broken-id"); err == nil {
+		t.Error("Expected an error for a 500 response")
+	}
+	for _, method := range methods {
+		if method != "HEAD" {
+			t.Errorf("Expected only HEAD requests, got %s", method)
+		}
+	}
+}
+
+func TestPublishFieldErrors(t *testing.T) {
+	body := []byte(`{"title":"Unprocessable Entity","status":422,"errors":[` +
+		`{"message":"expected length >= 1","location":"body.description"},` +
+		`{"message":"required header parameter is missing","location":"header.Authorization"}]}`)
+	got := publishFieldErrors(body)
+	if len(got) != 1 || got[0] != "description: expected length >= 1" {
+		t.Errorf("Expected only the body field error, got %v", got)
+	}
+	if got := publishFieldErrors([]byte("not json")); got != nil {
+		t.Errorf("Expected no field errors for a non-JSON body, got %v", got)
+	}
+}
+
+func TestEditFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.json")
+	if err := os.WriteFile(path, []byte(`{"version":"bad"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "sed -i s/bad/1.0.0/")
+
+	if err := editFile(path); err != nil {
+		t.Fatalf("editFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != `{"version":"1.0.0"}` {
+		t.Errorf("Expected the editor to modify the file, got %s", data)
+	}
+}
+
+func TestPublishDirectory(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "io.test/bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"detail":"rejected"}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"message":"Server published successfully","id":"dir-id"}`)
+	}))
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json":       `{"name":"io.test/a","description":"A","version":"1.0.0"}`,
+		"notes.json":   `{"todo":"not a manifest"}`,
+		"readme.txt":   `ignored`,
+		"sub/b.json":   `{"name":"io.test/b","description":"B","version":"1.0.0"}`,
+		"sub/bad.json": `{"name":"io.test/bad","description":"Bad","version":"1.0.0"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := NewMCPXClient(mockServer.URL)
+	client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+	output := captureStdout(t, func() {
+		if err := client.PublishDirectory(dir, false, "test-token", PublishOptions{}, ""); err != nil {
+			t.Errorf("Expected the top-level directory to publish, got %v", err)
+		}
+	})
+	if !strings.Contains(output, "Published: 1\nFailed: 0\nSkipped: 1") {
+		t.Errorf("Unexpected non-recursive summary:\n%s", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := client.PublishDirectory(dir, true, "test-token", PublishOptions{}, ""); err == nil {
+			t.Error("Expected an error when a manifest fails to publish")
+		}
+	})
+	if !strings.Contains(output, "Published: 2\nFailed: 1\nSkipped: 1") || !strings.Contains(output, "bad.json") {
+		t.Errorf("Unexpected recursive summary:\n%s", output)
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := func(servers []Server) string {
+		var result []string
+		for _, s := range servers {
+			result = append(result, s.Version)
+		}
+		return strings.Join(result, " ")
+	}
+	withDate := func(version, date string) Server {
+		return Server{Version: version, Meta: &ServerMeta{Official: &RegistryExtensions{PublishedAt: date}}}
+	}
+
+	shuffled := []Server{
+		{Version: "1.0.0-alpha.1"}, {Version: "2.0.0"}, {Version: "1.0.0"}, {Version: "1.0.0-beta"},
+		{Version: "1.10.0"}, {Version: "1.0.0-alpha"}, {Version: "1.2.0"}, {Version: "1.0.0-alpha.beta"},
+	}
+	want := "1.0.0-alpha 1.0.0-alpha.1 1.0.0-alpha.beta 1.0.0-beta 1.0.0 1.2.0 1.10.0 2.0.0"
+
+	asc := append([]Server(nil), shuffled...)
+	sortVersions(asc, VersionOrderAsc)
+	if got := versions(asc); got != want {
+		t.Errorf("asc = %s\nwant  %s", got, want)
+	}
+
+	desc := append([]Server(nil), shuffled...)
+	sortVersions(desc, VersionOrderDesc)
+	if got := versions(desc); !strings.HasPrefix(got, "2.0.0 1.10.0 1.2.0 1.0.0 ") || !strings.HasSuffix(got, " 1.0.0-alpha") {
+		t.Errorf("desc = %s", got)
+	}
+
+	// Calendar versions are not semver, so the publish date decides
+	calendar := []Server{
+		withDate("2024.10", "2024-10-01T00:00:00Z"),
+		withDate("2024.9", "2024-09-01T00:00:00Z"),
+		withDate("2024.11", "2024-11-01T00:00:00+00:00"),
+	}
+	sortVersions(calendar, VersionOrderDesc)
+	if got := versions(calendar); got != "2024.11 2024.10 2024.9" {
+		t.Errorf("date fallback = %s", got)
+	}
+}
+
+func TestListServerVersionsLatestNote(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"servers":[`+
+			`{"server":{"name":"io.test/server","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"isLatest":true}}},`+
+			`{"server":{"name":"io.test/server","version":"2.0.0-rc.1"}}]}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	output, err := captureStdoutErr(t, func() error {
+		return client.ListServerVersions("io.test/server", ListServersOptions{Order: VersionOrderDesc})
+	})
+	if err != nil {
+		t.Fatalf("ListServerVersions failed: %v", err)
+	}
+	if !strings.Contains(output, "  2.0.0-rc.1\n  1.0.0 [latest]\n") {
+		t.Errorf("Expected versions in descending order, got:\n%s", output)
+	}
+	if !strings.Contains(output, "marks 1.0.0 as latest, but 2.0.0-rc.1 sorts higher") {
+		t.Errorf("Expected a note about the latest version, got:\n%s", output)
+	}
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	transport := newHTTPTransport()
+	if !transport.ForceAttemptHTTP2 || transport.DisableKeepAlives {
+		t.Error("Expected HTTP/2 and keep-alives to be enabled")
+	}
+	if transport.MaxIdleConnsPerHost != defaultMaxIdleConnsPerHost {
+		t.Errorf("MaxIdleConnsPerHost = %d, want %d", transport.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost)
+	}
+	if transport.Proxy == nil {
+		t.Error("Expected proxy settings from the environment to be honoured")
+	}
+}
+
+// BenchmarkSequentialRequests compares the client's pooled transport with dialing a new connection per
+// request, which is what sequential commands like servers --detailed would pay without connection reuse.
+// Run with: go test -bench SequentialRequests -run '^$'
+func BenchmarkSequentialRequests(b *testing.B) {
+	mockServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer mockServer.Close()
+
+	for _, tc := range []struct {
+		name      string
+		keepAlive bool
+	}{{"reused connections", true}, {"new connection per request", false}} {
+		b.Run(tc.name, func(b *testing.B) {
+			client := NewMCPXClient(mockServer.URL)
+			transport := newHTTPTransport()
+			transport.TLSClientConfig = mockServer.Client().Transport.(*http.Transport).TLSClientConfig
+			transport.DisableKeepAlives = !tc.keepAlive
+			client.httpClient.Transport = transport
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				resp, err := client.makeRequest("GET", "/v0/health", nil, "none")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := client.readResponseBody(resp); err != nil {
+					b.Fatal(err)
+				}
+				_ = resp.Body.Close()
+			}
+		})
+	}
+}
+
+func TestJSONErrorOutput(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, `{"title":"Bad Request","status":400,"detail":"nope"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	serverFile := createTempServerFile(t, exampleServerNPMJSON)
+	defer func() { _ = os.Remove(serverFile) }()
+
+	commands := map[string]func() error{
+		"servers":  func() error { return client.ListServers(ListServersOptions{JSON: true}) },
+		"search":   func() error { return client.SearchServers("x", ListServersOptions{JSON: true}) },
+		"versions": func() error { return client.ListServerVersions("io.test/server", ListServersOptions{JSON: true}) },
+		"server":   func() error { return client.GetServer("io.test/server", GetServerOptions{JSON: true}) },
+		"update": func() error {
+			return client.UpdateServer("io.test/server", serverFile, "test-token", UpdateOptions{JSON: true})
+		},
+		"publish": func() error { return client.PublishServer(serverFile, "test-token", PublishOptions{JSON: true}) },
+	}
+
+	for name, run := range commands {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			output := captureStdout(t, func() { err = run() })
+			if !errors.Is(err, errJSONErrorWritten) {
+				t.Fatalf("Expected errJSONErrorWritten so the command exits non-zero, got %v", err)
+			}
+
+			var got struct {
+				Error  map[string]interface{} `json:"error"`
+				Status int                    `json:"status"`
+			}
+			if jsonErr := json.Unmarshal([]byte(output), &got); jsonErr != nil {
+				t.Fatalf("Expected a single JSON document, got %q", output)
+			}
+			if got.Status != 400 || got.Error["detail"] != "nope" {
+				t.Errorf("Unexpected error document %+v", got)
+			}
+		})
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		err := client.DeleteServer("io.test/server", "1.0.0", "test-token", true)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+			t.Fatalf("Expected an APIError with status 400, got %v", err)
+		}
+		doc := newJSONError(apiErr.StatusCode, apiErr.Body)
+		if problem, ok := doc.Error.(map[string]interface{}); !ok || problem["title"] != "Bad Request" {
+			t.Errorf("Expected the problem document as error, got %+v", doc.Error)
+		}
+	})
+
+	t.Run("plain text body", func(t *testing.T) {
+		doc := newJSONError(502, []byte("Bad Gateway\n"))
+		if problem, ok := doc.Error.(map[string]interface{}); !ok || problem["message"] != "Bad Gateway" {
+			t.Errorf("Expected a message for a non-JSON body, got %+v", doc.Error)
+		}
+	})
+}
+
+func TestTextErrorOutput(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, `{"detail":"nope"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	serverFile := createTempServerFile(t, exampleServerNPMJSON)
+	defer func() { _ = os.Remove(serverFile) }()
+
+	// Without --json the same failures must exit non-zero, so they come back as an APIError for main to report
+	commands := map[string]func() error{
+		"servers":  func() error { return client.ListServers(ListServersOptions{}) },
+		"search":   func() error { return client.SearchServers("x", ListServersOptions{}) },
+		"versions": func() error { return client.ListServerVersions("io.test/server", ListServersOptions{}) },
+		"server":   func() error { return client.GetServer("io.test/server", GetServerOptions{}) },
+		"update": func() error {
+			return client.UpdateServer("io.test/server", serverFile, "test-token", UpdateOptions{})
+		},
+	}
+
+	for name, run := range commands {
+		t.Run(name, func(t *testing.T) {
+			_, err := captureStdoutErr(t, run)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 || !strings.Contains(string(apiErr.Body), "nope") {
+				t.Errorf("Expected an APIError with status 400, got %v", err)
+			}
+		})
+	}
+}
+func TestListServersHeadTail(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{{"alpha", "beta"}, {"gamma", "delta"}})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	tests := []struct {
+		name string
+		opts ListServersOptions
+		want string
+	}{
+		{"head across pages", ListServersOptions{All: true, IDOnly: true, Head: 3}, "id-alpha\nid-beta\nid-gamma\n"},
+		{"tail across pages", ListServersOptions{All: true, IDOnly: true, Tail: 1}, "id-delta\n"},
+		{"tail of filtered list", ListServersOptions{All: true, IDOnly: true, Filter: "a", Tail: 2}, "id-gamma\nid-delta\n"},
+		{"head larger than list", ListServersOptions{IDOnly: true, Head: 10}, "id-alpha\nid-beta\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := captureStdoutErr(t, func() error { return client.ListServers(tt.opts) })
+			if err != nil {
+				t.Fatalf("ListServers failed: %v", err)
+			}
+			if output != tt.want {
+				t.Errorf("got %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadManifestSniffsFormat(t *testing.T) {
+	dir := t.TempDir()
+	var logs bytes.Buffer
+	client := NewMCPXClient("http://unused")
+	client.logger = NewLogger(&logs, LogFormatText, true)
+
+	// JSON content is accepted regardless of the file extension
+	jsonFile := filepath.Join(dir, "server.yaml")
+	if err := os.WriteFile(jsonFile, []byte(`{"name":"io.test/server","version":"1.0.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := client.readManifest(jsonFile)
+	if err != nil {
+		t.Fatalf("readManifest failed: %v", err)
+	}
+	if !strings.Contains(string(data), "io.test/server") {
+		t.Errorf("unexpected data: %s", data)
+	}
+	if !strings.Contains(logs.String(), "format=json") {
+		t.Errorf("expected detected format in verbose log, got %q", logs.String())
+	}
+
+	// Content that is not JSON reports the JSON error alone
+	yamlFile := filepath.Join(dir, "server.json")
+	if err := os.WriteFile(yamlFile, []byte("name: io.test/server\nversion: 1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.readManifest(yamlFile)
+	if err == nil {
+		t.Fatal("expected error for unparseable manifest")
+	}
+	if !strings.Contains(err.Error(), "invalid JSON in server file") || strings.Contains(err.Error(), "YAML") {
+		t.Errorf("expected only the JSON error, got %v", err)
+	}
+}
+
+func TestRequestAttemptTimeoutAndDeadline(t *testing.T) {
+	var calls int32
+	hang := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 || r.URL.Path == "/hang" {
+			// Hang until the client gives up on this attempt
+			select {
+			case <-r.Context().Done():
+			case <-hang:
+			}
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer mockServer.Close()
+	defer close(hang)
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.httpClient.Timeout = 0
+	client.attemptTimeout = 100 * time.Millisecond
+	client.retries = 1
+	client.retryBackoff = time.Millisecond
+	client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+	t.Run("hanging attempt is retried", func(t *testing.T) {
+		resp, err := client.makeRequest("GET", "/v0/health", nil, "none")
+		if err != nil {
+			t.Fatalf("Expected the second attempt to succeed, got %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil || !strings.Contains(string(body), "ok") {
+			t.Fatalf("Expected to read the body after the attempt returned, got %q, %v", body, err)
+		}
+		if got := atomic.LoadInt32(&calls); got != 2 {
+			t.Errorf("Expected 2 attempts, got %d", got)
+		}
+	})
+
+	t.Run("deadline caps all attempts", func(t *testing.T) {
+		client.retries = 10
+		client.retryBackoff = 50 * time.Millisecond
+		client.deadline = time.Now().Add(250 * time.Millisecond)
+		start := time.Now()
+		_, err := client.makeRequest("GET", "/hang", nil, "none")
+		if err == nil {
+			t.Fatal("Expected the deadline to be exceeded")
+		}
+		if classifyNetworkError(err) != NetworkErrorTimeout {
+			t.Errorf("Expected a timeout error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the deadline to stop retries, took %v", elapsed)
+		}
+	})
+}
+
+func TestSetServerStatus(t *testing.T) {
+	var putQuery url.Values
+	var putBody ServerDetail
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if !strings.HasSuffix(r.URL.Path, "/versions/latest") {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"server":{"name":"io.test/server","description":"d","version":"1.2.0","packages":[{"registryType":"npm","identifier":"x","version":"1.2.0"}]},
+				"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"sid","versionId":"vid","status":"active"}}}`)
+		case "PUT":
+			putQuery = r.URL.Query()
+			_ = json.NewDecoder(r.Body).Decode(&putBody)
+			_, _ = fmt.Fprint(w, `{"message":"updated"}`)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output, err := captureStdoutErr(t, func() error {
+		return client.SetServerStatus("io.test/server", "", ServerStatusDeprecated, "use io.test/next", "test-token", false)
+	})
+	if err != nil {
+		t.Fatalf("SetServerStatus failed: %v", err)
+	}
+	if !strings.Contains(output, "now deprecated") {
+		t.Errorf("Expected confirmation, got %q", output)
+	}
+	if putQuery.Get("status") != "deprecated" || putQuery.Get("reason") != "use io.test/next" {
+		t.Errorf("Unexpected query %v", putQuery)
+	}
+	if putBody.Status != "deprecated" || putBody.Version != "1.2.0" || len(putBody.Packages) != 1 {
+		t.Errorf("Expected the fetched manifest with the new status, got %+v", putBody)
+	}
+	if putBody.Meta != nil || putBody.ID != "" {
+		t.Errorf("Expected registry-managed fields to be cleared, got id=%q meta=%v", putBody.ID, putBody.Meta)
+	}
+
+	var apiErr *APIError
+	err = client.SetServerStatus("io.test/server", "9.9.9", ServerStatusDeprecated, "gone", "test-token", true)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a not found APIError for an unknown version, got %v", err)
+	}
+}
+
+func TestUpdateServerPatch(t *testing.T) {
+	t.Run("merge patch", func(t *testing.T) {
+		var method, contentType, patch string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, contentType = r.Method, r.Header.Get("Content-Type")
+			body, _ := io.ReadAll(r.Body)
+			patch = string(body)
+			_, _ = fmt.Fprint(w, `{"message":"updated"}`)
+		}))
+		defer mockServer.Close()
+
+		client := NewMCPXClient(mockServer.URL)
+		client.cacheDir = ""
+		opts := UpdateOptions{Patch: true, Version: "1.0.0", Overrides: []fieldOverride{{Field: "status", Value: "deprecated"}, {Field: "repository.url", Value: "https://github.com/test/next"}}}
+		output, err := captureStdoutErr(t, func() error { return client.UpdateServer("io.test/server", "", "test-token", opts) })
+		if err != nil {
+			t.Fatalf("UpdateServer --patch failed: %v", err)
+		}
+		if method != "PATCH" || contentType != mergePatchContentType {
+			t.Errorf("Expected a PATCH with %s, got %s with %q", mergePatchContentType, method, contentType)
+		}
+		if patch != `{"repository":{"url":"https://github.com/test/next"},"status":"deprecated"}` {
+			t.Errorf("Expected only the set fields in the patch, got %s", patch)
+		}
+		if !strings.Contains(output, "✅ Server version 'io.test/server/1.0.0' updated: status, repository.url") {
+			t.Errorf("Expected confirmation, got:\n%s", output)
+		}
+	})
+
+	t.Run("fallback on 405", func(t *testing.T) {
+		var putQuery url.Values
+		var putBody ServerDetail
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "PATCH":
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			case "GET":
+				_, _ = fmt.Fprint(w, `{"server":{"name":"io.test/server","description":"d","version":"1.2.0","packages":[{"registryType":"npm","identifier":"x","version":"1.2.0"}]},
+					"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"sid","versionId":"vid","status":"active"}}}`)
+			case "PUT":
+				putQuery = r.URL.Query()
+				_ = json.NewDecoder(r.Body).Decode(&putBody)
+				_, _ = fmt.Fprint(w, `{"message":"updated"}`)
+			}
+		}))
+		defer mockServer.Close()
+
+		client := NewMCPXClient(mockServer.URL)
+		client.cacheDir = ""
+		opts := UpdateOptions{Patch: true, Overrides: []fieldOverride{{Field: "status", Value: "deprecated"}}}
+		output, err := captureStdoutErr(t, func() error { return client.UpdateServer("io.test/server", "", "test-token", opts) })
+		if err != nil {
+			t.Fatalf("UpdateServer --patch failed: %v", err)
+		}
+		if !strings.Contains(output, "does not support PATCH (405)") || !strings.Contains(output, "'io.test/server/1.2.0' updated") {
+			t.Errorf("Expected a fallback note and confirmation, got:\n%s", output)
+		}
+		if putBody.Status != "deprecated" || len(putBody.Packages) != 1 || putBody.Meta != nil {
+			t.Errorf("Expected the fetched manifest with the new status, got %+v", putBody)
+		}
+		if putQuery.Get("status") != "deprecated" {
+			t.Errorf("Expected the status as a query parameter, got %v", putQuery)
+		}
+	})
+
+	t.Run("rejected patches", func(t *testing.T) {
+		client := NewMCPXClient("http://localhost:1")
+		for _, overrides := range [][]fieldOverride{nil, {{Field: "packages.0.version", Value: "2.0.0"}}} {
+			captureStdout(t, func() {
+				if err := client.UpdateServer("io.test/server", "", "test-token", UpdateOptions{Patch: true, Overrides: overrides}); err == nil {
+					t.Errorf("Expected an error for overrides %v", overrides)
+				}
+			})
+		}
+	})
+}
+
+func TestRestoreServer(t *testing.T) {
+	var putStatus string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/versions/1.0.0"):
+			_, _ = fmt.Fprint(w, `{"name":"io.test/server","description":"d","version":"1.0.0","status":"deleted"}`)
+		case r.Method == "PUT":
+			putStatus = r.URL.Query().Get("status")
+			_, _ = fmt.Fprint(w, `{"message":"updated"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	if _, err := captureStdoutErr(t, func() error { return client.RestoreServer("io.test/server", "1.0.0", "test-token", false) }); err != nil {
+		t.Fatalf("RestoreServer failed: %v", err)
+	}
+	if putStatus != ServerStatusActive {
+		t.Errorf("Expected status to be set to active, got %q", putStatus)
+	}
+
+	err := client.RestoreServer("io.test/server", "2.0.0", "test-token", true)
+	if err == nil || !strings.Contains(err.Error(), "hard-delete") {
+		t.Errorf("Expected a hard delete error, got %v", err)
+	}
+}
+
+func TestDisableCompression(t *testing.T) {
+	var acceptEncoding string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	get := func() {
+		resp, err := client.makeRequest("GET", "/v0/health", nil, "none")
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	get()
+	if acceptEncoding != "gzip" {
+		t.Errorf("Expected gzip to be negotiated by default, got %q", acceptEncoding)
+	}
+
+	client.disableCompression()
+	get()
+	if acceptEncoding != "" {
+		t.Errorf("Expected no Accept-Encoding with compression disabled, got %q", acceptEncoding)
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv("PAGER", "more -s")
+	if cmd := pagerCommand(); strings.Join(cmd.Args, " ") != "more -s" {
+		t.Errorf("Expected $PAGER to be split into arguments, got %v", cmd.Args)
+	}
+
+	t.Setenv("PAGER", "")
+	t.Setenv("LESS", "")
+	cmd := pagerCommand()
+	if cmd.Args[0] != "less" {
+		t.Errorf("Expected less as the default pager, got %v", cmd.Args)
+	}
+	if !strings.Contains(strings.Join(cmd.Env, "\n"), "LESS=FRX") {
+		t.Errorf("Expected LESS=FRX for the default pager")
+	}
+
+	// Output that is not a terminal is never paged
+	stdout := os.Stdout
+	stop, err := startPager()
+	if err != nil {
+		t.Fatalf("startPager failed: %v", err)
+	}
+	stop()
+	if os.Stdout != stdout {
+		t.Errorf("Expected stdout to be left alone when it is not a terminal")
+	}
+}
+
+func TestListServersCount(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{{"alpha", "beta"}, {"gamma", "delta"}, {"epsilon"}})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output, err := captureStdoutErr(t, func() error { return client.ListServers(ListServersOptions{Count: 3, IDOnly: true}) })
+	if err != nil {
+		t.Fatalf("ListServers failed: %v", err)
+	}
+	if output != "id-alpha\nid-beta\nid-gamma\n" {
+		t.Errorf("Expected the first 3 servers across pages, got %q", output)
+	}
+
+	// --limit is kept as an alias of --page-size
+	var opts ListServersOptions
+	fs := flag.NewFlagSet("servers", flag.ContinueOnError)
+	addListFlags(fs, &opts)
+	if err := fs.Parse([]string{"--limit", "7", "--count", "20"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if opts.Limit != 7 || opts.Count != 20 {
+		t.Errorf("Expected page size 7 and count 20, got %d and %d", opts.Limit, opts.Count)
+	}
+}
+
+func TestTokenCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	client := NewMCPXClient("http://unused")
+
+	if err := client.runTokenCommand([]string{"print"}); err == nil {
+		t.Fatal("Expected an error without a stored token")
+	}
+
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	if err := client.saveAuthConfig(AuthConfig{Token: "secret-token", Method: AuthMethodAnonymous, ExpiresAt: expiresAt}); err != nil {
+		t.Fatalf("saveAuthConfig failed: %v", err)
+	}
+
+	output, err := captureStdoutErr(t, func() error { return client.runTokenCommand([]string{"print"}) })
+	if err != nil {
+		t.Fatalf("token print failed: %v", err)
+	}
+	if output != "secret-token\n" {
+		t.Errorf("Expected only the token on stdout, got %q", output)
+	}
+
+	output, err = captureStdoutErr(t, func() error { return client.runTokenCommand([]string{"expiry"}) })
+	if err != nil {
+		t.Fatalf("token expiry failed: %v", err)
+	}
+	if want := time.Unix(expiresAt, 0).UTC().Format(time.RFC3339) + "\n"; output != want {
+		t.Errorf("Expected %q, got %q", want, output)
+	}
+}
+
+func TestCheckPackageURLs(t *testing.T) {
+	downloads := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("Expected HEAD, got %s", r.Method)
+		}
+		if r.URL.Path != "/ok.whl" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer downloads.Close()
+
+	client := NewMCPXClient("http://unused")
+	server := ServerDetail{Packages: []Package{
+		{RegistryType: "wheel", WheelURL: downloads.URL + "/ok.whl"},
+		{RegistryType: "binary", BinaryURL: downloads.URL + "/missing"},
+		{RegistryType: "npm"},
+	}}
+
+	var problems []string
+	output := captureStdout(t, func() {
+		problems = client.checkPackageURLs(server)
+	})
+	if !strings.Contains(output, "packages[0].wheelUrl") {
+		t.Errorf("Expected the reachable URL to be reported, got %q", output)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], "packages[1].binaryUrl") || !strings.Contains(problems[0], "404") {
+		t.Errorf("Expected one 404 problem for the binary URL, got %v", problems)
+	}
+}
+
+func TestPrintCommandUsage(t *testing.T) {
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("publish", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	fs.Bool("dry-run", false, "Show what would be published")
+	printCommandUsage(fs)
+
+	output := buf.String()
+	for _, want := range []string{"Usage: mcpx-cli [global flags] publish <server.json>", "Flags:", "-dry-run", "Show what would be published", "Examples:", "mcpx-cli publish --interactive"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected usage to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// A command without a help entry or flags still gets a usage line
+	buf.Reset()
+	fs = flag.NewFlagSet("unknown", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	printCommandUsage(fs)
+	if !strings.HasPrefix(buf.String(), "Usage: mcpx-cli [global flags] unknown [flags]") || strings.Contains(buf.String(), "Flags:") {
+		t.Errorf("Unexpected usage for a command without flags: %q", buf.String())
+	}
+}
+
+func TestListServersClientLimit(t *testing.T) {
+	// The mock ignores the limit parameter, like a non-compliant registry
+	mockServer := createPaginatedMockServer(t, [][]string{{"alpha", "beta", "gamma", "delta"}})
+	defer mockServer.Close()
+
+	var logs bytes.Buffer
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.logger = NewLogger(&logs, LogFormatText, false)
+
+	output, err := captureStdoutErr(t, func() error { return client.ListServers(ListServersOptions{Limit: 2, IDOnly: true}) })
+	if err != nil {
+		t.Fatalf("ListServers failed: %v", err)
+	}
+	if output != "id-alpha\nid-beta\n" {
+		t.Errorf("Expected the page to be truncated to 2 servers, got %q", output)
+	}
+	if !strings.Contains(logs.String(), "--no-client-limit") {
+		t.Errorf("Expected a note about the truncation, got %q", logs.String())
+	}
+
+	output, err = captureStdoutErr(t, func() error {
+		return client.ListServers(ListServersOptions{Limit: 2, IDOnly: true, NoClientLimit: true})
+	})
+	if err != nil {
+		t.Fatalf("ListServers failed: %v", err)
+	}
+	if strings.Count(output, "\n") != 4 {
+		t.Errorf("Expected all 4 servers with NoClientLimit, got %q", output)
+	}
+
+	t.Run("cursor dropped", func(t *testing.T) {
+		// The cursor of an oversized page points past all of it, so keeping it would skip gamma and delta
+		mockServer := createPaginatedMockServer(t, [][]string{{"alpha", "beta", "gamma", "delta"}, {"epsilon"}})
+		defer mockServer.Close()
+		client := NewMCPXClient(mockServer.URL)
+		client.cacheDir = ""
+		client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+		servers, metadata, _, _, err := client.fetchServerList("/v0/servers", ListServersOptions{Limit: 2})
+		if err != nil {
+			t.Fatalf("fetchServerList failed: %v", err)
+		}
+		if len(servers) != 2 || metadata.NextCursor != "" {
+			t.Errorf("Expected 2 servers and no next cursor, got %d servers and cursor %q", len(servers), metadata.NextCursor)
+		}
+
+		_, _, _, _, err = client.fetchServerList("/v0/servers", ListServersOptions{Limit: 2, SaveCursor: true})
+		if err == nil || !strings.Contains(err.Error(), "--no-client-limit") {
+			t.Errorf("Expected --save-cursor to fail on a truncated page, got %v", err)
+		}
+	})
+}
+
+func TestOpenServerRepository(t *testing.T) {
+	mockServer := createMockServer()
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output, err := captureStdoutErr(t, func() error { return client.OpenServerRepository("io.test/server1", true) })
+	if err != nil {
+		t.Fatalf("OpenServerRepository failed: %v", err)
+	}
+	if output != "https://github.com/test/server\n" {
+		t.Errorf("Expected only the repository URL, got %q", output)
+	}
+
+	noRepo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"name":"io.test/norepo","version":"1.0.0"}`)
+	}))
+	defer noRepo.Close()
+	client = NewMCPXClient(noRepo.URL)
+	client.cacheDir = ""
+	if err := client.OpenServerRepository("io.test/norepo", true); err == nil || !strings.Contains(err.Error(), "no repository URL") {
+		t.Errorf("Expected a missing repository error, got %v", err)
+	}
+}
+
+func TestBrowserCommand(t *testing.T) {
+	tests := map[string]string{
+		"linux":   "xdg-open https://example.com",
+		"darwin":  "open https://example.com",
+		"windows": "rundll32 url.dll,FileProtocolHandler https://example.com",
+	}
+	for goos, want := range tests {
+		cmd, err := browserCommand(goos, "https://example.com")
+		if err != nil {
+			t.Fatalf("browserCommand(%s) failed: %v", goos, err)
+		}
+		if got := strings.Join(cmd.Args, " "); got != want {
+			t.Errorf("browserCommand(%s) = %q, want %q", goos, got, want)
+		}
+	}
+
+	for _, target := range []string{"file:///etc/passwd", "calc.exe", "javascript:alert(1)", "https://", "\\\\host\\share"} {
+		if _, err := browserCommand("windows", target); err == nil {
+			t.Errorf("Expected %q to be refused", target)
+		}
+	}
+}
+
+func TestRedirectLoop(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v0/health":
+			http.Redirect(w, r, "/bounce", http.StatusFound)
+		case "/bounce":
+			http.Redirect(w, r, "/v0/health", http.StatusFound)
+		default:
+			// Every hop goes somewhere new, so only the redirect cap stops it
+			var n int
+			_, _ = fmt.Sscanf(r.URL.Path, "/hop/%d", &n)
+			http.Redirect(w, r, fmt.Sprintf("/hop/%d", n+1), http.StatusFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	_, err := client.makeRequest("GET", "/v0/health", nil, "none")
+	var loopErr *RedirectLoopError
+	if !errors.As(err, &loopErr) {
+		t.Fatalf("Expected a RedirectLoopError, got %v", err)
+	}
+	if len(loopErr.Chain) != 3 || !strings.Contains(err.Error(), "/bounce") {
+		t.Errorf("Expected the loop to be reported as soon as a URL repeats, got %v", err)
+	}
+
+	_, err = client.makeRequest("GET", "/hop/0", nil, "none")
+	if !errors.As(err, &loopErr) || len(loopErr.Chain) != maxRedirects+1 {
+		t.Errorf("Expected the redirect cap to stop the chain, got %v", err)
+	}
+
+	httpsBounce := &RedirectLoopError{Chain: []string{"http://registry.example.com/v0/servers", "https://registry.example.com/v0/servers", "http://registry.example.com/v0/servers"}}
+	if !strings.Contains(httpsBounce.Error(), "try --base-url https://registry.example.com") {
+		t.Errorf("Expected an https hint, got %q", httpsBounce.Error())
+	}
+}
+
+func TestDefaultAuthMethod(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{env: "", want: AuthMethodAnonymous},
+		{env: "github-oidc", want: AuthMethodGitHubOIDC},
+		{env: " github-oauth ", want: AuthMethodGitHubOAuth},
+		{env: "password", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Setenv(authMethodEnvVar, tt.env)
+		got, err := defaultAuthMethod()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s=%q: got %q, %v; want %q", authMethodEnvVar, tt.env, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckCredentials(t *testing.T) {
+	const protected = "/v0/protected"
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != protected {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Header.Get("Authorization") {
+		case "Bearer good":
+			_, _ = fmt.Fprint(w, `{}`)
+		case "Bearer limited":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"detail":"invalid token"}`)
+		}
+	}))
+	defer mockServer.Close()
+
+	tests := []struct {
+		name     string
+		token    string
+		stored   string
+		endpoint string
+		wantErr  string
+		want     string
+	}{
+		{"accepted token", "good", "", protected, "", "✅ The registry accepted the credentials"},
+		{"accepted stored login", "", "good", protected, "", "Credentials: stored github-oauth login"},
+		{"rejected stored login", "", "revoked", protected, "status 401", "❌ The registry rejected the credentials"},
+		{"forbidden", "limited", "", protected, "status 403", "do not grant access"},
+		{"unknown endpoint", "good", "", "/v0/missing", "status 404", "Inconclusive"},
+		{"no credentials", "", "", protected, "no credentials to check", "=== Credential Check ==="},
+		{"no endpoint", "good", "", "", "--endpoint is required", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			client := NewMCPXClient(mockServer.URL)
+			client.cacheDir = ""
+			if tt.stored != "" {
+				if err := client.saveAuthConfig(AuthConfig{Method: AuthMethodGitHubOAuth, Token: tt.stored}); err != nil {
+					t.Fatalf("Failed to save auth config: %v", err)
+				}
+			}
+			var err error
+			output := captureStdout(t, func() {
+				err = client.CheckCredentials(tt.token, tt.endpoint)
+			})
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestHealthExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   int
+	}{
+		{"healthy", http.StatusOK, `{"status":"ok"}`, 0},
+		{"degraded", http.StatusOK, `{"status":"degraded"}`, exitCodeUnhealthy},
+		{"server error", http.StatusServiceUnavailable, `{"status":"ok"}`, exitCodeUnhealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+			defer mockServer.Close()
+
+			client := NewMCPXClient(mockServer.URL)
+			client.cacheDir = ""
+			var code int
+			output := captureStdout(t, func() {
+				code = client.HealthExitCode()
+			})
+			if code != tt.want {
+				t.Errorf("HealthExitCode() = %d, want %d", code, tt.want)
+			}
+			if output != "" {
+				t.Errorf("Expected no output, got %q", output)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to reserve a port: %v", err)
+		}
+		addr := listener.Addr().String()
+		_ = listener.Close()
+
+		client := NewMCPXClient("http://" + addr)
+		client.cacheDir = ""
+		if code := client.HealthExitCode(); code != exitCodeUnreachable {
+			t.Errorf("HealthExitCode() = %d, want %d", code, exitCodeUnreachable)
+		}
+	})
+}
+
+func TestListServersSaveCursorResume(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	mockServer := createPaginatedMockServer(t, [][]string{{"alpha"}, {"beta"}, {"gamma"}})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+	list := func(opts ListServersOptions) string {
+		t.Helper()
+		output, err := captureStdoutErr(t, func() error { return client.ListServers(opts) })
+		if err != nil {
+			t.Fatalf("ListServers failed: %v", err)
+		}
+		return output
+	}
+
+	if got := list(ListServersOptions{IDOnly: true, SaveCursor: true}); got != "id-alpha\n" {
+		t.Fatalf("Unexpected first page %q", got)
+	}
+	for _, want := range []string{"id-beta\n", "id-gamma\n"} {
+		if got := list(ListServersOptions{IDOnly: true, Resume: true, SaveCursor: true}); got != want {
+			t.Errorf("Expected %q when resuming, got %q", want, got)
+		}
+	}
+
+	// The last page clears the cursor
+	if err := client.ListServers(ListServersOptions{IDOnly: true, Resume: true}); err == nil {
+		t.Error("Expected an error when resuming after the last page")
+	}
+
+	// Cursors are kept per registry
+	if err := client.saveCursor("page-1"); err != nil {
+		t.Fatalf("saveCursor failed: %v", err)
+	}
+	other := NewMCPXClient("http://other.example")
+	if _, err := other.savedCursor(); err == nil {
+		t.Error("Expected no saved cursor for a different registry")
+	}
+}
+
+func TestCheckCursor(t *testing.T) {
+	client := NewMCPXClient("https://registry.example.com")
+	tests := []struct {
+		cursor  string
+		want    string
+		wantErr bool
+	}{
+		{cursor: "", want: ""},
+		{cursor: "io.test/server:1.0.0", want: "io.test/server:1.0.0"},
+		{cursor: "eyJvZmZzZXQiOjEwfQ==", want: "eyJvZmZzZXQiOjEwfQ=="},
+		{cursor: "has space", wantErr: true},
+		{cursor: "tab\there", wantErr: true},
+		{cursor: strings.Repeat("a", maxCursorLength+1), wantErr: true},
+		{cursor: "https://registry.example.com/v0/servers?cursor=abc&limit=5", want: "abc"},
+		{cursor: "https://other.example.com/v0/servers?cursor=abc", wantErr: true},
+		{cursor: "https://registry.example.com/v0/servers", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := client.checkCursor(tt.cursor)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("checkCursor(%.40q) = %q, %v; want %q, wantErr %v", tt.cursor, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestListServersInvalidCursor(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{{"alpha"}})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	for _, opts := range []ListServersOptions{{Cursor: "bogus"}, {Cursor: "bogus", All: true}} {
+		var err error
+		captureStdout(t, func() {
+			err = client.ListServers(opts)
+		})
+		if !errors.Is(err, errInvalidCursor) {
+			t.Errorf("Expected an invalid cursor error for %+v, got %v", opts, err)
+		}
+	}
+}
+
+func TestDescribeServer(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/versions") {
+			_, _ = fmt.Fprint(w, `{"servers":[`+
+				`{"server":{"name":"io.test/server","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"publishedAt":"2025-01-01T00:00:00Z"}}},`+
+				`{"server":{"name":"io.test/server","version":"1.1.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"isLatest":true}}}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"name":"io.test/server","version":"1.1.0","packages":[{"registryType":"npm","identifier":"@test/server","version":"1.1.0"}]}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output, err := captureStdoutErr(t, func() error { return client.DescribeServer("io.test/server", false, "") })
+	if err != nil {
+		t.Fatalf("DescribeServer failed: %v", err)
+	}
+	for _, want := range []string{"=== Describe Server (Name: io.test/server) ===", "npm: npx @test/server@1.1.0", "Version History (2):", "  1.1.0 [latest]\n", "  1.0.0  published 2025-01-01T00:00:00Z\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	output, err = captureStdoutErr(t, func() error { return client.DescribeServer("io.test/server", true, "") })
+	if err != nil {
+		t.Fatalf("DescribeServer --json failed: %v", err)
+	}
+	var description ServerDescription
+	if err := json.Unmarshal([]byte(output), &description); err != nil {
+		t.Fatalf("Expected a JSON document, got %v:\n%s", err, output)
+	}
+	if description.Server.Name != "io.test/server" || len(description.InstallCommands) != 1 || len(description.Versions) != 2 || description.Versions[0].Version != "1.1.0" {
+		t.Errorf("Unexpected composite object: %+v", description)
+	}
+}
+
+func TestAuthFailureHints(t *testing.T) {
+	status := http.StatusUnauthorized
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, `{"detail":"denied"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	err := client.DeleteServer("io.github.someone/server", "1.0.0", "token", true)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !strings.Contains(apiErr.Hint, "mcpx-cli login") {
+		t.Errorf("Expected a login hint for 401, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `{"detail":"denied"}`) {
+		t.Errorf("Expected the response body to be kept, got %v", err)
+	}
+
+	status = http.StatusForbidden
+	err = client.DeleteServer("io.github.someone/server", "1.0.0", "token", true)
+	if !errors.As(err, &apiErr) || !strings.Contains(apiErr.Hint, "not allowed to modify io.github.someone/server") {
+		t.Errorf("Expected an ownership hint for 403, got %v", err)
+	}
+
+	serverFile := createTempServerFile(t, []byte(`{"name":"io.github.someone/server","description":"d","version":"1.0.0"}`))
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(serverFile)
+	_, err = captureStdoutErr(t, func() error {
+		return client.UpdateServer("io.github.someone/server", serverFile, "token", UpdateOptions{})
+	})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 403 || !strings.Contains(apiErr.Hint, "authenticated, but not allowed") {
+		t.Errorf("Expected an APIError with a 403 hint from update, got %v", err)
+	}
+
+	status = http.StatusUnauthorized
+	output, err := captureStdoutErr(t, func() error { return client.PublishServer(serverFile, "token", PublishOptions{JSON: true}) })
+	if !errors.Is(err, errJSONErrorWritten) {
+		t.Fatalf("Expected errJSONErrorWritten from a failed publish, got %v", err)
+	}
+	var doc jsonError
+	if err := json.Unmarshal([]byte(output), &doc); err != nil || doc.Status != 401 || !strings.Contains(doc.Hint, "mcpx-cli login") {
+		t.Errorf("Expected a JSON error with a login hint, got %v:\n%s", err, output)
+	}
+
+	if hint := authFailureHint(http.StatusBadRequest, "io.test/server"); hint != "" {
+		t.Errorf("Expected no hint for 400, got %q", hint)
+	}
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "localhost:8080", want: "http://localhost:8080"},
+		{input: "127.0.0.1:8080/", want: "http://127.0.0.1:8080"},
+		{input: "registry.example.com", want: "https://registry.example.com"},
+		{input: "https://x/", want: "https://x"},
+		{input: "http://registry.example.com/api/", want: "http://registry.example.com/api"},
+		{input: "", wantErr: true},
+		{input: "not a url", wantErr: true},
+		{input: "ftp://registry.example.com", wantErr: true},
+		{input: "https://", wantErr: true},
+		{input: "localhost:port", wantErr: true},
+		{input: "https://x/?q=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeBaseURL(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("normalizeBaseURL(%q) = %q, expected an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeBaseURL(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestRepositoryConsistency(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  Server
+		problem string
+	}{
+		{name: "matching github repository", server: Server{Name: "io.github.alice/tool", Repository: Repository{URL: "https://github.com/alice/tool", Source: "github", ID: "alice/tool"}}},
+		{name: "owner taken from URL", server: Server{Name: "io.github.Alice/tool", Repository: Repository{URL: "https://github.com/alice/tool", Source: "github"}}},
+		{name: "other namespace on github", server: Server{Name: "io.modelcontextprotocol/tool", Repository: Repository{URL: "https://github.com/example/tool", Source: "github"}}},
+		{name: "non-github source", server: Server{Name: "io.github.alice/tool", Repository: Repository{URL: "https://gitlab.com/alice/tool", Source: "gitlab"}}, problem: `repository.source is "gitlab"`},
+		{name: "different owner", server: Server{Name: "io.github.alice/tool", Repository: Repository{URL: "https://github.com/bob/tool", Source: "github", ID: "bob/tool"}}, problem: "belongs to GitHub user alice, but the repository belongs to bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := repositoryConsistencyProblems(ServerDetail{Server: tt.server})
+			if tt.problem == "" {
+				if len(problems) != 0 {
+					t.Errorf("Expected no problems, got %v", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.problem) {
+				t.Errorf("Expected a problem containing %q, got %v", tt.problem, problems)
+			}
+		})
+	}
+
+	mockServer := createMockServer()
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	serverFile := createTempServerFile(t, []byte(`{"name":"io.github.alice/tool","description":"Test","version":"1.0.0","repository":{"url":"https://gitlab.com/alice/tool","source":"gitlab"}}`))
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(serverFile)
+
+	captureStdout(t, func() {
+		if err := client.ValidateServerFile(serverFile, ValidateOptions{ConsistencyChecks: true}); err == nil {
+			t.Error("Expected validate to report the namespace mismatch")
+		}
+		if err := client.ValidateServerFile(serverFile, ValidateOptions{}); err != nil {
+			t.Errorf("Expected validate to pass with consistency checks disabled, got %v", err)
+		}
+		_ = client.PublishServer(serverFile, "test-token", PublishOptions{})
+	})
+	if !strings.Contains(logs.String(), "--no-consistency-checks") {
+		t.Errorf("Expected a pre-flight warning, got %v", logs.String())
+	}
+
+	logs.Reset()
+	captureStdout(t, func() {
+		_ = client.PublishServer(serverFile, "test-token", PublishOptions{NoConsistencyChecks: true})
+	})
+	if strings.Contains(logs.String(), "namespace") {
+		t.Errorf("Expected no warning with NoConsistencyChecks, got %v", logs.String())
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := map[string]string{
+		"io.github.user/server": "io.github.user_server",
+		"a b:c\\d":              "a_b_c_d",
+		"../escape":             "_escape",
+		"":                      "_",
+	}
+	for input, want := range tests {
+		if got := sanitizeFileName(input); got != want {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestExportAllServers(t *testing.T) {
+	mockServer := createPaginatedMockServer(t, [][]string{{"io.test/a", "io.test/b"}, {"io.test/a@2.0.0"}})
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	output, err := captureStdoutErr(t, func() error { return client.ExportAllServers(dir, false, false) })
+	if err != nil {
+		t.Fatalf("ExportAllServers failed: %v", err)
+	}
+	if !strings.Contains(output, "✅ Exported 3 server(s)") {
+		t.Errorf("Expected a final count, got:\n%s", output)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read export directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{"io.test_a-2.0.0.json", "io.test_a.json", "io.test_b.json"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected files %v, got %v", want, names)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "io.test_b.json"))
+	if err != nil {
+		t.Fatalf("Failed to read exported manifest: %v", err)
+	}
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Exported manifest is not JSON: %v", err)
+	}
+	if manifest["name"] != "io.test/b" || manifest["_meta"] != nil {
+		t.Errorf("Expected a publishable manifest for io.test/b, got %v", manifest)
+	}
+}
+
+func TestImportDirectory(t *testing.T) {
+	var publishedNames []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.Contains(r.URL.Path, "/versions/"):
+			if strings.Contains(r.URL.Path, "existing") {
+				_, _ = fmt.Fprint(w, `{"name":"io.test/existing","version":"1.0.0"}`)
+				return
+			}
+			http.NotFound(w, r)
+		case r.Method == "POST" && r.URL.Path == "/v0/publish":
+			body, _ := io.ReadAll(r.Body)
+			server, _ := rawBodyServer(body)
+			if server.Name == "io.test/broken" {
+				http.Error(w, `{"detail":"rejected"}`, http.StatusBadRequest)
+				return
+			}
+			publishedNames = append(publishedNames, server.Name)
+			_, _ = fmt.Fprint(w, `{"message":"ok","id":"new-id"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	for file, content := range map[string]string{
+		"a_broken.json":   `{"name":"io.test/broken","description":"d","version":"1.0.0"}`,
+		"b_existing.json": `{"name":"io.test/existing","description":"d","version":"1.0.0"}`,
+		"c_github.json":   `{"name":"io.github.someone/server","description":"d","version":"1.0.0"}`,
+		"d_new.json":      `{"name":"io.test/new","description":"d","version":"1.0.0"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+	}
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	// Anonymous login is not exercised here; the stored-token check is satisfied by a temp auth config
+	t.Setenv("HOME", t.TempDir())
+	if err := client.saveAuthConfig(AuthConfig{Method: AuthMethodAnonymous, Token: "stored"}); err != nil {
+		t.Fatalf("Failed to save auth config: %v", err)
+	}
+
+	captureStdout(t, func() {
+		if err := client.ImportDirectory(dir, "", PublishOptions{}, bulkPublishOptions{}); err == nil {
+			t.Error("Expected the import to stop at the first failure")
+		}
+	})
+	if len(publishedNames) != 0 || !strings.Contains(logs.String(), "3 file(s) not attempted") {
+		t.Errorf("Expected the import to stop after the first file, published %v, logs:\n%s", publishedNames, logs.String())
+	}
+
+	logs.Reset()
+	output := captureStdout(t, func() {
+		if err := client.ImportDirectory(dir, "", PublishOptions{}, bulkPublishOptions{ContinueOnError: true, IfNotExists: true}); err == nil {
+			t.Error("Expected an error for the failed manifests")
+		}
+	})
+	if strings.Join(publishedNames, ",") != "io.test/new" {
+		t.Errorf("Expected only io.test/new to be published, got %v", publishedNames)
+	}
+	for _, want := range []string{"Published: 1\n", "Failed: 2\n", "Already present: 1\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the summary, got:\n%s", want, output)
+		}
+	}
+	if !strings.Contains(logs.String(), "io.github.*") {
+		t.Errorf("Expected the io.github.* manifest to fail without a token, got logs:\n%s", logs.String())
+	}
+
+	publishedNames = nil
+	output = captureStdout(t, func() {
+		bulk := bulkPublishOptions{ContinueOnError: true, IfNotExists: true, Summary: SummaryJSON}
+		if err := client.ImportDirectory(dir, "", PublishOptions{}, bulk); err == nil {
+			t.Error("Expected an error for the failed manifests")
+		}
+	})
+	var summary BulkPublishSummary
+	if err := json.Unmarshal([]byte(output), &summary); err != nil {
+		t.Fatalf("Expected stdout to be only the JSON summary: %v\n%s", err, output)
+	}
+	if summary.Total != 4 || len(summary.Succeeded) != 1 || len(summary.Skipped) != 1 || len(summary.Failed) != 2 {
+		t.Fatalf("Unexpected summary counts: %+v", summary)
+	}
+	if got := summary.Succeeded[0]; got.Name != "io.test/new" || got.ServerID != "new-id" {
+		t.Errorf("Expected the new server ID in the summary, got %+v", got)
+	}
+	if got := summary.Skipped[0]; got.Name != "io.test/existing" || got.Reason == "" {
+		t.Errorf("Expected the existing manifest to be skipped with a reason, got %+v", got)
+	}
+	if got := summary.Failed[0]; !strings.Contains(got.Reason, "HTTP 400") || !strings.Contains(got.Reason, "rejected") {
+		t.Errorf("Expected the failure reason to include the registry error, got %+v", got)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	client := NewMCPXClient("http://localhost")
+	var out bytes.Buffer
+	client.progressOut = &out
+
+	progress := client.newProgress("Publishing", 20)
+	for i := 0; i < 20; i++ {
+		progress.Add(1)
+	}
+	progress.Done()
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 10 || lines[0] != "Publishing: 2/20 (10%)" || lines[9] != "Publishing: 20/20 (100%)" {
+		t.Errorf("Expected a line every 10%% and no repeated final line, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	progress = client.newProgress("Exporting", 0)
+	progress.Add(3)
+	progress.Done()
+	if out.String() != "Exporting: 3 done\n" {
+		t.Errorf("Expected a final count for an unknown total, got %q", out.String())
+	}
+
+	out.Reset()
+	bar := &Progress{out: &out, label: "Fetching", total: 4, tty: true}
+	bar.Add(1)
+	bar.Clear()
+	bar.Done()
+	if !strings.Contains(out.String(), "\r\033[KFetching [#######-----------------------] 1/4 25%") || !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("Expected a redrawn bar on a terminal, got %q", out.String())
+	}
+
+	client.quiet = true
+	if progress := client.newProgress("Publishing", 5); progress != nil {
+		t.Error("Expected no progress report when quiet")
+	}
+	var quietProgress *Progress
+	quietProgress.Add(1)
+	quietProgress.Done()
+}
+
+func TestRetryAfterRateLimit(t *testing.T) {
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	var waits []time.Duration
+	client.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	resp, err := client.makeRequest("GET", "/v0/health", nil, "token")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != 200 || requests != 2 {
+		t.Errorf("Expected a 200 on the second request, got %d after %d requests", resp.StatusCode, requests)
+	}
+	if len(waits) != 1 || waits[0] != 2*time.Second {
+		t.Errorf("Expected one 2s wait, got %v", waits)
+	}
+	if !strings.Contains(logs.String(), "rate limited, waiting 2s") {
+		t.Errorf("Expected a rate limit message, got %q", logs.String())
+	}
+
+	alwaysLimited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer alwaysLimited.Close()
+	client.baseURL = alwaysLimited.URL
+	waits = nil
+	resp, err = client.makeRequest("GET", "/v0/health", nil, "token")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || len(waits) != maxRateLimitRetries {
+		t.Errorf("Expected the 429 to be returned after %d waits, got %d after %v", maxRateLimitRetries, resp.StatusCode, waits)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"2", 2 * time.Second, true},
+		{" 120 ", 2 * time.Minute, true},
+		{"Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSelectServerName(t *testing.T) {
+	var searches []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches = append(searches, r.URL.Query().Get("search"))
+		_, _ = fmt.Fprint(w, `{"servers":[`+
+			`{"server":{"name":"io.test/foo-a","version":"1.0.0"}},`+
+			`{"server":{"name":"io.test/foo-a","version":"2.0.0"}},`+
+			`{"server":{"name":"io.test/foo-b","version":"1.0.0"}},`+
+			`{"server":{"name":"io.test/bar","description":"mentions foo","version":"1.0.0"}}]}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.logger = NewLogger(io.Discard, LogFormatText, false)
+
+	names, err := client.serverNamesLike("foo")
+	if err != nil {
+		t.Fatalf("serverNamesLike failed: %v", err)
+	}
+	if strings.Join(names, ",") != "io.test/foo-a,io.test/foo-b" || searches[0] != "foo" {
+		t.Errorf("Expected distinct name matches from a registry search, got %v (searched %v)", names, searches)
+	}
+
+	if _, err := client.selectServerName("foo", false); err == nil || !strings.Contains(err.Error(), "io.test/foo-a, io.test/foo-b") {
+		t.Errorf("Expected an error listing the matches when not interactive, got %v", err)
+	}
+	if name, err := client.selectServerName("FOO-B", false); err != nil || name != "io.test/foo-b" {
+		t.Errorf("Expected the single match to be selected, got %q, %v", name, err)
+	}
+	if _, err := client.selectServerName("baz", false); err == nil || !strings.Contains(err.Error(), "no server name contains") {
+		t.Errorf("Expected a no-match error, got %v", err)
+	}
+}
+
+func TestVerboseRequestSummary(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, true)
+
+	resp, err := client.makeRequest("GET", "/v0/health", nil, "token")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if _, err := client.readResponseBody(resp); err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	_ = resp.Body.Close()
+	_ = resp.Body.Close()
+
+	if !strings.Contains(logs.String(), "[verbose] request summary method=GET path=/v0/health status=200 bytes=15 elapsed_ms=") {
+		t.Errorf("Expected a request summary, got:\n%s", logs.String())
+	}
+	if strings.Count(logs.String(), "request summary") != 1 {
+		t.Errorf("Expected the summary to be logged once, got:\n%s", logs.String())
+	}
+
+	logs.Reset()
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	resp, err = client.makeRequest("GET", "/v0/health", nil, "token")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if logs.Len() != 0 {
+		t.Errorf("Expected no summary without --verbose, got %q", logs.String())
+	}
+}
+
+func TestDefaultPageSize(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(defaultPageSizeEnvVar, "")
+
+	parse := func(args ...string) ListServersOptions {
+		t.Helper()
+		var opts ListServersOptions
+		fs := flag.NewFlagSet("servers", flag.ContinueOnError)
+		addListFlags(fs, &opts)
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if err := applyDefaultPageSize(fs, &opts); err != nil {
+			t.Fatalf("applyDefaultPageSize failed: %v", err)
+		}
+		return opts
+	}
+
+	if opts := parse(); opts.Limit != defaultPageSize {
+		t.Errorf("Expected the built-in page size, got %d", opts.Limit)
+	}
+
+	var settings Settings
+	if err := settings.Set("default-limit", "0"); err == nil {
+		t.Error("Expected an error for a non-positive default-limit")
+	}
+	if err := settings.Set("default-limit", "100"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := saveSettings(settings); err != nil {
+		t.Fatalf("saveSettings failed: %v", err)
+	}
+	if opts := parse(); opts.Limit != 100 {
+		t.Errorf("Expected the configured default-limit, got %d", opts.Limit)
+	}
+	if opts := parse("--page-size", "30"); opts.Limit != 30 {
+		t.Errorf("Expected an explicit --page-size equal to the built-in default to win, got %d", opts.Limit)
+	}
+
+	t.Setenv(defaultPageSizeEnvVar, "50")
+	if opts := parse(); opts.Limit != 50 {
+		t.Errorf("Expected %s to take precedence over the setting, got %d", defaultPageSizeEnvVar, opts.Limit)
+	}
+	t.Setenv(defaultPageSizeEnvVar, "lots")
+	if _, err := configuredPageSize(); err == nil || !strings.Contains(err.Error(), "positive integer") {
+		t.Errorf("Expected an invalid %s to be rejected, got %v", defaultPageSizeEnvVar, err)
+	}
+}
+
+func TestPrettyJSONError(t *testing.T) {
+	decode := func(data string) error {
+		var server ServerDetail
+		return prettyJSONError([]byte(data), json.Unmarshal([]byte(data), &server))
+	}
+
+	err := decode("{\n  \"name\": \"io.test/server\",\n  \"version\": \"1.0.0\",\n}\n")
+	want := "at line 4, column 1:\n  4 | }\n    | ^"
+	if err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("Expected a trailing comma to be located, got:\n%v", err)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the syntax error to stay wrapped, got %T", err)
+	}
+
+	err = decode("{\n\t\"name\": \"io.test/server\",\n\t\"version\": 1\n}")
+	want = "at line 3, column 13:\n  3 | \t\"version\": 1\n    | \t           ^"
+	if err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("Expected a type error to be located with tabs kept in the caret line, got:\n%v", err)
+	}
+
+	err = decode(`{"name":"io.test/server","description":"` + strings.Repeat("x", 100) + `","version":"1.0.0",}`)
+	if err == nil || !strings.Contains(err.Error(), "line 1, column 161:\n  1 | ...") || !strings.HasSuffix(err.Error(), "}\n    |"+strings.Repeat(" ", 64)+"^") {
+		t.Errorf("Expected a window around the error in a long line, got:\n%v", err)
+	}
+
+	if err := decode(`{"name":`); err == nil || !strings.Contains(err.Error(), "unexpected end of JSON input") {
+		t.Errorf("Expected the end of input error, got %v", err)
+	}
+	plain := errors.New("not a JSON error")
+	if got := prettyJSONError([]byte("{}"), plain); got != plain {
+		t.Errorf("Expected other errors to be returned unchanged, got %v", got)
+	}
+}
+
+func TestCheckStrictManifest(t *testing.T) {
+	templates, err := filepath.Glob("example-server-*.json")
+	if err != nil || len(templates) == 0 {
+		t.Fatalf("Expected example templates, got %v, %v", templates, err)
+	}
+	for _, template := range templates {
+		data, err := os.ReadFile(template)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", template, err)
+		}
+		if err := checkStrictManifest(data); err != nil {
+			t.Errorf("Expected %s to pass strict parsing, got %v", template, err)
+		}
+	}
+
+	typo := "{\n  \"name\": \"io.test/server\",\n  \"version\": \"1.0.0\",\n  \"repostiory\": {\"url\": \"https://github.com/test/server\"}\n}"
+	err = checkStrictManifest([]byte(typo))
+	if err == nil || !strings.Contains(err.Error(), `unknown field "repostiory" at line 4, column 3`) {
+		t.Errorf("Expected the misspelled key to be reported with its position, got %v", err)
+	}
+
+	wrapped := `{"server":{"name":"io.test/server","version":"1.0.0","packages":[{"registryType":"npm","identifier":"x","verison":"1"}]},"x-publisher":{"tool":"ci"}}`
+	if err := checkStrictManifest([]byte(wrapped)); err == nil || !strings.Contains(err.Error(), `unknown field "verison"`) {
+		t.Errorf("Expected unknown keys inside a PublishRequest to be reported, got %v", err)
+	}
+
+	extensions := `{"name":"io.test/server","version":"1.0.0","_meta":{"io.modelcontextprotocol.registry/publisher-provided":{"any":"thing"}}}`
+	if err := checkStrictManifest([]byte(extensions)); err != nil {
+		t.Errorf("Expected free-form _meta to be accepted, got %v", err)
+	}
+
+	mockServer := createMockServer()
+	defer mockServer.Close()
+	client := NewMCPXClient(mockServer.URL)
+	serverFile := createTempServerFile(t, []byte(typo))
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(serverFile)
+	captureStdout(t, func() {
+		if err := client.ValidateServerFile(serverFile, ValidateOptions{Strict: true}); err == nil {
+			t.Error("Expected validate --strict to fail")
+		}
+		if err := client.PublishServer(serverFile, "test-token", PublishOptions{Strict: true}); err == nil {
+			t.Error("Expected publish --strict to fail")
+		}
+		if err := client.UpdateServer("io.test/server", serverFile, "test-token", UpdateOptions{Strict: true}); err == nil {
+			t.Error("Expected update --strict to fail")
+		}
+	})
+}
+
+func TestRenameServer(t *testing.T) {
+	var published []byte
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.Contains(r.URL.Path, "/versions/"):
+			_, _ = fmt.Fprint(w, `{"id":"old-id","name":"io.modelcontextprotocol.anonymous/weather","description":"d","version":"1.2.0","status":"active","repository":{"url":"https://github.com/owner/weather","source":"github","id":"owner/weather"}}`)
+		case r.Method == "POST" && r.URL.Path == "/v0/publish":
+			published, _ = io.ReadAll(r.Body)
+			_, _ = fmt.Fprint(w, `{"message":"ok","id":"new-id"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+
+	if err := client.RenameServer("io.test/same", "io.test/same", "token", PublishOptions{}); err == nil {
+		t.Error("Expected an error when the new name equals the old one")
+	}
+
+	output := captureStdout(t, func() {
+		if err := client.RenameServer("io.modelcontextprotocol.anonymous/weather", "io.github.owner/weather", "token", PublishOptions{}); err != nil {
+			t.Errorf("Unexpected rename error: %v", err)
+		}
+	})
+	server, err := rawBodyServer(published)
+	if err != nil {
+		t.Fatalf("Failed to read the published body %s: %v", published, err)
+	}
+	if server.Name != "io.github.owner/weather" || server.Version != "1.2.0" || server.ID != "" {
+		t.Errorf("Expected the manifest to be republished under the new name without its id, got %+v", server)
+	}
+	if !strings.Contains(logs.String(), "published as a new entry") {
+		t.Errorf("Expected a warning that the old entry is kept, got:\n%s", logs.String())
+	}
+	if !strings.Contains(output, "mcpx-cli deprecate io.modelcontextprotocol.anonymous/weather") {
+		t.Errorf("Expected a hint to deprecate the old entry, got:\n%s", output)
+	}
+}
+
+func TestNamespaceToken(t *testing.T) {
+	for name, want := range map[string]string{
+		"io.github.user/server":                    NamespaceGitHub,
+		"io.modelcontextprotocol.anonymous/server": NamespaceAnonymous,
+		"io.modelcontextprotocol/server":           NamespaceCustom,
+		"com.example/server":                       NamespaceCustom,
+	} {
+		if got := classifyNamespace(name); got != want {
+			t.Errorf("classifyNamespace(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	anonymousLogins := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/auth/none" {
+			anonymousLogins++
+			_, _ = fmt.Fprintf(w, `{"registry_token":"anonymous-%d"}`, anonymousLogins)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := client.namespaceToken("io.github.user/server", ""); err == nil {
+		t.Error("Expected io.github.* names to require a token")
+	}
+	if token, err := client.namespaceToken("io.github.user/server", "explicit"); err != nil || token != "explicit" {
+		t.Errorf("Expected an explicit token to be used as is, got %q, %v", token, err)
+	}
+
+	// A stored GitHub login is kept; the anonymous token is only used for this request
+	if err := client.saveAuthConfig(AuthConfig{Method: AuthMethodGitHubOAuth, Token: "github"}); err != nil {
+		t.Fatalf("Failed to save auth config: %v", err)
+	}
+	logs.Reset()
+	token, err := client.namespaceToken("io.modelcontextprotocol.anonymous/server", "")
+	if err != nil || token != "anonymous-1" {
+		t.Errorf("Expected a one-off anonymous token, got %q, %v", token, err)
+	}
+	if !strings.Contains(logs.String(), "may be removed by the registry") {
+		t.Errorf("Expected a warning about anonymous servers, got:\n%s", logs.String())
+	}
+	if config, _ := client.loadAuthConfig(); config.Token != "github" {
+		t.Errorf("Expected the stored GitHub login to be kept, got %+v", config)
+	}
+
+	// Other namespaces use the stored login
+	if token, err := client.namespaceToken("com.example/server", ""); err != nil || token != "" || anonymousLogins != 1 {
+		t.Errorf("Expected the stored login to be used, got %q, %v after %d anonymous logins", token, err, anonymousLogins)
+	}
+
+	// Without a stored login, an anonymous login is stored and reused
+	if err := client.clearAuthConfig(); err != nil {
+		t.Fatalf("Failed to clear auth config: %v", err)
+	}
+	captureStdout(t, func() {
+		token, err = client.namespaceToken("io.modelcontextprotocol.anonymous/server", "")
+	})
+	if err != nil || token != "" {
+		t.Errorf("Expected the new anonymous login to be stored, got %q, %v", token, err)
+	}
+	if config, _ := client.loadAuthConfig(); config.Method != AuthMethodAnonymous || config.Token != "anonymous-2" {
+		t.Errorf("Expected an anonymous login to be stored, got %+v", config)
+	}
+	if token, err := client.namespaceToken("io.modelcontextprotocol.anonymous/other", ""); err != nil || token != "" || anonymousLogins != 2 {
+		t.Errorf("Expected the stored anonymous login to be reused, got %q, %v after %d anonymous logins", token, err, anonymousLogins)
+	}
+}
+
+func TestListServersCSV(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"servers":[
+			{"id":"id-1","name":"io.test/one","description":"Reads, writes \"files\"","version":"1.0.0","status":"active","repository":{"url":"https://github.com/test/one","source":"github"}},
+			{"id":"id-2","name":"io.test/two","description":"plain","version":"2.0.0","repository":{"url":"https://gitlab.com/test/two","source":"gitlab"}}
+		],"metadata":{"count":2}}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	output := captureStdout(t, func() {
+		if err := client.ListServers(ListServersOptions{CSV: true, Filter: "one"}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	expected := "id,name,version,status,repository_url,source,description\n" +
+		"id-1,io.test/one,1.0.0,active,https://github.com/test/one,github,\"Reads, writes \"\"files\"\"\"\n"
+	if output != expected {
+		t.Errorf("Expected CSV output:\n%s\ngot:\n%s", expected, output)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil || len(records) != 2 || records[1][6] != `Reads, writes "files"` {
+		t.Errorf("Expected the CSV to read back cleanly, got %q, %v", records, err)
+	}
+}
+
+func TestAuthURL(t *testing.T) {
+	var issuerPath, issuerAuth string
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuerPath, issuerAuth = r.URL.Path, r.Header.Get("Authorization")
+		_, _ = fmt.Fprint(w, `{"registry_token":"issued"}`)
+	}))
+	defer issuer.Close()
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected registry request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	defer registry.Close()
+
+	client := NewMCPXClient(registry.URL)
+	if got := client.authEndpoint("none"); got != "/v0/auth/none" {
+		t.Errorf("Expected the default auth endpoint on the registry, got %q", got)
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	if err := client.saveAuthConfig(AuthConfig{Method: AuthMethodGitHubOAuth, Token: "registry-secret"}); err != nil {
+		t.Fatalf("Failed to save auth config: %v", err)
+	}
+	client.authURL = issuer.URL + "/auth"
+	captureStdout(t, func() {
+		if err := client.loginAnonymous(); err != nil {
+			t.Errorf("Unexpected login error: %v", err)
+		}
+	})
+	if issuerPath != "/auth/none" {
+		t.Errorf("Expected the login to be sent to the issuer, got path %q", issuerPath)
+	}
+	if issuerAuth != "" {
+		t.Errorf("Expected the stored registry token not to be sent to the issuer, got %q", issuerAuth)
+	}
+	if config, _ := client.loadAuthConfig(); config.Token != "issued" {
+		t.Errorf("Expected the issued token to be stored, got %+v", config)
+	}
+}
+
+func TestSelectInstallHints(t *testing.T) {
+	packages := []Package{
+		{RegistryType: RegistryTypeNPM, Identifier: "@test/server", Version: "1.0.0"},
+		{RegistryType: RegistryTypeMCPB, Identifier: ""},
+		{RegistryType: RegistryTypeDocker, Identifier: "test/server", Version: "1.0.0"},
+	}
+
+	hints, err := selectInstallHints(packages, "")
+	if err != nil || len(hints) != 2 || hints[0].Command != "npx @test/server@1.0.0" || hints[1].Command != "docker run -i --rm test/server:1.0.0" {
+		t.Errorf("Expected a command for every package, got %+v, %v", hints, err)
+	}
+
+	hints, err = selectInstallHints(packages, "Docker")
+	if err != nil || len(hints) != 1 || hints[0].RegistryType != RegistryTypeDocker {
+		t.Errorf("Expected only the docker command, got %+v, %v", hints, err)
+	}
+
+	if _, err := selectInstallHints(packages, "pypi"); err == nil || !strings.Contains(err.Error(), "available: npm, docker") {
+		t.Errorf("Expected an error listing the available registries, got %v", err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"name":"io.test/server","version":"1.0.0","packages":[
+			{"registryType":"npm","identifier":"@test/server","version":"1.0.0"},
+			{"registryType":"docker","identifier":"test/server","version":"1.0.0"}]}`)
+	}))
+	defer mockServer.Close()
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output := captureStdout(t, func() {
+		if err := client.GetServerInstallCommand("io.test/server", ""); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if output != "npm: npx @test/server@1.0.0\ndocker: docker run -i --rm test/server:1.0.0\n" {
+		t.Errorf("Expected one labeled line per package, got %q", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := client.GetServerInstallCommand("io.test/server", "docker"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if output != "docker run -i --rm test/server:1.0.0\n" {
+		t.Errorf("Expected only the docker command, got %q", output)
+	}
+
+	output = captureStdout(t, func() {
+		if err := client.GetServer("io.test/server", GetServerOptions{}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Install Commands (heuristic):\n  npm: npx @test/server@1.0.0\n  docker: docker run -i --rm test/server:1.0.0\n") {
+		t.Errorf("Expected every install command in the server details, got:\n%s", output)
+	}
+}
+
+func TestApplyRetrySettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	parse := func(args ...string) (int, time.Duration, error) {
+		t.Helper()
+		var retries int
+		backoff := defaultRetryBackoff
+		fs := flag.NewFlagSet("global", flag.ContinueOnError)
+		fs.IntVar(&retries, "retries", 0, "")
+		fs.DurationVar(&backoff, "retry-backoff", defaultRetryBackoff, "")
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		err := applyRetrySettings(fs, &retries, &backoff)
+		return retries, backoff, err
+	}
+
+	if retries, backoff, err := parse(); err != nil || retries != 0 || backoff != defaultRetryBackoff {
+		t.Errorf("Expected the built-in defaults, got %d, %s, %v", retries, backoff, err)
+	}
+
+	var settings Settings
+	for _, invalid := range [][2]string{{"retries", "21"}, {"retries", "-1"}, {"retry-backoff", "1ms"}, {"retry-backoff", "soon"}} {
+		if err := settings.Set(invalid[0], invalid[1]); err == nil {
+			t.Errorf("Expected %s=%s to be rejected", invalid[0], invalid[1])
+		}
+	}
+	if err := settings.Set("retries", "5"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := settings.Set("retry-backoff", "2s"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := saveSettings(settings); err != nil {
+		t.Fatalf("saveSettings failed: %v", err)
+	}
+	if value, ok := settings.Get("retry-backoff"); !ok || value != "2s" {
+		t.Errorf("Expected retry-backoff=2s, got %q, %v", value, ok)
+	}
+
+	if retries, backoff, err := parse(); err != nil || retries != 5 || backoff != 2*time.Second {
+		t.Errorf("Expected the saved settings, got %d, %s, %v", retries, backoff, err)
+	}
+	if retries, backoff, err := parse("--retries", "0", "--retry-backoff", "100ms"); err != nil || retries != 0 || backoff != 100*time.Millisecond {
+		t.Errorf("Expected explicit flags to win, got %d, %s, %v", retries, backoff, err)
+	}
+	if _, _, err := parse("--retries", "50"); err == nil {
+		t.Error("Expected --retries above the maximum to be rejected")
+	}
+}
+
+func TestSelfUpdate(t *testing.T) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	binaryName := "mcpx-cli"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	newBinary := []byte("new binary")
+	for name, content := range map[string][]byte{"README.md": []byte("readme"), binaryName: newBinary} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("Failed to write tar entry: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close gzip: %v", err)
+	}
+
+	archiveName := releaseArchiveName("v1.2.0", runtime.GOOS, runtime.GOARCH)
+	checksum := fmt.Sprintf("%x", sha256.Sum256(archive.Bytes()))
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			_, _ = fmt.Fprintf(w, `{"tag_name":"v1.2.0","assets":[{"name":%q,"browser_download_url":"%s/archive"},{"name":"mcpx-cli_1.2.0_checksums.txt","browser_download_url":"%s/checksums"}]}`, archiveName, mockServer.URL, mockServer.URL)
+		case "/archive":
+			_, _ = w.Write(archive.Bytes())
+		case "/checksums":
+			_, _ = fmt.Fprintf(w, "%s  %s\n", checksum, archiveName)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	oldVersion := version
+	defer func() { version = oldVersion }()
+	client := NewMCPXClient(mockServer.URL)
+	executable := filepath.Join(t.TempDir(), binaryName)
+	if err := os.WriteFile(executable, []byte("old binary"), 0755); err != nil {
+		t.Fatalf("Failed to write executable: %v", err)
+	}
+
+	version = "v1.2.0"
+	output := captureStdout(t, func() {
+		if err := client.selfUpdate(mockServer.URL+"/latest", false, executable); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "up to date") {
+		t.Errorf("Expected the current release to be up to date, got:\n%s", output)
+	}
+
+	version = "1.1.0"
+	output = captureStdout(t, func() {
+		if err := client.selfUpdate(mockServer.URL+"/latest", true, executable); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(executable); !strings.Contains(output, "An update to v1.2.0 is available") || string(data) != "old binary" {
+		t.Errorf("Expected --check-only to report the update without installing it, got:\n%s", output)
+	}
+
+	captureStdout(t, func() {
+		if err := client.selfUpdate(mockServer.URL+"/latest", false, executable); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(executable); !bytes.Equal(data, newBinary) {
+		t.Errorf("Expected the executable to be replaced, got %q", data)
+	}
+
+	if err := verifyChecksum([]byte(checksum+"  "+archiveName+"\n"), archiveName, []byte("tampered")); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a tampered archive to be rejected, got %v", err)
+	}
+	if err := verifyChecksum([]byte(checksum+"  other.tar.gz\n"), archiveName, archive.Bytes()); err == nil {
+		t.Error("Expected a missing checksum entry to be rejected")
+	}
+	if newer, err := updateAvailable("dev", "v0.0.1"); err != nil || !newer {
+		t.Errorf("Expected a development build to be older than any release, got %v, %v", newer, err)
+	}
+}
+
+func TestUpdateNotifier(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(noUpdateCheckEnvVar, "")
+	oldVersion := version
+	defer func() { version = oldVersion }()
+	version = "v1.0.0"
+
+	var checks atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		_, _ = fmt.Fprint(w, `{"tag_name":"v1.1.0"}`)
+	}))
+	defer mockServer.Close()
+
+	now := time.Now()
+	var logs bytes.Buffer
+	logger := NewLogger(&logs, LogFormatText, false)
+	notifier := startUpdateCheck(mockServer.URL, now)
+	// Wait for the background check; a real run abandons it instead
+	latest := <-notifier.result
+	notifier.result = make(chan string, 1)
+	notifier.result <- latest
+	notifier.finish(logger)
+	if !strings.Contains(logs.String(), "A new version v1.1.0 is available (current: v1.0.0)") {
+		t.Errorf("Expected an update notice, got %q", logs.String())
+	}
+
+	// Within a day the stored result is reused without a request
+	logs.Reset()
+	notifier = startUpdateCheck(mockServer.URL, now.Add(time.Hour))
+	if notifier.result != nil {
+		t.Error("Expected no new check within the check interval")
+	}
+	notifier.finish(logger)
+	if checks.Load() != 1 || !strings.Contains(logs.String(), "v1.1.0") {
+		t.Errorf("Expected the cached version to be reported after %d check(s), got %q", checks.Load(), logs.String())
+	}
+
+	// A check still in flight is abandoned without a notice
+	notifier = &updateNotifier{result: make(chan string)}
+	logs.Reset()
+	notifier.finish(logger)
+	if logs.Len() != 0 {
+		t.Errorf("Expected no notice without a result, got %q", logs.String())
+	}
+
+	version = "1.1.0"
+	logs.Reset()
+	startUpdateCheck(mockServer.URL, now.Add(time.Hour)).finish(logger)
+	if logs.Len() != 0 {
+		t.Errorf("Expected no notice when up to date, got %q", logs.String())
+	}
+
+	if updateCheckEnabled(true, "servers") {
+		t.Error("Expected --no-update-check to disable the check")
+	}
+	t.Setenv(noUpdateCheckEnvVar, "1")
+	if updateCheckEnabled(false, "servers") {
+		t.Errorf("Expected %s to disable the check", noUpdateCheckEnvVar)
+	}
+	t.Setenv(noUpdateCheckEnvVar, "")
+	version = "dev"
+	if updateCheckEnabled(false, "servers") {
+		t.Error("Expected development builds not to check")
+	}
+}
+
+func TestUpdateNotifierSlowCheck(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	delay := make(chan time.Duration, 3)
+	var checks atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		time.Sleep(<-delay)
+		_, _ = fmt.Fprint(w, `{"tag_name":"v1.1.0"}`)
+	}))
+	defer mockServer.Close()
+	oldVersion := version
+	defer func() { version = oldVersion }()
+	version = "v1.0.0"
+	var logs bytes.Buffer
+	logger := NewLogger(&logs, LogFormatText, false)
+
+	// A release server slower than the command still delivers its answer within updateCheckWait
+	now := time.Now()
+	delay <- updateCheckWait / 4
+	startUpdateCheck(mockServer.URL, now).finish(logger)
+	state, err := loadState()
+	if err != nil {
+		t.Fatalf("loadState failed: %v", err)
+	}
+	if state.LastUpdateCheck != now.Unix() || state.LatestVersion != "v1.1.0" {
+		t.Errorf("Expected the check recorded at %d with v1.1.0, got %d and %q", now.Unix(), state.LastUpdateCheck, state.LatestVersion)
+	}
+	if !strings.Contains(logs.String(), "A new version v1.1.0 is available") {
+		t.Errorf("Expected an update notice, got %q", logs.String())
+	}
+
+	// One that misses the wait is abandoned unrecorded, so the next run checks again
+	later := now.Add(2 * updateCheckInterval)
+	delay <- 2 * updateCheckWait
+	startUpdateCheck(mockServer.URL, later).finish(logger)
+	if state, err := loadState(); err != nil || state.LastUpdateCheck != now.Unix() {
+		t.Errorf("Expected the abandoned check not to be recorded, got %d (%v)", state.LastUpdateCheck, err)
+	}
+	delay <- 0
+	if notifier := startUpdateCheck(mockServer.URL, later); notifier.result == nil {
+		t.Error("Expected a new check after an abandoned one")
+	}
+	if checks.Load() < 2 {
+		t.Errorf("Expected a request per check, got %d", checks.Load())
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# project settings\n\nMCPX_TEST_BASE_URL=https://registry.example.com\nexport MCPX_TEST_QUOTED=\"a b\"\nMCPX_TEST_EXISTING=from-file\r\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	t.Setenv("MCPX_TEST_EXISTING", "from-shell")
+	for _, key := range []string{"MCPX_TEST_BASE_URL", "MCPX_TEST_QUOTED"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	if err := loadEnvFile(path); err != nil {
+		t.Fatalf("loadEnvFile failed: %v", err)
+	}
+	if got := os.Getenv("MCPX_TEST_BASE_URL"); got != "https://registry.example.com" {
+		t.Errorf("Expected the value from the file, got %q", got)
+	}
+	if got := os.Getenv("MCPX_TEST_QUOTED"); got != "a b" {
+		t.Errorf("Expected the export prefix and quotes to be stripped, got %q", got)
+	}
+	if got := os.Getenv("MCPX_TEST_EXISTING"); got != "from-shell" {
+		t.Errorf("Expected the real environment to win, got %q", got)
+	}
+
+	if _, err := parseEnvFile([]byte("A=1\nnot a pair\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error naming line 2, got %v", err)
+	}
+	if err := loadEnvFile(filepath.Join(t.TempDir(), "missing.env")); err == nil {
+		t.Error("Expected a missing env file to be an error")
+	}
+
+	fs := flag.NewFlagSet("global", flag.ContinueOnError)
+	fs.String("base-url", "", "")
+	fs.Bool("verbose", false, "")
+	fs.String("env-file", "", "")
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--env-file", ".env", "servers"}, ".env"},
+		{[]string{"--verbose", "--base-url", "x", "--env-file=ci.env", "servers"}, "ci.env"},
+		{[]string{"--base-url", "--env-file", "servers"}, ""},
+		{[]string{"servers", "--env-file", ".env"}, ""},
+	}
+	for _, tc := range cases {
+		if got := envFileArg(fs, tc.args); got != tc.want {
+			t.Errorf("envFileArg(%v) = %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestListServerPackages(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "remote-only") {
+			_, _ = fmt.Fprint(w, `{"name":"io.test/remote-only","version":"1.0.0","remotes":[{"type":"sse","url":"https://example.com/sse"}]}`)
+			return
+		}
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"error":"not found"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"name":"io.test/server","version":"1.1.0","packages":[{"registryType":"npm","identifier":"@test/server","version":"1.1.0",`+
+			`"transport":{"type":"stdio"},"environmentVariables":[{"name":"API_KEY","description":"Key","isRequired":true}],`+
+			`"packageArguments":[{"type":"named","name":"--port","description":"Port"}]}]}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output, err := captureStdoutErr(t, func() error { return client.ListServerPackages("io.test/server", false) })
+	if err != nil {
+		t.Fatalf("ListServerPackages failed: %v", err)
+	}
+	for _, want := range []string{"=== Packages (Name: io.test/server, Version: 1.1.0) ===", "Identifier: @test/server", "Transport: stdio", "- API_KEY: Key (required)", "Package Arguments:", "- named:--port (optional): Port"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Description:") {
+		t.Errorf("Expected only the packages, got:\n%s", output)
+	}
+
+	output, err = captureStdoutErr(t, func() error { return client.ListServerPackages("io.test/server", true) })
+	if err != nil {
+		t.Fatalf("ListServerPackages --json failed: %v", err)
+	}
+	var packages []Package
+	if err := json.Unmarshal([]byte(output), &packages); err != nil {
+		t.Fatalf("Expected a JSON array, got %v:\n%s", err, output)
+	}
+	if len(packages) != 1 || packages[0].Identifier != "@test/server" || len(packages[0].EnvironmentVariables) != 1 {
+		t.Errorf("Unexpected packages: %+v", packages)
+	}
+
+	output, err = captureStdoutErr(t, func() error { return client.ListServerPackages("io.test/remote-only", true) })
+	if err != nil {
+		t.Fatalf("ListServerPackages --json failed: %v", err)
+	}
+	if strings.TrimSpace(output) != "[]" {
+		t.Errorf("Expected an empty array for a server without packages, got %q", output)
+	}
+
+	var apiErr *APIError
+	if err := client.ListServerPackages("io.test/missing", false); !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("Expected a 404 APIError, got %v", err)
+	}
+}
+
+func TestListServerRemotes(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "package-only") {
+			_, _ = fmt.Fprint(w, `{"name":"io.test/package-only","version":"1.0.0","packages":[{"registryType":"npm","identifier":"x","version":"1.0.0"}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"name":"io.test/server","version":"2.0.0","remotes":[{"type":"streamable-http","url":"https://example.com/mcp","headers":[`+
+			`{"name":"Content-Type","value":"application/json","description":"JSON requests"},`+
+			`{"name":"Authorization","value":"Bearer secret","isSecret":true,"isRequired":true}]}]}`)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output, err := captureStdoutErr(t, func() error { return client.ListServerRemotes("io.test/server", false) })
+	if err != nil {
+		t.Fatalf("ListServerRemotes failed: %v", err)
+	}
+	for _, want := range []string{"=== Remotes (Name: io.test/server, Version: 2.0.0) ===", "Transport: streamable-http", "URL: https://example.com/mcp", "- Content-Type: application/json (optional) JSON requests", "- Authorization (required)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Bearer secret") {
+		t.Errorf("Expected secret header values to be hidden, got:\n%s", output)
+	}
+
+	output, err = captureStdoutErr(t, func() error { return client.ListServerRemotes("io.test/server", true) })
+	if err != nil {
+		t.Fatalf("ListServerRemotes --json failed: %v", err)
+	}
+	var remotes []Remote
+	if err := json.Unmarshal([]byte(output), &remotes); err != nil {
+		t.Fatalf("Expected a JSON array, got %v:\n%s", err, output)
+	}
+	if len(remotes) != 1 || remotes[0].URL != "https://example.com/mcp" || len(remotes[0].Headers) != 2 {
+		t.Errorf("Unexpected remotes: %+v", remotes)
+	}
+
+	output, err = captureStdoutErr(t, func() error { return client.ListServerRemotes("io.test/package-only", false) })
+	if err != nil {
+		t.Fatalf("ListServerRemotes failed: %v", err)
+	}
+	if !strings.Contains(output, "No remotes") {
+		t.Errorf("Expected a note for a server without remotes, got:\n%s", output)
+	}
+}
+
+func TestNoAuth(t *testing.T) {
+	var authHeaders []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = fmt.Fprint(w, `{"error":"forbidden"}`)
+	}))
+	defer mockServer.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, true)
+	if err := client.saveAuthConfig(AuthConfig{Method: AuthMethodAnonymous, Token: "stored"}); err != nil {
+		t.Fatalf("Failed to save auth config: %v", err)
+	}
+
+	for _, token := range []string{"", "explicit"} {
+		resp, err := client.makeRequest("GET", "/v0/servers", nil, token)
+		if err != nil {
+			t.Fatalf("makeRequest failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+	if authHeaders[0] != "Bearer stored" || authHeaders[1] != "Bearer explicit" {
+		t.Fatalf("Expected the stored and explicit tokens without --no-auth, got %q", authHeaders)
+	}
+
+	client.noAuth = true
+	authHeaders = nil
+	for _, token := range []string{"", "explicit"} {
+		resp, err := client.makeRequest("GET", "/v0/servers", nil, token)
+		if err != nil {
+			t.Fatalf("makeRequest failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+	if authHeaders[0] != "" || authHeaders[1] != "" {
+		t.Errorf("Expected no Authorization header with --no-auth, got %q", authHeaders)
+	}
+	if !strings.Contains(logs.String(), "not sending the given token (--no-auth)") {
+		t.Errorf("Expected a verbose note about the dropped token, got:\n%s", logs.String())
+	}
+
+	// Namespaces that normally trigger a login or require a token are sent as they are
+	if token, err := client.namespaceToken("io.github.owner/server", ""); err != nil || token != "" {
+		t.Errorf("Expected no token and no error with --no-auth, got %q, %v", token, err)
+	}
+}
+
+func TestListServersJSONMetadata(t *testing.T) {
+	bodies := map[string]string{
+		"wrapper": `{"servers":[{"server":{"name":"io.test/a","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"id-a"}}}],` +
+			`"metadata":{"nextCursor":"next-page","count":1,"total":42}}`,
+		"legacy":       `{"servers":[{"name":"io.test/a","version":"1.0.0"}],"metadata":{"nextCursor":"next-page","count":1,"total":42}}`,
+		"snake-cursor": `{"servers":[{"server":{"name":"io.test/a","version":"1.0.0"}}],"metadata":{"next_cursor":"next-page","count":1,"total":42}}`,
+	}
+	for format, body := range bodies {
+		t.Run(format, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, body)
+			}))
+			defer mockServer.Close()
+
+			t.Setenv("HOME", t.TempDir())
+			client := NewMCPXClient(mockServer.URL)
+			client.cacheDir = ""
+			output, err := captureStdoutErr(t, func() error { return client.ListServers(ListServersOptions{Limit: 30, JSON: true}) })
+			if err != nil {
+				t.Fatalf("ListServers failed: %v", err)
+			}
+
+			var response LegacyServersResponse
+			if err := json.Unmarshal([]byte(output), &response); err != nil {
+				t.Fatalf("Invalid JSON output: %v\nOutput: %s", err, output)
+			}
+			if len(response.Servers) != 1 || response.Servers[0].Name != "io.test/a" {
+				t.Errorf("Unexpected servers: %+v", response.Servers)
+			}
+			want := Metadata{NextCursor: "next-page", Count: 1, Total: 42}
+			if response.Metadata != want {
+				t.Errorf("Expected metadata %+v to survive the conversion, got %+v", want, response.Metadata)
+			}
+			if !strings.Contains(output, `"nextCursor": "next-page"`) {
+				t.Errorf("Expected the cursor under nextCursor, got:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestTeeOutput(t *testing.T) {
+	for _, spec := range []string{"out.json", "json:", "xml:out.xml", "yaml:out.yaml"} {
+		if _, err := parseOutputSink(spec); err == nil {
+			t.Errorf("Expected --tee %q to be rejected", spec)
+		}
+	}
+
+	mockServer := createPaginatedMockServer(t, [][]string{{"alpha", "beta", "gamma"}})
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	sink, err := parseOutputSink("json:" + filepath.Join(dir, "artifacts", "servers.json"))
+	if err != nil {
+		t.Fatalf("parseOutputSink failed: %v", err)
+	}
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	client.sinks = []outputSink{sink}
+	output, err := captureStdoutErr(t, func() error { return client.ListServers(ListServersOptions{Limit: 30, Filter: "gamma"}) })
+	if err != nil {
+		t.Fatalf("ListServers failed: %v", err)
+	}
+	if !strings.Contains(output, "=== List Servers ===") {
+		t.Errorf("Expected the text listing on stdout, got:\n%s", output)
+	}
+	if !client.sinksWritten {
+		t.Error("Expected the listing to be handed to the sinks")
+	}
+
+	data, err := os.ReadFile(sink.Path)
+	if err != nil {
+		t.Fatalf("Expected the --tee file to be written: %v", err)
+	}
+	var response LegacyServersResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Expected the --tee file to hold JSON: %v\n%s", err, data)
+	}
+	if len(response.Servers) != 1 || response.Servers[0].Name != "gamma" || response.Metadata.Count != 1 {
+		t.Errorf("Expected the filtered listing in the --tee file, got %+v", response)
+	}
+}
+
+func TestFirstServer(t *testing.T) {
+	var limits []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v0/servers" && r.URL.Query().Get("cursor") == "":
+			limits = append(limits, r.URL.Query().Get("limit"))
+			_, _ = fmt.Fprint(w, `{"servers":[{"server":{"name":"io.test/alpha","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"id-alpha"}}}],"metadata":{"nextCursor":"page-2"}}`)
+		case r.URL.Path == "/v0/servers":
+			limits = append(limits, r.URL.Query().Get("limit"))
+			_, _ = fmt.Fprint(w, `{"servers":[{"server":{"name":"io.test/filesystem","version":"2.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"id-fs"}}}]}`)
+		case r.URL.Path == "/v0/servers/id-alpha":
+			_, _ = fmt.Fprint(w, `{"name":"io.test/alpha","version":"1.0.0","packages":[{"registryType":"npm","identifier":"alpha","version":"1.0.0"}]}`)
+		case r.URL.Path == "/v0/servers/id-fs":
+			_, _ = fmt.Fprint(w, `{"name":"io.test/filesystem","version":"2.0.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+
+	output, err := captureStdoutErr(t, func() error { return client.FirstServer(ListServersOptions{Limit: 30, JSON: true}) })
+	if err != nil {
+		t.Fatalf("FirstServer failed: %v", err)
+	}
+	var detail ServerDetail
+	if err := json.Unmarshal([]byte(output), &detail); err != nil {
+		t.Fatalf("Expected a single JSON object, got %v:\n%s", err, output)
+	}
+	if detail.Name != "io.test/alpha" || len(detail.Packages) != 1 || detail.ID != "id-alpha" {
+		t.Errorf("Unexpected first server: %+v", detail)
+	}
+	if len(limits) != 1 || limits[0] != "1" {
+		t.Errorf("Expected a single request for one server, got limits %v", limits)
+	}
+
+	limits = nil
+	output, err = captureStdoutErr(t, func() error { return client.FirstServer(ListServersOptions{Limit: 30, Filter: "FILESYSTEM"}) })
+	if err != nil {
+		t.Fatalf("FirstServer --filter failed: %v", err)
+	}
+	if !strings.Contains(output, "=== First Server ===") || !strings.Contains(output, "Name: io.test/filesystem") {
+		t.Errorf("Expected the first match on the second page, got:\n%s", output)
+	}
+	if len(limits) != 2 || limits[0] != "30" {
+		t.Errorf("Expected filtered pages of the configured size, got limits %v", limits)
+	}
+
+	if err := client.FirstServer(ListServersOptions{Limit: 30, Filter: "nothing"}); err == nil || !strings.Contains(err.Error(), "no server matches") {
+		t.Errorf("Expected a no-match error, got %v", err)
+	}
+}
+
+func TestUpdatedRangeFilter(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Time{
+		"2025-06-01T00:00:00Z": time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		"2025-06-01":           time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		"36h":                  now.Add(-36 * time.Hour),
+		"7d":                   now.AddDate(0, 0, -7),
+		"2w":                   now.AddDate(0, 0, -14),
+	} {
+		if got, err := parseTimeBound(value, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseTimeBound(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := parseTimeBound("last week", now); err == nil {
+		t.Error("Expected an error for an unparseable time")
+	}
+
+	server := func(name, publishedAt, updatedAt string) Server {
+		return Server{Name: name, Meta: &ServerMeta{Official: &RegistryExtensions{PublishedAt: publishedAt, UpdatedAt: updatedAt}}}
+	}
+	servers := []Server{
+		server("io.test/old", "2025-01-01T00:00:00Z", ""),
+		server("io.test/republished", "2025-01-01T00:00:00Z", "2025-06-10T00:00:00Z"),
+		server("io.test/new", "2025-06-12T00:00:00Z", ""),
+		{Name: "io.test/no-meta"},
+	}
+	names := func(servers []Server) string {
+		var result []string
+		for _, s := range servers {
+			result = append(result, s.Name)
+		}
+		return strings.Join(result, ",")
+	}
+
+	after, _ := parseTimeBound("7d", now)
+	if got := names(filterServers(servers, ListServersOptions{UpdatedAfter: after})); got != "io.test/republished,io.test/new" {
+		t.Errorf("--updated-after 7d kept %s", got)
+	}
+	before, _ := parseTimeBound("2025-06-11", now)
+	if got := names(filterServers(servers, ListServersOptions{UpdatedAfter: after, UpdatedBefore: before, Filter: "test"})); got != "io.test/republished" {
+		t.Errorf("Expected the range and --filter to combine with AND, kept %s", got)
+	}
+}
+
+func TestCountServers(t *testing.T) {
+	t.Run("metadata total", func(t *testing.T) {
+		var requests []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RawQuery)
+			_, _ = fmt.Fprint(w, `{"servers":[{"server":{"name":"io.test/a","version":"1.0.0"}}],"metadata":{"nextCursor":"next","total":1234}}`)
+		}))
+		defer mockServer.Close()
+
+		client := NewMCPXClient(mockServer.URL)
+		client.cacheDir = ""
+		output, err := captureStdoutErr(t, func() error { return client.CountServers(30, false) })
+		if err != nil {
+			t.Fatalf("CountServers failed: %v", err)
+		}
+		if output != "Total servers: 1234\n" {
+			t.Errorf("Unexpected output: %q", output)
+		}
+		if len(requests) != 1 || requests[0] != "limit=1" {
+			t.Errorf("Expected a single one-server request, got %v", requests)
+		}
+	})
+
+	t.Run("counted listing", func(t *testing.T) {
+		mockServer := createPaginatedMockServer(t, [][]string{{"a", "b"}, {"c"}})
+		defer mockServer.Close()
+
+		client := NewMCPXClient(mockServer.URL)
+		client.cacheDir = ""
+		output, err := captureStdoutErr(t, func() error { return client.CountServers(30, true) })
+		if err != nil {
+			t.Fatalf("CountServers failed: %v", err)
+		}
+		var count ServerCount
+		if err := json.Unmarshal([]byte(output), &count); err != nil {
+			t.Fatalf("Expected a JSON object, got %v:\n%s", err, output)
+		}
+		if count.Total != 3 || count.Source != "listing" {
+			t.Errorf("Expected every page to be counted, got %+v", count)
+		}
+	})
+}
+
+func TestGetServerLatest(t *testing.T) {
+	var requested []string
+	versions := `{"servers":[` +
+		`{"server":{"name":"io.test/server","version":"1.0.0"}},` +
+		`{"server":{"name":"io.test/server","version":"1.2.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"isLatest":true}}},` +
+		`{"server":{"name":"io.test/server","version":"2.0.0-beta"}}]}`
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/versions") {
+			_, _ = fmt.Fprint(w, versions)
+			return
+		}
+		version := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		_, _ = fmt.Fprintf(w, `{"name":"io.test/server","version":%q}`, version)
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+
+	output, err := captureStdoutErr(t, func() error { return client.GetServer("io.test/server", GetServerOptions{Latest: true}) })
+	if err != nil {
+		t.Fatalf("GetServer --latest failed: %v", err)
+	}
+	if !strings.Contains(output, "Resolved latest version: 1.2.0") || !strings.Contains(output, "Version: 1.2.0") {
+		t.Errorf("Expected the version marked latest, got:\n%s", output)
+	}
+	if last := requested[len(requested)-1]; !strings.HasSuffix(last, "/versions/1.2.0") {
+		t.Errorf("Expected the resolved version to be fetched explicitly, got %s", last)
+	}
+
+	// Without an isLatest marker the highest version wins
+	versions = `{"servers":[{"server":{"name":"io.test/server","version":"1.0.0"}},{"server":{"name":"io.test/server","version":"1.10.0"}},{"server":{"name":"io.test/server","version":"1.9.0"}}]}`
+	output, err = captureStdoutErr(t, func() error { return client.GetServer("io.test/server", GetServerOptions{Latest: true, JSON: true}) })
+	if err != nil {
+		t.Fatalf("GetServer --latest --json failed: %v", err)
+	}
+	var detail ServerDetail
+	if err := json.Unmarshal([]byte(output), &detail); err != nil || detail.Version != "1.10.0" {
+		t.Errorf("Expected version 1.10.0 as clean JSON, got %v:\n%s", err, output)
+	}
+	if !strings.Contains(logs.String(), "no version of io.test/server is marked latest") || !strings.Contains(logs.String(), "Resolved latest version: 1.10.0") {
+		t.Errorf("Expected the fallback warning and resolved version on stderr, got:\n%s", logs.String())
+	}
+}
+
+func TestServerIDMismatch(t *testing.T) {
+	body := `{"servers":[` +
+		`{"server":{"id":"embedded-a","name":"io.test/a","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"meta-a"}}},` +
+		`{"id":"embedded-b","name":"io.test/b","version":"1.0.0","_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"meta-b"}}},` +
+		`{"server":{"id":"same","name":"io.test/c","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"same"}}}]}`
+	servers, _, err := parseServersResponse([]byte(body))
+	if err != nil {
+		t.Fatalf("parseServersResponse failed: %v", err)
+	}
+	for i, want := range []string{"meta-a", "meta-b", "same"} {
+		if got := servers[i].GetServerID(); got != want {
+			t.Errorf("servers[%d]: expected the metadata serverId %s to win, got %s", i, want, got)
+		}
+	}
+	if servers[0].embeddedID != "embedded-a" || servers[1].embeddedID != "embedded-b" || servers[2].embeddedID != "" {
+		t.Errorf("Expected only differing ids to be recorded, got %q, %q, %q", servers[0].embeddedID, servers[1].embeddedID, servers[2].embeddedID)
+	}
+
+	detail, err := parseServerDetail([]byte(`{"server":{"id":"embedded","name":"io.test/a","version":"1.0.0"},"_meta":{"io.modelcontextprotocol.registry/official":{"serverId":"meta"}}}`))
+	if err != nil || detail.ID != "meta" || detail.embeddedID != "embedded" {
+		t.Errorf("Expected the detail mismatch to be recorded, got %+v, %v", detail, err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, body)
+	}))
+	defer mockServer.Close()
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	var logs bytes.Buffer
+	client.logger = NewLogger(&logs, LogFormatText, false)
+	if _, _, _, _, err := client.fetchServersPage("/v0/servers", "", 0); err != nil {
+		t.Fatalf("fetchServersPage failed: %v", err)
+	}
+	if strings.Contains(logs.String(), "mismatch") {
+		t.Errorf("Expected no mismatch report without --verbose, got:\n%s", logs.String())
+	}
+
+	client.logger = NewLogger(&logs, LogFormatText, true)
+	if _, _, _, _, err := client.fetchServersPage("/v0/servers", "", 0); err != nil {
+		t.Fatalf("fetchServersPage failed: %v", err)
+	}
+	if strings.Count(logs.String(), "server ID mismatch") != 2 || !strings.Contains(logs.String(), "serverId=meta-a id=embedded-a") {
+		t.Errorf("Expected two mismatch reports in verbose mode, got:\n%s", logs.String())
+	}
+}
+
+func TestPublishServerInteractiveRequiresTTY(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer r.Close()
+	_ = w.Close()
+	oldStdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = oldStdin }()
+
+	client := NewMCPXClient("http://localhost:1")
+	output := captureStdout(t, func() {
+		if err := client.PublishServerInteractive("", PublishOptions{}); !errors.Is(err, errInteractiveNeedsTTY) {
+			t.Errorf("Expected errInteractiveNeedsTTY for piped stdin, got %v", err)
+		}
+	})
+	if strings.Contains(output, "Interactive") {
+		t.Errorf("Expected no prompts before the TTY check, got:\n%s", output)
+	}
+}
+
+func TestPublishServerInteractiveAcceptDefaults(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer r.Close()
+	_ = w.Close()
+	oldStdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = oldStdin }()
+	t.Setenv("HOME", t.TempDir())
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd failed: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Chdir failed: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldDir) }()
+
+	var published PublishRequest
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v0/publish" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&published); err != nil {
+			t.Errorf("Failed to decode publish body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"message":"published","id":"new-id"}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewMCPXClient(mockServer.URL)
+	client.cacheDir = ""
+	if err := client.saveAuthConfig(AuthConfig{Method: AuthMethodAnonymous, Token: "anon", ExpiresAt: time.Now().Add(time.Hour).Unix()}); err != nil {
+		t.Fatalf("Failed to save auth config: %v", err)
+	}
+
+	opts := PublishOptions{
+		AcceptDefaults: true,
+		Overrides:      []fieldOverride{{Field: "version", Value: "2.0.0"}},
+	}
+	output := captureStdout(t, func() {
+		if err := client.PublishServerInteractive("", opts); err != nil {
+			t.Errorf("PublishServerInteractive failed: %v", err)
+		}
+	})
+	if promptAcceptDefaults {
+		t.Error("Expected the prompt mode to be reset after publishing")
+	}
+
+	var template ServerDetail
+	if err := json.Unmarshal(exampleServerNPMJSON, &template); err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+	if published.Server.Name != template.Name || published.Server.Description != template.Description {
+		t.Errorf("Expected the node template defaults, got name %q description %q", published.Server.Name, published.Server.Description)
+	}
+	if published.Server.Version != "2.0.0" {
+		t.Errorf("Expected --set-version to apply to the generated manifest, got %q", published.Server.Version)
+	}
+	if len(published.Server.Packages) != len(template.Packages) || published.Server.Packages[0].Identifier != template.Packages[0].Identifier {
+		t.Errorf("Expected the template packages unchanged, got %+v", published.Server.Packages)
+	}
+	for _, want := range []string{"Server name [" + template.Name + "]: " + template.Name, "Using default: node", "Version: 2.0.0", "✅ Success: published"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	saved, err := os.ReadFile("server-config.json")
+	if err != nil || !strings.Contains(string(saved), `"version": "2.0.0"`) {
+		t.Errorf("Expected the configuration saved with overrides applied, got %s (%v)", saved, err)
+	}
+
+	t.Run("preview full", func(t *testing.T) {
+		opts := PublishOptions{AcceptDefaults: true, PreviewFull: true}
+		output, err := captureStdoutErr(t, func() error { return client.PublishServerInteractive("", opts) })
+		if err != nil {
+			t.Fatalf("PublishServerInteractive failed: %v", err)
+		}
+		manifest := strings.Index(output, "=== Full Manifest ===")
+		confirm := strings.Index(output, "Proceed with publishing?")
+		if manifest < 0 || confirm < 0 || manifest > confirm {
+			t.Fatalf("Expected the full manifest before the confirmation, got:\n%s", output)
+		}
+		if full := output[manifest:confirm]; !strings.Contains(full, `"packages"`) || !strings.Contains(full, template.Packages[0].Identifier) {
+			t.Errorf("Expected the complete manifest with its packages, got:\n%s", full)
+		}
+	})
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "server.json")
+	if err := writeFile(path, []byte("first"), false); err != nil {
+		t.Fatalf("writeFile failed: %v", err)
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		t.Fatalf("Expected the parent directories to be created, got %v", err)
+	}
+
+	err := writeFile(path, []byte("second"), false)
+	if err == nil || !strings.Contains(err.Error(), "already exists; pass --force") {
+		t.Errorf("Expected an existing file to be refused without force, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first" {
+		t.Errorf("Expected the existing file to be unchanged, got %q", data)
+	}
+
+	if err := writeFile(path, []byte("2nd"), true); err != nil {
+		t.Fatalf("writeFile with force failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "2nd" {
+		t.Errorf("Expected force to replace the whole file, got %q", data)
+	}
+}